@@ -1,19 +1,39 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
+const (
+	defaultOutboxMaxAttempts     = 10
+	defaultOutboxDeliveryTimeout = 10 * time.Second
+	defaultOutboxBackoffBase     = time.Second
+	defaultOutboxBackoffMax      = time.Hour
+	defaultOutboxRetention       = 7 * 24 * time.Hour
+	defaultOutboxPublisher       = "http"
+	defaultOutboxHTTPBody        = "envelope"
+	maxOutboxSigningKeys         = 2
+	defaultCloudEventsSource     = "/library"
+	defaultInboxMaxAttempts      = 10
+	defaultInboxBackoffBase      = time.Second
+	defaultInboxBackoffMax       = time.Hour
+)
+
 type (
 	Config struct {
 		GRPC
 		PG
 		Outbox
+		Inbox
+		Fines
 		Observability
+		Admin
 	}
 
 	GRPC struct {
@@ -39,6 +59,74 @@ type (
 		InProgressTTLMS time.Duration `env:"OUTBOX_IN_PROGRESS_TTL_MS"`
 		AuthorSendURL   string        `env:"OUTBOX_AUTHOR_SEND_URL"`
 		BookSendURL     string        `env:"OUTBOX_BOOK_SEND_URL"`
+		// A delivery is cancelled after DeliveryTimeoutMS, or earlier if
+		// its batch could be claimed again after InProgressTTLMS by then.
+		DeliveryTimeoutMS time.Duration `env:"OUTBOX_DELIVERY_TIMEOUT_MS"`
+		// A failed message is retried after BackoffBaseMS doubled on every
+		// attempt, up to BackoffMaxMS, and is dead-lettered after
+		// MaxAttempts deliveries.
+		MaxAttempts   int           `env:"OUTBOX_MAX_ATTEMPTS"`
+		BackoffBaseMS time.Duration `env:"OUTBOX_BACKOFF_BASE_MS"`
+		BackoffMaxMS  time.Duration `env:"OUTBOX_BACKOFF_MAX_MS"`
+		// Delivered messages are deleted RetentionHours after delivery;
+		// zero keeps them forever.
+		RetentionHours time.Duration `env:"OUTBOX_RETENTION_HOURS"`
+		// Ordered delivers the messages of every aggregate one by one, in
+		// the order they were committed.
+		Ordered bool `env:"OUTBOX_ORDERED"`
+		// Publisher delivers the messages of every kind not listed in
+		// Publishers, which maps kind names to publishers: http, kafka,
+		// nats or file.
+		Publisher  string            `env:"OUTBOX_PUBLISHER"`
+		Publishers map[string]string `env:"OUTBOX_PUBLISHERS"`
+		// HTTPBody is what webhooks get: the entity ID (key), the whole
+		// message (payload) or the message in a versioned envelope
+		// (envelope). Webhooks are signed with every one of
+		// HTTPSigningKeys: the current key and, while rotating, the next.
+		// Unsigned webhooks are sent only to legacy receivers, with
+		// HTTPAllowUnsigned.
+		HTTPBody          string   `env:"OUTBOX_HTTP_BODY"`
+		HTTPSigningKeys   []string `env:"OUTBOX_HTTP_SIGNING_KEYS"`
+		HTTPAllowUnsigned bool     `env:"OUTBOX_HTTP_ALLOW_UNSIGNED"`
+		KafkaBrokers      []string `env:"OUTBOX_KAFKA_BROKERS"`
+		KafkaTopic        string   `env:"OUTBOX_KAFKA_TOPIC"`
+		NATSURL           string   `env:"OUTBOX_NATS_URL"`
+		NATSSubject       string   `env:"OUTBOX_NATS_SUBJECT"`
+		FilePath          string   `env:"OUTBOX_FILE_PATH"`
+		// CloudEvents maps publishers to the CloudEvents content mode of
+		// their messages: structured or binary. Publishers not listed send
+		// messages in their own format.
+		CloudEvents       map[string]string `env:"OUTBOX_CLOUDEVENTS"`
+		CloudEventsSource string            `env:"OUTBOX_CLOUDEVENTS_SOURCE"`
+	}
+
+	// Inbox applies the events other services send to the library. A
+	// failed event is retried like an outbox message. Events are accepted
+	// only with Token in the x-inbox-token header.
+	Inbox struct {
+		Enabled         bool          `env:"INBOX_ENABLED"`
+		Workers         int           `env:"INBOX_WORKERS"`
+		BatchSize       int           `env:"INBOX_BATCH_SIZE"`
+		WaitTimeMS      time.Duration `env:"INBOX_WAIT_TIME_MS"`
+		InProgressTTLMS time.Duration `env:"INBOX_IN_PROGRESS_TTL_MS"`
+		MaxAttempts     int           `env:"INBOX_MAX_ATTEMPTS"`
+		BackoffBaseMS   time.Duration `env:"INBOX_BACKOFF_BASE_MS"`
+		BackoffMaxMS    time.Duration `env:"INBOX_BACKOFF_MAX_MS"`
+		Token           string        `env:"INBOX_TOKEN"`
+	}
+
+	// Fines are accrued in minor currency units for every day a loan is
+	// overdue past the grace period, up to MaxPerLoan when it's set.
+	Fines struct {
+		Enabled    bool          `env:"FINES_ENABLED"`
+		IntervalMS time.Duration `env:"FINES_INTERVAL_MS"`
+		DailyRate  int64         `env:"FINES_DAILY_RATE"`
+		MaxPerLoan int64         `env:"FINES_MAX_PER_LOAN"`
+		GraceDays  int           `env:"FINES_GRACE_DAYS"`
+	}
+
+	Admin struct {
+		Token string `env:"ADMIN_TOKEN"`
 	}
 
 	Observability struct {
@@ -58,6 +146,8 @@ func New() (*Config, error) {
 	cfg.GRPC.Port = os.Getenv("GRPC_PORT")
 	cfg.GRPC.GatewayPort = os.Getenv("GRPC_GATEWAY_PORT")
 
+	cfg.Admin.Token = os.Getenv("ADMIN_TOKEN")
+
 	cfg.PG.Host = os.Getenv("POSTGRES_HOST")
 	cfg.PG.Port = os.Getenv("POSTGRES_PORT")
 	cfg.PG.DB = os.Getenv("POSTGRES_DB")
@@ -101,28 +191,320 @@ func New() (*Config, error) {
 			return nil, err
 		}
 
+		cfg.Outbox.DeliveryTimeoutMS = defaultOutboxDeliveryTimeout
+		if deliveryTimeout := os.Getenv("OUTBOX_DELIVERY_TIMEOUT_MS"); deliveryTimeout != "" {
+			cfg.Outbox.DeliveryTimeoutMS, err = parseTime(deliveryTimeout)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if cfg.Outbox.DeliveryTimeoutMS <= 0 {
+			return nil, fmt.Errorf("Outbox delivery timeout must be positive: DeliveryTimeout=%s",
+				cfg.Outbox.DeliveryTimeoutMS)
+		}
+
 		cfg.Outbox.BookSendURL = os.Getenv("OUTBOX_BOOK_SEND_URL")
 		cfg.Outbox.AuthorSendURL = os.Getenv("OUTBOX_AUTHOR_SEND_URL")
 
-		if cfg.Outbox.BookSendURL == "" || cfg.Outbox.AuthorSendURL == "" {
-			return nil, fmt.Errorf("Outbox URLs must be configured: BookSendURL='%s', AuthorSendURL='%s'",
-				cfg.Outbox.BookSendURL, cfg.Outbox.AuthorSendURL)
+		cfg.Outbox.Publisher = defaultOutboxPublisher
+		if publisher := os.Getenv("OUTBOX_PUBLISHER"); publisher != "" {
+			cfg.Outbox.Publisher = publisher
+		}
+
+		cfg.Outbox.Publishers, err = parseMap(os.Getenv("OUTBOX_PUBLISHERS"))
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Outbox.HTTPBody = defaultOutboxHTTPBody
+		if httpBody := os.Getenv("OUTBOX_HTTP_BODY"); httpBody != "" {
+			cfg.Outbox.HTTPBody = httpBody
+		}
+
+		cfg.Outbox.HTTPSigningKeys = parseList(os.Getenv("OUTBOX_HTTP_SIGNING_KEYS"))
+
+		if allowUnsigned := os.Getenv("OUTBOX_HTTP_ALLOW_UNSIGNED"); allowUnsigned != "" {
+			cfg.Outbox.HTTPAllowUnsigned, err = strconv.ParseBool(allowUnsigned)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cfg.Outbox.KafkaBrokers = parseList(os.Getenv("OUTBOX_KAFKA_BROKERS"))
+		cfg.Outbox.KafkaTopic = os.Getenv("OUTBOX_KAFKA_TOPIC")
+		cfg.Outbox.NATSURL = os.Getenv("OUTBOX_NATS_URL")
+		cfg.Outbox.NATSSubject = os.Getenv("OUTBOX_NATS_SUBJECT")
+		cfg.Outbox.FilePath = os.Getenv("OUTBOX_FILE_PATH")
+
+		cfg.Outbox.CloudEvents, err = parseMap(os.Getenv("OUTBOX_CLOUDEVENTS"))
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Outbox.CloudEventsSource = defaultCloudEventsSource
+		if source := os.Getenv("OUTBOX_CLOUDEVENTS_SOURCE"); source != "" {
+			cfg.Outbox.CloudEventsSource = source
+		}
+
+		if err = validatePublishers(&cfg.Outbox); err != nil {
+			return nil, err
+		}
+
+		cfg.Outbox.MaxAttempts = defaultOutboxMaxAttempts
+		if maxAttempts := os.Getenv("OUTBOX_MAX_ATTEMPTS"); maxAttempts != "" {
+			cfg.Outbox.MaxAttempts, err = parseInt(maxAttempts)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cfg.Outbox.BackoffBaseMS = defaultOutboxBackoffBase
+		if backoffBase := os.Getenv("OUTBOX_BACKOFF_BASE_MS"); backoffBase != "" {
+			cfg.Outbox.BackoffBaseMS, err = parseTime(backoffBase)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cfg.Outbox.BackoffMaxMS = defaultOutboxBackoffMax
+		if backoffMax := os.Getenv("OUTBOX_BACKOFF_MAX_MS"); backoffMax != "" {
+			cfg.Outbox.BackoffMaxMS, err = parseTime(backoffMax)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if cfg.Outbox.MaxAttempts <= 0 || cfg.Outbox.BackoffBaseMS <= 0 ||
+			cfg.Outbox.BackoffMaxMS < cfg.Outbox.BackoffBaseMS {
+			return nil, fmt.Errorf("Outbox retries are misconfigured: MaxAttempts=%d, BackoffBase=%s, BackoffMax=%s",
+				cfg.Outbox.MaxAttempts, cfg.Outbox.BackoffBaseMS, cfg.Outbox.BackoffMaxMS)
+		}
+
+		cfg.Outbox.RetentionHours = defaultOutboxRetention
+		if retention := os.Getenv("OUTBOX_RETENTION_HOURS"); retention != "" {
+			hours, err := parseInt(retention)
+			if err != nil {
+				return nil, err
+			}
+			if hours < 0 {
+				return nil, fmt.Errorf("Outbox retention must not be negative: RetentionHours=%d", hours)
+			}
+
+			cfg.Outbox.RetentionHours = time.Duration(hours) * time.Hour
+		}
+
+		if ordered := os.Getenv("OUTBOX_ORDERED"); ordered != "" {
+			cfg.Outbox.Ordered, err = strconv.ParseBool(ordered)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	cfg.Inbox.Token = os.Getenv("INBOX_TOKEN")
+
+	if enabled := os.Getenv("INBOX_ENABLED"); enabled != "" {
+		cfg.Inbox.Enabled, err = strconv.ParseBool(enabled)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Inbox.Enabled {
+		cfg.Inbox.Workers, err = parseInt(os.Getenv("INBOX_WORKERS"))
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Inbox.BatchSize, err = parseInt(os.Getenv("INBOX_BATCH_SIZE"))
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Inbox.WaitTimeMS, err = parseTime(os.Getenv("INBOX_WAIT_TIME_MS"))
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Inbox.InProgressTTLMS, err = parseTime(os.Getenv("INBOX_IN_PROGRESS_TTL_MS"))
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Inbox.MaxAttempts = defaultInboxMaxAttempts
+		if maxAttempts := os.Getenv("INBOX_MAX_ATTEMPTS"); maxAttempts != "" {
+			cfg.Inbox.MaxAttempts, err = parseInt(maxAttempts)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cfg.Inbox.BackoffBaseMS = defaultInboxBackoffBase
+		if backoffBase := os.Getenv("INBOX_BACKOFF_BASE_MS"); backoffBase != "" {
+			cfg.Inbox.BackoffBaseMS, err = parseTime(backoffBase)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cfg.Inbox.BackoffMaxMS = defaultInboxBackoffMax
+		if backoffMax := os.Getenv("INBOX_BACKOFF_MAX_MS"); backoffMax != "" {
+			cfg.Inbox.BackoffMaxMS, err = parseTime(backoffMax)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if cfg.Inbox.MaxAttempts <= 0 || cfg.Inbox.BackoffBaseMS <= 0 ||
+			cfg.Inbox.BackoffMaxMS < cfg.Inbox.BackoffBaseMS {
+			return nil, fmt.Errorf("Inbox retries are misconfigured: MaxAttempts=%d, BackoffBase=%s, BackoffMax=%s",
+				cfg.Inbox.MaxAttempts, cfg.Inbox.BackoffBaseMS, cfg.Inbox.BackoffMaxMS)
+		}
+	}
+
+	if enabled := os.Getenv("FINES_ENABLED"); enabled != "" {
+		cfg.Fines.Enabled, err = strconv.ParseBool(enabled)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Fines.Enabled {
+		cfg.Fines.IntervalMS, err = parseTime(os.Getenv("FINES_INTERVAL_MS"))
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Fines.DailyRate, err = parseInt64(os.Getenv("FINES_DAILY_RATE"))
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Fines.MaxPerLoan, err = parseInt64(os.Getenv("FINES_MAX_PER_LOAN"))
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Fines.GraceDays, err = parseInt(os.Getenv("FINES_GRACE_DAYS"))
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.Fines.IntervalMS <= 0 || cfg.Fines.DailyRate < 0 ||
+			cfg.Fines.MaxPerLoan < 0 || cfg.Fines.GraceDays < 0 {
+			return nil, fmt.Errorf("Fines interval must be positive and policy not negative: Interval=%s, DailyRate=%d, MaxPerLoan=%d, GraceDays=%d",
+				cfg.Fines.IntervalMS, cfg.Fines.DailyRate, cfg.Fines.MaxPerLoan, cfg.Fines.GraceDays)
 		}
 	}
 
 	return cfg, nil
 }
 
+// validatePublishers checks that every publisher in use is configured.
+func validatePublishers(outbox *Outbox) error {
+	used := map[string]bool{outbox.Publisher: true}
+	for _, publisher := range outbox.Publishers {
+		used[publisher] = true
+	}
+
+	for publisher := range used {
+		switch publisher {
+		case "http":
+			if outbox.BookSendURL == "" || outbox.AuthorSendURL == "" {
+				return fmt.Errorf("Outbox URLs must be configured: BookSendURL='%s', AuthorSendURL='%s'",
+					outbox.BookSendURL, outbox.AuthorSendURL)
+			}
+			if outbox.HTTPBody != "key" && outbox.HTTPBody != "payload" && outbox.HTTPBody != "envelope" {
+				return fmt.Errorf("unknown outbox HTTP body: '%s'", outbox.HTTPBody)
+			}
+			if len(outbox.HTTPSigningKeys) == 0 && !outbox.HTTPAllowUnsigned {
+				return errors.New("outbox HTTP signing key must be configured, " +
+					"or unsigned webhooks allowed for legacy receivers")
+			}
+			if len(outbox.HTTPSigningKeys) > maxOutboxSigningKeys {
+				return fmt.Errorf("at most %d outbox HTTP signing keys can be active, got %d",
+					maxOutboxSigningKeys, len(outbox.HTTPSigningKeys))
+			}
+		case "kafka":
+			if len(outbox.KafkaBrokers) == 0 || outbox.KafkaTopic == "" {
+				return fmt.Errorf("Outbox Kafka must be configured: Brokers=%v, Topic='%s'",
+					outbox.KafkaBrokers, outbox.KafkaTopic)
+			}
+		case "nats":
+			if outbox.NATSURL == "" || outbox.NATSSubject == "" {
+				return fmt.Errorf("Outbox NATS must be configured: URL='%s', Subject='%s'",
+					outbox.NATSURL, outbox.NATSSubject)
+			}
+		case "file":
+			if outbox.FilePath == "" {
+				return errors.New("Outbox file path must be configured")
+			}
+		default:
+			return fmt.Errorf("unknown outbox publisher: '%s'", publisher)
+		}
+	}
+
+	for publisher, mode := range outbox.CloudEvents {
+		switch {
+		case publisher != "http" && publisher != "kafka" && publisher != "nats" && publisher != "file":
+			return fmt.Errorf("unknown outbox publisher in CloudEvents: '%s'", publisher)
+		case mode != "structured" && mode != "binary":
+			return fmt.Errorf("unknown CloudEvents mode of %s: '%s'", publisher, mode)
+		case publisher == "file" && mode == "binary":
+			return errors.New("file publisher supports only structured CloudEvents")
+		}
+	}
+
+	return nil
+}
+
+// parseList reads a comma-separated list.
+func parseList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// parseMap reads comma-separated key=value pairs.
+func parseMap(s string) (map[string]string, error) {
+	items := parseList(s)
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	m := make(map[string]string, len(items))
+	for _, item := range items {
+		key, value, ok := strings.Cut(item, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid key=value pair: '%s'", item)
+		}
+
+		m[key] = value
+	}
+
+	return m, nil
+}
+
 func parseTime(s string) (time.Duration, error) {
 	t, err := parseInt(s)
 	return time.Duration(t) * time.Millisecond, err
 }
 
 func parseInt(s string) (int, error) {
-	num, err := strconv.ParseInt(s, 10, 64)
+	num, err := parseInt64(s)
 	if err != nil {
 		return 0, err
 	}
 
 	return int(num), nil
 }
+
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
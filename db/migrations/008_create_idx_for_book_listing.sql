@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE INDEX idx_book_created_at_id ON book (created_at, id);
+CREATE INDEX idx_book_updated_at ON book (updated_at);
+CREATE INDEX idx_book_name_pattern ON book (name text_pattern_ops);
+
+-- +goose Down
+DROP INDEX idx_book_name_pattern;
+DROP INDEX idx_book_updated_at;
+DROP INDEX idx_book_created_at_id;
@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_author_lower_name_id ON author (lower(name), id);
+CREATE INDEX idx_author_lower_name_trgm ON author USING gin (lower(name) gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX idx_author_lower_name_trgm;
+DROP INDEX idx_author_lower_name_id;
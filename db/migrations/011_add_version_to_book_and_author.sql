@@ -0,0 +1,53 @@
+-- +goose Up
+ALTER TABLE book ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE author ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_book_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_author_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_author_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_book_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+ALTER TABLE author DROP COLUMN version;
+ALTER TABLE book DROP COLUMN version;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE book
+    ADD COLUMN isbn             TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN publisher        TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN publication_year INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN language         TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN page_count       INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN description      TEXT    NOT NULL DEFAULT '';
+
+-- isbn is stored normalized to ISBN-13, so ISBN-10 and ISBN-13 forms of
+-- the same book collide here.
+CREATE UNIQUE INDEX idx_book_isbn ON book (isbn) WHERE isbn <> '' AND deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX idx_book_isbn;
+
+ALTER TABLE book
+    DROP COLUMN description,
+    DROP COLUMN page_count,
+    DROP COLUMN language,
+    DROP COLUMN publication_year,
+    DROP COLUMN publisher,
+    DROP COLUMN isbn;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE author
+    ADD COLUMN birth_date      DATE,
+    ADD COLUMN death_date      DATE,
+    ADD COLUMN nationality     TEXT   NOT NULL DEFAULT '',
+    ADD COLUMN biography       TEXT   NOT NULL DEFAULT '',
+    ADD COLUMN alternate_names TEXT[] NOT NULL DEFAULT '{}',
+    ADD CONSTRAINT author_lifespan_check CHECK (death_date >= birth_date);
+
+-- +goose Down
+ALTER TABLE author
+    DROP CONSTRAINT author_lifespan_check,
+    DROP COLUMN alternate_names,
+    DROP COLUMN biography,
+    DROP COLUMN nationality,
+    DROP COLUMN death_date,
+    DROP COLUMN birth_date;
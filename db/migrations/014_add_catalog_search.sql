@@ -0,0 +1,44 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- array_to_string is only STABLE, so generated columns need an IMMUTABLE
+-- wrapper; joining text never depends on settings.
+-- +goose StatementBegin
+CREATE FUNCTION immutable_array_to_string(arr TEXT[], sep TEXT) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE AS
+$$
+SELECT array_to_string(arr, sep)
+$$;
+-- +goose StatementEnd
+
+-- The 'simple' configuration doesn't stem, so Russian and English titles
+-- are indexed the same way.
+ALTER TABLE book
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', name), 'A') ||
+        setweight(to_tsvector('simple', description), 'B')
+    ) STORED;
+
+ALTER TABLE author
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', name), 'A') ||
+        setweight(to_tsvector('simple', immutable_array_to_string(alternate_names, ' ')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_book_search_vector ON book USING GIN (search_vector);
+CREATE INDEX idx_author_search_vector ON author USING GIN (search_vector);
+
+-- Trigram indexes back the typo-tolerant fallback (word similarity on names).
+CREATE INDEX idx_book_name_trgm ON book USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_author_name_trgm ON author USING GIN (name gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX idx_author_name_trgm;
+DROP INDEX idx_book_name_trgm;
+DROP INDEX idx_author_search_vector;
+DROP INDEX idx_book_search_vector;
+
+ALTER TABLE author DROP COLUMN search_vector;
+ALTER TABLE book DROP COLUMN search_vector;
+
+DROP FUNCTION immutable_array_to_string(TEXT[], TEXT);
@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE genre
+(
+    id         UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name       TEXT                    NOT NULL,
+    parent_id  UUID REFERENCES genre (id) ON DELETE RESTRICT,
+    created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+-- Sibling names are unique regardless of case; roots are siblings too.
+CREATE UNIQUE INDEX idx_genre_parent_name
+    ON genre (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), lower(name));
+
+CREATE INDEX idx_genre_parent ON genre (parent_id);
+
+CREATE TABLE book_genre
+(
+    genre_id UUID NOT NULL REFERENCES genre (id) ON DELETE RESTRICT,
+    book_id  UUID NOT NULL REFERENCES book (id) ON DELETE CASCADE,
+    PRIMARY KEY (genre_id, book_id)
+);
+
+CREATE INDEX idx_book_genre_book ON book_genre (book_id);
+
+CREATE TABLE book_tag
+(
+    book_id UUID NOT NULL REFERENCES book (id) ON DELETE CASCADE,
+    tag     TEXT NOT NULL,
+    PRIMARY KEY (book_id, tag)
+);
+
+CREATE INDEX idx_book_tag_tag ON book_tag (tag);
+
+-- +goose Down
+DROP TABLE book_tag;
+DROP TABLE book_genre;
+DROP TABLE genre;
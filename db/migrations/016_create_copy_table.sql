@@ -0,0 +1,41 @@
+-- +goose Up
+CREATE TABLE copy
+(
+    id             UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    book_id        UUID      NOT NULL REFERENCES book (id) ON DELETE CASCADE,
+    barcode        TEXT      NOT NULL,
+    branch         TEXT      NOT NULL,
+    shelf_location TEXT      NOT NULL DEFAULT '',
+    condition      TEXT      NOT NULL DEFAULT 'good'
+        CONSTRAINT copy_condition_check CHECK (condition IN ('new', 'good', 'fair', 'poor', 'damaged')),
+    acquired_on    DATE,
+    status         TEXT      NOT NULL DEFAULT 'available'
+        CONSTRAINT copy_status_check CHECK (status IN ('available', 'on_loan', 'lost', 'in_repair')),
+    created_at     TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at     TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_copy_barcode ON copy (barcode);
+
+CREATE INDEX idx_copy_book ON copy (book_id, branch, barcode);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_copy_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_update_copy_timestamp
+    BEFORE UPDATE
+    ON copy
+    FOR EACH ROW
+EXECUTE FUNCTION update_copy_timestamp();
+
+-- +goose Down
+DROP TABLE copy;
+DROP FUNCTION update_copy_timestamp();
@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TABLE patron
+(
+    id                    UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    card_number           TEXT      NOT NULL,
+    name                  TEXT      NOT NULL,
+    email                 TEXT      NOT NULL DEFAULT '',
+    phone                 TEXT      NOT NULL DEFAULT '',
+    address               TEXT      NOT NULL DEFAULT '',
+    membership_expires_on DATE      NOT NULL,
+    status                TEXT      NOT NULL DEFAULT 'active'
+        CONSTRAINT patron_status_check CHECK (status IN ('active', 'blocked')),
+    block_reason          TEXT      NOT NULL DEFAULT '',
+    created_at            TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at            TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_patron_card_number ON patron (card_number);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_patron_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_update_patron_timestamp
+    BEFORE UPDATE
+    ON patron
+    FOR EACH ROW
+EXECUTE FUNCTION update_patron_timestamp();
+
+-- +goose Down
+DROP TABLE patron;
+DROP FUNCTION update_patron_timestamp();
@@ -0,0 +1,40 @@
+-- +goose Up
+CREATE TABLE loan
+(
+    id             UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    copy_id        UUID      NOT NULL REFERENCES copy (id) ON DELETE CASCADE,
+    book_id        UUID      NOT NULL REFERENCES book (id) ON DELETE CASCADE,
+    patron_id      UUID      NOT NULL REFERENCES patron (id),
+    checked_out_at TIMESTAMP NOT NULL DEFAULT now(),
+    due_on         DATE      NOT NULL,
+    returned_at    TIMESTAMP,
+    renewals       INT       NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at     TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- A copy has at most one open loan, which backs up the row lock taken
+-- on checkout.
+CREATE UNIQUE INDEX idx_loan_open_copy ON loan (copy_id) WHERE returned_at IS NULL;
+CREATE INDEX idx_loan_open_patron ON loan (patron_id) WHERE returned_at IS NULL;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_loan_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_update_loan_timestamp
+    BEFORE UPDATE
+    ON loan
+    FOR EACH ROW
+EXECUTE FUNCTION update_loan_timestamp();
+
+-- +goose Down
+DROP TABLE loan;
+DROP FUNCTION update_loan_timestamp();
@@ -0,0 +1,51 @@
+-- +goose Up
+ALTER TABLE copy DROP CONSTRAINT copy_status_check;
+ALTER TABLE copy ADD CONSTRAINT copy_status_check
+    CHECK (status IN ('available', 'on_loan', 'on_hold', 'lost', 'in_repair'));
+
+CREATE TABLE hold
+(
+    id         UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    book_id    UUID      NOT NULL REFERENCES book (id) ON DELETE CASCADE,
+    patron_id  UUID      NOT NULL REFERENCES patron (id),
+    copy_id    UUID      REFERENCES copy (id) ON DELETE SET NULL,
+    status     TEXT      NOT NULL DEFAULT 'waiting'
+        CONSTRAINT hold_status_check CHECK (status IN ('waiting', 'ready', 'fulfilled', 'cancelled', 'expired')),
+    placed_at  TIMESTAMP NOT NULL DEFAULT now(),
+    ready_at   TIMESTAMP,
+    expires_on DATE,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_hold_open_patron_book ON hold (book_id, patron_id)
+    WHERE status IN ('waiting', 'ready');
+CREATE INDEX idx_hold_queue ON hold (book_id, placed_at, id) WHERE status = 'waiting';
+CREATE INDEX idx_hold_ready_copy ON hold (copy_id) WHERE status = 'ready';
+CREATE INDEX idx_hold_patron ON hold (patron_id);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_hold_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_update_hold_timestamp
+    BEFORE UPDATE
+    ON hold
+    FOR EACH ROW
+EXECUTE FUNCTION update_hold_timestamp();
+
+-- +goose Down
+DROP TABLE hold;
+DROP FUNCTION update_hold_timestamp();
+
+UPDATE copy SET status = 'available' WHERE status = 'on_hold';
+ALTER TABLE copy DROP CONSTRAINT copy_status_check;
+ALTER TABLE copy ADD CONSTRAINT copy_status_check
+    CHECK (status IN ('available', 'on_loan', 'lost', 'in_repair'));
@@ -0,0 +1,37 @@
+-- +goose Up
+-- Amounts are in minor currency units. accrued_on is the last day the
+-- fine was accrued for: today for an open loan, the return day otherwise.
+CREATE TABLE fine
+(
+    id         UUID PRIMARY KEY   DEFAULT uuid_generate_v4(),
+    loan_id    UUID      NOT NULL UNIQUE REFERENCES loan (id) ON DELETE CASCADE,
+    patron_id  UUID      NOT NULL REFERENCES patron (id),
+    amount     BIGINT    NOT NULL CHECK (amount >= 0),
+    paid       BIGINT    NOT NULL DEFAULT 0 CHECK (paid >= 0 AND paid <= amount),
+    accrued_on DATE      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_fine_outstanding_patron ON fine (patron_id) WHERE paid < amount;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_fine_timestamp() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE OR REPLACE TRIGGER trigger_update_fine_timestamp
+    BEFORE UPDATE
+    ON fine
+    FOR EACH ROW
+EXECUTE FUNCTION update_fine_timestamp();
+
+-- +goose Down
+DROP TABLE fine;
+DROP FUNCTION update_fine_timestamp();
@@ -0,0 +1,30 @@
+-- +goose NO TRANSACTION
+-- New enum values can't be used in the transaction that adds them, so the
+-- migration runs statement by statement.
+
+-- +goose Up
+ALTER TYPE outbox_status ADD VALUE IF NOT EXISTS 'FAILED';
+ALTER TYPE outbox_status ADD VALUE IF NOT EXISTS 'DEAD';
+
+-- attempts counts the deliveries started; a FAILED message is retried
+-- once next_attempt_at has passed, a DEAD one is never retried.
+ALTER TABLE outbox
+    ADD COLUMN attempts        INT       NOT NULL DEFAULT 0,
+    ADD COLUMN next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+    ADD COLUMN last_error      TEXT;
+
+CREATE INDEX idx_outbox_pending ON outbox (next_attempt_at) WHERE status IN ('CREATED', 'FAILED');
+
+-- +goose Down
+DROP INDEX idx_outbox_pending;
+
+ALTER TABLE outbox
+    DROP COLUMN attempts,
+    DROP COLUMN next_attempt_at,
+    DROP COLUMN last_error;
+
+ALTER TABLE outbox ALTER COLUMN status TYPE TEXT;
+UPDATE outbox SET status = 'CREATED' WHERE status IN ('FAILED', 'DEAD');
+DROP TYPE outbox_status;
+CREATE TYPE outbox_status as ENUM ('CREATED', 'IN_PROGRESS', 'SUCCESS');
+ALTER TABLE outbox ALTER COLUMN status TYPE outbox_status USING status::outbox_status;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX idx_outbox_status_created_at ON outbox (status, created_at, idempotency_key);
+
+-- +goose Down
+DROP INDEX idx_outbox_status_created_at;
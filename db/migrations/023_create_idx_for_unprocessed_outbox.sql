@@ -0,0 +1,12 @@
+-- +goose NO TRANSACTION
+-- The outbox table can be large by now, so the index is built without
+-- blocking writes.
+
+-- +goose Up
+-- Delivered rows make up most of the table; leaving them out keeps the
+-- worker's fetch proportional to the pending work.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_outbox_unprocessed_created_at
+    ON outbox (created_at) WHERE status <> 'SUCCESS';
+
+-- +goose Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_outbox_unprocessed_created_at;
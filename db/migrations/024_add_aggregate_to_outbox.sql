@@ -0,0 +1,33 @@
+-- +goose NO TRANSACTION
+-- The outbox table can be large by now, so the index is built without
+-- blocking writes. Without a transaction a failed run leaves the statements
+-- before it applied, so each of them can be run again.
+
+-- +goose Up
+-- Messages about one aggregate (a book with its copies, loans and holds,
+-- or an author) are numbered by sequence in the order their transactions
+-- commit. Messages written before have no aggregate and aren't ordered.
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS aggregate_key TEXT,
+    ADD COLUMN IF NOT EXISTS sequence      BIGINT;
+
+-- The row of an aggregate stays locked until the transaction that took the
+-- next sequence commits, so concurrent writers take numbers in turn.
+CREATE TABLE IF NOT EXISTS outbox_aggregate
+(
+    aggregate_key TEXT PRIMARY KEY,
+    sequence      BIGINT NOT NULL
+);
+
+-- Lets the ordered fetch find the undelivered predecessors of a message.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_outbox_unprocessed_aggregate
+    ON outbox (aggregate_key, sequence) WHERE status <> 'SUCCESS';
+
+-- +goose Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_outbox_unprocessed_aggregate;
+
+DROP TABLE IF EXISTS outbox_aggregate;
+
+ALTER TABLE outbox
+    DROP COLUMN IF EXISTS aggregate_key,
+    DROP COLUMN IF EXISTS sequence;
@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TYPE inbox_status as ENUM ('CREATED', 'IN_PROGRESS', 'SUCCESS', 'FAILED', 'DEAD');
+
+-- Events received from other services. message_id is chosen by the sender,
+-- so a redelivered event hits the primary key and is stored only once.
+CREATE TABLE inbox
+(
+    message_id      TEXT PRIMARY KEY,
+    kind            TEXT                    NOT NULL,
+    data            JSONB                   NOT NULL,
+    status          inbox_status            NOT NULL,
+    trace_id        TEXT,
+    attempts        INT       DEFAULT 0     NOT NULL,
+    next_attempt_at TIMESTAMP DEFAULT now() NOT NULL,
+    last_error      TEXT,
+    created_at      TIMESTAMP DEFAULT now() NOT NULL,
+    updated_at      TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE
+OR REPLACE TRIGGER trigger_update_inbox_timestamp
+    BEFORE
+UPDATE
+    ON inbox
+    FOR EACH ROW
+    EXECUTE FUNCTION update_outbox_timestamp();
+
+CREATE INDEX idx_inbox_unprocessed_created_at ON inbox (created_at) WHERE status <> 'SUCCESS';
+
+-- +goose Down
+DROP TABLE inbox;
+DROP TYPE inbox_status;
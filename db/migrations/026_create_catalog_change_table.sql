@@ -0,0 +1,46 @@
+-- +goose Up
+-- The change log of books and authors that WatchCatalog streams. Positions
+-- are taken from the single row of catalog_change_position, which stays
+-- locked until the transaction that took a position commits: positions
+-- follow the commit order and have no gaps, so a reader that has seen
+-- position N has seen every change before it.
+CREATE TABLE catalog_change
+(
+    position   BIGINT PRIMARY KEY,
+    kind       INT                     NOT NULL,
+    entity_id  TEXT                    NOT NULL,
+    data       JSONB                   NOT NULL,
+    trace_id   TEXT,
+    created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE TABLE catalog_change_position
+(
+    id       BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    position BIGINT NOT NULL
+);
+
+INSERT INTO catalog_change_position (position) VALUES (0);
+
+-- Wakes the watchers up once the transaction commits.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_catalog_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_notify('catalog_change', '');
+    RETURN NULL;
+END;
+$$
+LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_notify_catalog_change
+    AFTER INSERT
+    ON catalog_change
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION notify_catalog_change();
+
+-- +goose Down
+DROP TABLE catalog_change;
+DROP TABLE catalog_change_position;
+DROP FUNCTION notify_catalog_change();
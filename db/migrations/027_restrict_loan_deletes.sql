@@ -0,0 +1,24 @@
+-- +goose Up
+-- Loans and fines are the circulation history and money owed, so deleting
+-- a copy or a book no longer takes them along: a copy or a book that was
+-- ever lent can't be deleted.
+ALTER TABLE loan
+    DROP CONSTRAINT loan_copy_id_fkey,
+    ADD CONSTRAINT loan_copy_id_fkey FOREIGN KEY (copy_id) REFERENCES copy (id) ON DELETE RESTRICT,
+    DROP CONSTRAINT loan_book_id_fkey,
+    ADD CONSTRAINT loan_book_id_fkey FOREIGN KEY (book_id) REFERENCES book (id) ON DELETE RESTRICT;
+
+ALTER TABLE fine
+    DROP CONSTRAINT fine_loan_id_fkey,
+    ADD CONSTRAINT fine_loan_id_fkey FOREIGN KEY (loan_id) REFERENCES loan (id) ON DELETE RESTRICT;
+
+-- +goose Down
+ALTER TABLE fine
+    DROP CONSTRAINT fine_loan_id_fkey,
+    ADD CONSTRAINT fine_loan_id_fkey FOREIGN KEY (loan_id) REFERENCES loan (id) ON DELETE CASCADE;
+
+ALTER TABLE loan
+    DROP CONSTRAINT loan_copy_id_fkey,
+    ADD CONSTRAINT loan_copy_id_fkey FOREIGN KEY (copy_id) REFERENCES copy (id) ON DELETE CASCADE,
+    DROP CONSTRAINT loan_book_id_fkey,
+    ADD CONSTRAINT loan_book_id_fkey FOREIGN KEY (book_id) REFERENCES book (id) ON DELETE CASCADE;
@@ -0,0 +1,11 @@
+-- +goose NO TRANSACTION
+-- The index is built without blocking author writes.
+
+-- +goose Up
+-- The (lower(name), id) index keeps the collation order for paging, so it
+-- can't serve LIKE prefixes outside the C locale.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_author_lower_name_pattern
+    ON author (lower(name) text_pattern_ops);
+
+-- +goose Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_author_lower_name_pattern;
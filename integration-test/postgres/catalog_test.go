@@ -0,0 +1,90 @@
+//go:build integration_test
+
+package integration_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/project/library/internal/entity"
+)
+
+// TestAuthorMergeWithConcurrentUpdates merges an author while its books are
+// being updated: the merge locks the books one by one, so a catalog change
+// position taken before the next book lock would deadlock with the updates.
+func TestAuthorMergeWithConcurrentUpdates(t *testing.T) {
+	cleanUp(t)
+
+	const (
+		rounds = 10
+		books  = 5
+	)
+
+	lib, transactor := newLibrary()
+	ctx := t.Context()
+
+	for round := range rounds {
+		source, err := lib.RegisterAuthor(ctx, &entity.Author{Name: fmt.Sprintf("Source %d", round)})
+		require.NoError(t, err)
+		target, err := lib.RegisterAuthor(ctx, &entity.Author{Name: fmt.Sprintf("Target %d", round)})
+		require.NoError(t, err)
+
+		bookIDs := make([]string, 0, books)
+		for i := range books {
+			book, err := lib.AddBook(ctx, &entity.Book{
+				Name:      fmt.Sprintf("Book %d-%d", round, i),
+				AuthorIDs: []string{source.ID},
+			})
+			require.NoError(t, err)
+			bookIDs = append(bookIDs, book.ID)
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan error, books+1)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- transactor.WithTx(ctx, func(ctx context.Context) error {
+				return lib.ApplyAuthorMerged(ctx, entity.AuthorMerged{SourceID: source.ID, TargetID: target.ID})
+			})
+		}()
+
+		// The updates go from the last book, the one the merge locks last.
+		for i := len(bookIDs) - 1; i >= 0; i-- {
+			name := fmt.Sprintf("Renamed %d-%d", round, i)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := lib.UpdateBook(ctx, bookIDs[i], entity.BookUpdate{Name: &name}, 0)
+				errs <- err
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		for i, id := range bookIDs {
+			book, err := lib.GetBook(ctx, id, false)
+			require.NoError(t, err)
+			require.Equal(t, []string{target.ID}, book.AuthorIDs)
+			require.Equal(t, fmt.Sprintf("Renamed %d-%d", round, i), book.Name)
+		}
+	}
+
+	// Every committed change got the next position.
+	var count, last int64
+	err := pool.QueryRow(ctx, "SELECT count(*), COALESCE(max(position), 0) FROM catalog_change").
+		Scan(&count, &last)
+	require.NoError(t, err)
+	require.Equal(t, count, last)
+}
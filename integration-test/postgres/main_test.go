@@ -0,0 +1,80 @@
+//go:build integration_test
+
+package integration_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/project/library/db"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+var pool *pgxpool.Pool
+
+// TestMain runs the repositories against the database from the POSTGRES_*
+// environment, migrated to the latest version.
+func TestMain(m *testing.M) {
+	source := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		url.QueryEscape(os.Getenv("POSTGRES_USER")),
+		url.QueryEscape(os.Getenv("POSTGRES_PASSWORD")),
+		os.Getenv("POSTGRES_HOST"),
+		os.Getenv("POSTGRES_PORT"),
+		os.Getenv("POSTGRES_DB"),
+	)
+
+	var err error
+	pool, err = pgxpool.New(context.Background(), source)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "can not connect to database: %v\n", err)
+		os.Exit(1)
+	}
+
+	db.SetupPostgres(pool, zap.NewNop())
+
+	code := m.Run()
+	pool.Close()
+	os.Exit(code)
+}
+
+func cleanUp(t *testing.T) {
+	t.Helper()
+
+	_, err := pool.Exec(t.Context(), `
+TRUNCATE TABLE author, book, outbox, outbox_aggregate, catalog_change RESTART IDENTITY CASCADE;
+UPDATE catalog_change_position SET position = 0;`)
+	require.NoError(t, err)
+}
+
+type libraryService interface {
+	library.AuthorUseCase
+	library.BooksUseCase
+	library.InboxUseCase
+}
+
+func newLibrary() (libraryService, repository.Transactor) {
+	logger := zap.NewNop()
+	repo := repository.NewPostgresRepository(pool, logger)
+	transactor := repository.NewTransactor(pool, logger)
+
+	return library.New(logger, library.Repositories{
+		Author:     repo,
+		Books:      repo,
+		Genre:      repo,
+		Copy:       repo,
+		Patron:     repo,
+		Loan:       repo,
+		Hold:       repo,
+		Fine:       repo,
+		Outbox:     repository.NewOutbox(pool, logger),
+		Transactor: transactor,
+	}), transactor
+}
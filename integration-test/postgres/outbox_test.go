@@ -0,0 +1,196 @@
+//go:build integration_test
+
+package integration_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/usecase/repository"
+)
+
+// TestOrderedMessagesWithManyWorkers delivers the messages of a few
+// aggregates with many workers that fail now and then: every aggregate
+// must see its messages one at a time and in sequence order.
+func TestOrderedMessagesWithManyWorkers(t *testing.T) {
+	cleanUp(t)
+
+	const (
+		aggregates = 5
+		perAggr    = 20
+		workers    = 8
+	)
+
+	outbox := repository.NewOutbox(pool, zap.NewNop())
+	ctx := t.Context()
+
+	for i := range perAggr {
+		for a := range aggregates {
+			err := outbox.SendMessage(ctx, fmt.Sprintf("key-%d-%d", a, i), repository.OutboxKindCopy,
+				fmt.Sprintf("aggregate-%d", a), []byte("{}"), "")
+			require.NoError(t, err)
+		}
+	}
+
+	var (
+		mu        sync.Mutex
+		inFlight  = make(map[string]bool)
+		delivered = make(map[string][]int64)
+		failures  []error
+	)
+
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, err)
+	}
+
+	deadline := time.Now().Add(time.Minute)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for time.Now().Before(deadline) {
+				messages, err := outbox.GetOrderedMessages(ctx, 3, time.Minute)
+				if err != nil {
+					fail(err)
+					return
+				}
+
+				if len(messages) == 0 {
+					mu.Lock()
+					done := len(delivered) == aggregates && allDelivered(delivered, perAggr)
+					mu.Unlock()
+					if done {
+						return
+					}
+
+					time.Sleep(5 * time.Millisecond)
+					continue
+				}
+
+				for _, message := range messages {
+					mu.Lock()
+					if inFlight[message.AggregateKey] {
+						failures = append(failures, fmt.Errorf("%s is claimed twice", message.AggregateKey))
+					}
+					inFlight[message.AggregateKey] = true
+					mu.Unlock()
+
+					time.Sleep(time.Duration(rand.IntN(2)) * time.Millisecond)
+
+					mu.Lock()
+					inFlight[message.AggregateKey] = false
+					if rand.IntN(4) > 0 {
+						delivered[message.AggregateKey] = append(delivered[message.AggregateKey], message.Sequence)
+						err = outbox.MarkAsProcessed(ctx, message.IdempotencyKey, message.Attempts)
+					} else {
+						err = outbox.MarkAsFailed(ctx, message.IdempotencyKey, message.Attempts, "failed", 0)
+					}
+					mu.Unlock()
+
+					if err != nil {
+						fail(err)
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.Empty(t, failures)
+	require.Len(t, delivered, aggregates)
+	for aggregate, sequences := range delivered {
+		want := make([]int64, 0, perAggr)
+		for sequence := range int64(perAggr) {
+			want = append(want, sequence+1)
+		}
+		require.Equal(t, want, sequences, aggregate)
+	}
+}
+
+func allDelivered(delivered map[string][]int64, perAggr int) bool {
+	for _, sequences := range delivered {
+		if len(sequences) < perAggr {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TestReclaimedOrderedMessage lets a worker outlive inProgressTTL: the
+// message goes to another worker, the outcome of the first one is
+// dropped, and the next message of the aggregate waits for the second.
+func TestReclaimedOrderedMessage(t *testing.T) {
+	cleanUp(t)
+
+	const ttl = 100 * time.Millisecond
+
+	outbox := repository.NewOutbox(pool, zap.NewNop())
+	ctx := t.Context()
+
+	for _, key := range []string{"first", "second"} {
+		err := outbox.SendMessage(ctx, key, repository.OutboxKindCopy, "aggregate", []byte("{}"), "")
+		require.NoError(t, err)
+	}
+
+	slow, err := outbox.GetOrderedMessages(ctx, 10, ttl)
+	require.NoError(t, err)
+	require.Len(t, slow, 1)
+	require.Equal(t, "first", slow[0].IdempotencyKey)
+
+	// Nothing else is due while the first message is claimed.
+	claimed, err := outbox.GetOrderedMessages(ctx, 10, ttl)
+	require.NoError(t, err)
+	require.Empty(t, claimed)
+
+	time.Sleep(2 * ttl)
+
+	reclaimed, err := outbox.GetOrderedMessages(ctx, 10, ttl)
+	require.NoError(t, err)
+	require.Len(t, reclaimed, 1)
+	require.Equal(t, "first", reclaimed[0].IdempotencyKey)
+	require.Equal(t, slow[0].Attempts+1, reclaimed[0].Attempts)
+
+	// The late outcomes of the first worker don't count.
+	err = outbox.MarkAsProcessed(ctx, slow[0].IdempotencyKey, slow[0].Attempts)
+	require.ErrorIs(t, err, repository.ErrOutboxMessageReclaimed)
+	err = outbox.MarkAsDead(ctx, slow[0].IdempotencyKey, slow[0].Attempts, "too slow")
+	require.ErrorIs(t, err, repository.ErrOutboxMessageReclaimed)
+
+	claimed, err = outbox.GetOrderedMessages(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, claimed)
+
+	err = outbox.MarkAsFailed(ctx, reclaimed[0].IdempotencyKey, reclaimed[0].Attempts, "unavailable", 0)
+	require.NoError(t, err)
+
+	message, err := outbox.GetMessage(ctx, "first")
+	require.NoError(t, err)
+	require.Equal(t, repository.OutboxStatusFailed, message.Status)
+
+	// The retry comes before the next message of the aggregate.
+	claimed, err = outbox.GetOrderedMessages(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, "first", claimed[0].IdempotencyKey)
+
+	err = outbox.MarkAsProcessed(ctx, claimed[0].IdempotencyKey, claimed[0].Attempts)
+	require.NoError(t, err)
+
+	claimed, err = outbox.GetOrderedMessages(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, "second", claimed[0].IdempotencyKey)
+}
@@ -0,0 +1,72 @@
+//go:build integration_test
+
+package integration_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/project/library/internal/entity"
+)
+
+// TestSearchSnippetIsEscaped stores markup in a book: the snippet keeps it
+// as text, and only the highlighting is markup.
+func TestSearchSnippetIsEscaped(t *testing.T) {
+	cleanUp(t)
+
+	lib, _ := newLibrary()
+	ctx := t.Context()
+
+	_, err := lib.AddBook(ctx, &entity.Book{
+		Name:        "Tom & Jerry",
+		Description: `<img src=x onerror="alert(1)"> Jerry runs`,
+	})
+	require.NoError(t, err)
+
+	results, _, err := lib.SearchCatalog(ctx, entity.SearchFilter{Query: "jerry"}, 10, "")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	snippet := results[0].Snippet
+	require.Contains(t, snippet, "<b>Jerry</b>")
+
+	text := strings.NewReplacer("<b>", "", "</b>", "").Replace(snippet)
+	require.NotContains(t, text, "<")
+	require.NotContains(t, text, ">")
+}
+
+// TestSearchPages pages through more matches than fit a page.
+func TestSearchPages(t *testing.T) {
+	cleanUp(t)
+
+	lib, _ := newLibrary()
+	ctx := t.Context()
+
+	for i := range 5 {
+		_, err := lib.AddBook(ctx, &entity.Book{Name: fmt.Sprintf("Dune %d", i)})
+		require.NoError(t, err)
+	}
+
+	seen := make(map[string]bool)
+	token := ""
+	for {
+		results, next, err := lib.SearchCatalog(ctx, entity.SearchFilter{Query: "dune"}, 2, token)
+		require.NoError(t, err)
+
+		for _, result := range results {
+			require.False(t, seen[result.ID])
+			require.Contains(t, result.Snippet, "<b>Dune</b>")
+			seen[result.ID] = true
+		}
+
+		if next == "" {
+			break
+		}
+		token = next
+	}
+
+	require.Len(t, seen, 5)
+}
@@ -20,8 +20,13 @@ import (
 
 	"github.com/project/library/config"
 	"github.com/project/library/db"
+	"github.com/project/library/generated/api/admin"
+	generatedcatalog "github.com/project/library/generated/api/catalog"
+	generatedinbox "github.com/project/library/generated/api/inbox"
 	generated "github.com/project/library/generated/api/library"
 	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/usecase/catalog"
+	"github.com/project/library/internal/usecase/inbox"
 	"github.com/project/library/internal/usecase/library"
 	"github.com/project/library/internal/usecase/repository"
 )
@@ -29,6 +34,9 @@ import (
 const (
 	gracefulShutdownTimeout = 5 * time.Second
 	tableMetricsInterval    = time.Minute
+	holdExpiryInterval      = time.Hour
+	outboxRetentionInterval = 10 * time.Minute
+	catalogPollInterval     = 5 * time.Second
 )
 
 func Run(
@@ -62,14 +70,60 @@ func Run(
 
 	repo := repository.NewPostgresRepository(dbPool, logger)
 	outboxRepository := repository.NewOutbox(dbPool, logger)
+	inboxRepository := repository.NewInbox(dbPool, logger)
 	transactor := repository.NewTransactor(dbPool, logger)
-	runOutbox(ctx, cfg, logger, outboxRepository, transactor)
+	if err = runOutbox(ctx, cfg, logger, outboxRepository, transactor); err != nil {
+		logger.Error("can not start outbox", zap.Error(err))
+		return
+	}
 
-	useCases := library.New(logger, repo, repo, outboxRepository, transactor)
-	ctrl := controller.New(logger, useCases, useCases)
+	useCases := library.New(logger, library.Repositories{
+		Author:     repo,
+		Books:      repo,
+		Genre:      repo,
+		Copy:       repo,
+		Patron:     repo,
+		Loan:       repo,
+		Hold:       repo,
+		Fine:       repo,
+		Outbox:     outboxRepository,
+		Transactor: transactor,
+	})
+	ctrl := controller.New(logger, controller.UseCases{
+		Books:  useCases,
+		Author: useCases,
+		Genre:  useCases,
+		Copy:   useCases,
+		Patron: useCases,
+		Loan:   useCases,
+		Hold:   useCases,
+		Fine:   useCases,
+	})
+	adminCtrl := controller.NewAdmin(logger, useCases)
+
+	inboxService := inbox.New(logger, inboxRepository, inboxHandler(useCases), cfg, transactor)
+	if cfg.Inbox.Enabled {
+		inboxService.Start(ctx, cfg.Inbox.Workers, cfg.Inbox.BatchSize,
+			cfg.Inbox.WaitTimeMS, cfg.Inbox.InProgressTTLMS)
+	}
+	inboxCtrl := controller.NewInbox(logger, inboxService)
 
+	catalogNotifier := repository.NewCatalogNotifier(dbPool, logger)
+	go catalogNotifier.Run(ctx)
+	catalogService := catalog.New(logger, repository.NewCatalogChanges(dbPool, logger),
+		catalogNotifier, catalogPollInterval)
+	catalogCtrl := controller.NewCatalog(logger, catalogService)
+
+	go runHoldExpiry(ctx, logger, useCases, holdExpiryInterval)
+	if cfg.Outbox.Enabled && cfg.Outbox.RetentionHours > 0 {
+		go runOutboxRetention(ctx, logger, useCases, cfg.Outbox.RetentionHours, outboxRetentionInterval)
+	}
+	if cfg.Fines.Enabled {
+		leader := repository.NewLeaderElector(dbPool, logger, fineAccrualLockKey)
+		go runFineAccrual(ctx, logger, useCases, leader, cfg.Fines)
+	}
 	go runRest(ctx, cfg, logger)
-	go runGrpc(cfg, logger, ctrl)
+	go runGrpc(cfg, logger, ctrl, adminCtrl, inboxCtrl, catalogCtrl)
 
 	tables := []string{"author", "book", "author_book"}
 	go startTableMetricsCollector(ctx, dbPool, tables, tableMetricsInterval)
@@ -83,7 +137,12 @@ func runRest(
 	cfg *config.Config,
 	logger *zap.Logger,
 ) {
-	mux := grpcruntime.NewServeMux()
+	mux := grpcruntime.NewServeMux(
+		grpcruntime.WithIncomingHeaderMatcher(gatewayHeaderMatcher),
+		grpcruntime.WithOutgoingHeaderMatcher(gatewayOutgoingHeaderMatcher),
+		grpcruntime.WithErrorHandler(gatewayErrorHandler),
+		grpcruntime.WithMetadata(gatewayMetadata),
+	)
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
 
 	address := "localhost:" + cfg.GRPC.Port
@@ -93,6 +152,24 @@ func runRest(
 		return
 	}
 
+	err = admin.RegisterAdminServiceHandlerFromEndpoint(ctx, mux, address, opts)
+	if err != nil {
+		logger.Error("can not register admin grpc gateway", zap.Error(err))
+		return
+	}
+
+	err = generatedinbox.RegisterInboxServiceHandlerFromEndpoint(ctx, mux, address, opts)
+	if err != nil {
+		logger.Error("can not register inbox grpc gateway", zap.Error(err))
+		return
+	}
+
+	err = generatedcatalog.RegisterCatalogServiceHandlerFromEndpoint(ctx, mux, address, opts)
+	if err != nil {
+		logger.Error("can not register catalog grpc gateway", zap.Error(err))
+		return
+	}
+
 	gatewayPort := ":" + cfg.GatewayPort
 	logger.Info("gateway listening at port",
 		zap.String("port", gatewayPort))
@@ -105,6 +182,9 @@ func runGrpc(
 	cfg *config.Config,
 	logger *zap.Logger,
 	libraryService generated.LibraryServer,
+	adminService admin.AdminServiceServer,
+	inboxService generatedinbox.InboxServiceServer,
+	catalogService generatedcatalog.CatalogServiceServer,
 ) {
 	port := ":" + cfg.GRPC.Port
 	lis, err := net.Listen("tcp", port)
@@ -119,11 +199,18 @@ func runGrpc(
 				otelgrpc.WithTracerProvider(otel.GetTracerProvider()),
 			),
 		),
-		grpc.UnaryInterceptor(grpcMetricsInterceptor),
+		grpc.ChainUnaryInterceptor(
+			grpcMetricsInterceptor,
+			adminInterceptor(cfg.Admin.Token),
+			inboxInterceptor(cfg.Inbox.Token),
+		),
 	)
 	reflection.Register(s)
 
 	generated.RegisterLibraryServer(s, libraryService)
+	admin.RegisterAdminServiceServer(s, adminService)
+	generatedinbox.RegisterInboxServiceServer(s, inboxService)
+	generatedcatalog.RegisterCatalogServiceServer(s, catalogService)
 	logger.Info("grpc server listening at port", zap.String("port", port))
 
 	if err = s.Serve(lis); err != nil {
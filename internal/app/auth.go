@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/admin"
+	"github.com/project/library/generated/api/inbox"
+)
+
+const (
+	adminTokenHeader = "x-admin-token"
+	inboxTokenHeader = "x-inbox-token"
+)
+
+var (
+	// adminMethodPrefix matches every method of the admin service.
+	adminMethodPrefix = "/" + admin.AdminService_ServiceDesc.ServiceName + "/"
+	// inboxMethodPrefix matches every method of the inbox service.
+	inboxMethodPrefix = "/" + inbox.InboxService_ServiceDesc.ServiceName + "/"
+)
+
+// purgeRequest is implemented by requests that can remove data permanently.
+type purgeRequest interface {
+	GetPurge() bool
+}
+
+func adminInterceptor(adminToken string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, adminMethodPrefix) && !isAdmin(ctx, adminToken) {
+			return nil, status.Error(codes.PermissionDenied, "admin service requires admin token")
+		}
+
+		if r, ok := req.(purgeRequest); ok && r.GetPurge() && !isAdmin(ctx, adminToken) {
+			return nil, status.Error(codes.PermissionDenied, "purge requires admin token")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// inboxInterceptor lets only the services holding the inbox token send
+// events to the inbox.
+func inboxInterceptor(inboxToken string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, inboxMethodPrefix) && !hasToken(ctx, inboxTokenHeader, inboxToken) {
+			return nil, status.Error(codes.PermissionDenied, "inbox service requires inbox token")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func isAdmin(ctx context.Context, adminToken string) bool {
+	return hasToken(ctx, adminTokenHeader, adminToken)
+}
+
+// hasToken tells whether header carries want, which is never the case
+// when want isn't configured.
+func hasToken(ctx context.Context, header string, want string) bool {
+	if want == "" {
+		return false
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+
+	for _, token := range md.Get(header) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/project/library/config"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+// fineAccrualLockKey is the advisory lock the replicas compete for to
+// accrue fines. It must not be used by any other advisory lock.
+const fineAccrualLockKey int64 = 0x66696e6573
+
+// runFineAccrual periodically accrues the fines of overdue loans and
+// reminds their patrons. Only the replica holding the advisory lock does
+// the work; the others take over once its connection goes away.
+func runFineAccrual(
+	ctx context.Context,
+	logger *zap.Logger,
+	fineUseCase library.FineUseCase,
+	leader repository.LeaderElector,
+	cfg config.Fines,
+) {
+	policy := entity.FinePolicy{
+		DailyRate:  cfg.DailyRate,
+		MaxPerLoan: cfg.MaxPerLoan,
+		GraceDays:  cfg.GraceDays,
+	}
+
+	ticker := time.NewTicker(cfg.IntervalMS)
+	defer ticker.Stop()
+	defer leader.Resign(context.Background())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			isLeader, err := leader.TryLead(ctx)
+			if err != nil {
+				logger.Error("can not elect fine accrual leader", zap.Error(err))
+			}
+			if !isLeader {
+				continue
+			}
+
+			accrued, err := fineUseCase.AccrueFines(ctx, policy)
+			if err != nil {
+				logger.Error("can not accrue fines", zap.Error(err))
+			}
+			if accrued > 0 {
+				logger.Info("accrued fines", zap.Int("count", accrued))
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	grpcruntime "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ifMatchHeader     = "if-match"
+	etagHeader        = "etag"
+	patchFieldsHeader = "x-patch-fields"
+)
+
+func gatewayHeaderMatcher(key string) (string, bool) {
+	switch {
+	case strings.EqualFold(key, adminTokenHeader):
+		return adminTokenHeader, true
+	case strings.EqualFold(key, inboxTokenHeader):
+		return inboxTokenHeader, true
+	case strings.EqualFold(key, ifMatchHeader):
+		return ifMatchHeader, true
+	}
+
+	return grpcruntime.DefaultHeaderMatcher(key)
+}
+
+func gatewayOutgoingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, etagHeader) {
+		return "ETag", true
+	}
+
+	return grpcruntime.MetadataHeaderPrefix + key, true
+}
+
+// gatewayMetadata passes the top-level fields of a PATCH body on, so that
+// a partial update without update_mask changes only the fields sent.
+func gatewayMetadata(_ context.Context, r *http.Request) metadata.MD {
+	if r.Method != http.MethodPatch || r.Body == nil {
+		return nil
+	}
+
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil
+	}
+	r.Body = io.NopCloser(bytes.NewReader(raw))
+
+	// A malformed body is reported by the handler decoding it.
+	var body map[string]json.RawMessage
+	if len(bytes.TrimSpace(raw)) > 0 && json.Unmarshal(raw, &body) != nil {
+		return nil
+	}
+
+	fields := make([]string, 0, len(body))
+	for field := range body {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	return metadata.Pairs(patchFieldsHeader, strings.Join(fields, ","))
+}
+
+// gatewayErrorHandler answers 412 instead of 409 to a version conflict
+// when the client made the request conditional with If-Match.
+func gatewayErrorHandler(
+	ctx context.Context,
+	mux *grpcruntime.ServeMux,
+	marshaler grpcruntime.Marshaler,
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+) {
+	if status.Code(err) == codes.Aborted && r.Header.Get(ifMatchHeader) != "" {
+		err = &grpcruntime.HTTPStatusError{
+			HTTPStatus: http.StatusPreconditionFailed,
+			Err:        err,
+		}
+	}
+
+	grpcruntime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/usecase/library"
+)
+
+// runHoldExpiry periodically expires the ready holds nobody picked up, so
+// their copies move on to the next patron in the queue.
+func runHoldExpiry(
+	ctx context.Context,
+	logger *zap.Logger,
+	holdUseCase library.HoldUseCase,
+	interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := holdUseCase.ExpireHolds(ctx)
+			if err != nil {
+				logger.Error("can not expire holds", zap.Error(err))
+			}
+			if expired > 0 {
+				logger.Info("expired holds", zap.Int("count", expired))
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/internal/usecase/inbox"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+// inboxHandler routes every inbox kind to the use case that applies it.
+func inboxHandler(inboxUseCase library.InboxUseCase) inbox.GlobalHandler {
+	return func(kind repository.InboxKind) (inbox.KindHandler, error) {
+		switch kind {
+		case repository.InboxKindBookPurchased:
+			return applyInboxEvent(inboxUseCase.ApplyBookPurchased), nil
+		case repository.InboxKindAuthorMerged:
+			return applyInboxEvent(inboxUseCase.ApplyAuthorMerged), nil
+		default:
+			return nil, fmt.Errorf("unsupported inbox kind: %s", kind)
+		}
+	}
+}
+
+func applyInboxEvent[T any](apply func(ctx context.Context, event T) error) inbox.KindHandler {
+	return func(ctx context.Context, message repository.InboxData) error {
+		var event T
+		if err := json.Unmarshal(message.RawData, &event); err != nil {
+			return status.Errorf(codes.InvalidArgument, "can not decode %s: %v", message.Kind, err)
+		}
+
+		return apply(ctx, event)
+	}
+}
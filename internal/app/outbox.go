@@ -1,17 +1,18 @@
 package app
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
 	"time"
 
+	"github.com/nats-io/nats.go"
 	"github.com/project/library/config"
 	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/publisher"
 	"github.com/project/library/internal/usecase/outbox"
 	"github.com/project/library/internal/usecase/repository"
 	"go.uber.org/zap"
@@ -33,7 +34,11 @@ func runOutbox(
 	logger *zap.Logger,
 	outboxRepository repository.OutboxRepository,
 	transactor repository.Transactor,
-) {
+) error {
+	if !cfg.Outbox.Enabled {
+		return nil
+	}
+
 	dialer := &net.Dialer{
 		Timeout:   Timeout,
 		KeepAlive: KeepAlive,
@@ -49,12 +54,18 @@ func runOutbox(
 		ExpectContinueTimeout: ExpectContinueTimeout,
 	}
 
-	client := &http.Client{Transport: transport}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Outbox.DeliveryTimeoutMS,
+	}
+
+	router, err := newOutboxRouter(cfg.Outbox, client)
+	if err != nil {
+		return err
+	}
 
-	globalHandler := globalOutboxHandler(
-		client, cfg.Outbox.BookSendURL, cfg.Outbox.AuthorSendURL)
 	outboxService := outbox.New(
-		logger, outboxRepository, globalHandler, cfg, transactor)
+		logger, outboxRepository, router.handler, cfg, transactor)
 
 	outboxService.Start(
 		ctx,
@@ -63,71 +74,250 @@ func runOutbox(
 		cfg.Outbox.WaitTimeMS,
 		cfg.Outbox.InProgressTTLMS,
 	)
+
+	go func() {
+		<-ctx.Done()
+		if err := router.Close(); err != nil {
+			logger.Error("can not close outbox publishers", zap.Error(err))
+		}
+	}()
+
+	return nil
 }
 
-func globalOutboxHandler(
-	client *http.Client,
-	bookURL string,
-	authorURL string,
-) outbox.GlobalHandler {
-	return func(kind repository.OutboxKind) (outbox.KindHandler, error) {
-		switch kind {
-		case repository.OutboxKindBook:
-			return bookOutboxHandler(client, bookURL), nil
-		case repository.OutboxKindAuthor:
-			return authorOutboxHandler(client, authorURL), nil
-		default:
-			return nil, fmt.Errorf("unsupported outbox kind: %d", kind)
+// outboxRouter picks the publisher of every outbox kind.
+type outboxRouter struct {
+	cfg        config.Outbox
+	client     *http.Client
+	signer     *publisher.Signer
+	publishers map[repository.OutboxKind]publisher.Publisher
+	// byDestination shares a publisher between the kinds sent to the
+	// same place.
+	byDestination map[string]publisher.Publisher
+}
+
+func newOutboxRouter(cfg config.Outbox, client *http.Client) (*outboxRouter, error) {
+	overrides := make(map[repository.OutboxKind]string, len(cfg.Publishers))
+	for name, publisherName := range cfg.Publishers {
+		kind, ok := repository.ParseOutboxKind(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown outbox kind in publishers: '%s'", name)
 		}
+		overrides[kind] = publisherName
 	}
-}
 
-func bookOutboxHandler(client *http.Client, url string) outbox.KindHandler {
-	return func(ctx context.Context, data []byte) error {
-		book := entity.Book{}
-		err := json.Unmarshal(data, &book)
+	router := &outboxRouter{
+		cfg:           cfg,
+		client:        client,
+		publishers:    make(map[repository.OutboxKind]publisher.Publisher),
+		byDestination: make(map[string]publisher.Publisher),
+	}
 
+	if len(cfg.HTTPSigningKeys) > 0 {
+		signer, err := publisher.NewSigner(cfg.HTTPSigningKeys...)
 		if err != nil {
-			return fmt.Errorf("can not deserialize data in book outbox handler: %w", err)
+			return nil, err
+		}
+		router.signer = signer
+	}
+
+	for _, kind := range repository.OutboxKinds() {
+		publisherName := cfg.Publisher
+		if override, ok := overrides[kind]; ok {
+			publisherName = override
 		}
 
-		return send(ctx, client, []byte(book.ID), url)
+		p, err := router.publisher(publisherName, kind)
+		if err != nil {
+			_ = router.Close()
+			return nil, err
+		}
+		router.publishers[kind] = p
 	}
+
+	return router, nil
 }
 
-func authorOutboxHandler(client *http.Client, url string) outbox.KindHandler {
-	return func(ctx context.Context, data []byte) error {
-		author := entity.Author{}
-		err := json.Unmarshal(data, &author)
+func (r *outboxRouter) publisher(name string, kind repository.OutboxKind) (publisher.Publisher, error) {
+	destination := name
+	if name == publisher.HTTP {
+		destination += " " + r.webhookURL(kind)
+	}
+
+	if p, ok := r.byDestination[destination]; ok {
+		return p, nil
+	}
 
+	var (
+		p   publisher.Publisher
+		err error
+	)
+	switch name {
+	case publisher.HTTP:
+		p = publisher.NewHTTP(r.client, r.webhookURL(kind), r.cfg.HTTPBody, r.signer, r.cloudEvents(name))
+	case publisher.Kafka:
+		p = publisher.NewKafka(publisher.NewKafkaWriter(r.cfg.KafkaBrokers, r.cfg.KafkaTopic), r.cloudEvents(name))
+	case publisher.NATS:
+		var conn *nats.Conn
+		conn, err = nats.Connect(r.cfg.NATSURL, nats.Name("library"), nats.MaxReconnects(-1))
+		if err != nil {
+			return nil, fmt.Errorf("can not connect to nats: %w", err)
+		}
+		p = publisher.NewNATS(conn, r.cfg.NATSSubject, r.cloudEvents(name))
+	case publisher.File:
+		p, err = publisher.NewFile(r.cfg.FilePath, r.cloudEvents(name))
 		if err != nil {
-			return fmt.Errorf("can not deserialize data in book outbox handler: %w", err)
+			return nil, err
 		}
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher: '%s'", name)
+	}
+
+	r.byDestination[destination] = p
+
+	return p, nil
+}
 
-		return send(ctx, client, []byte(author.ID), url)
+// cloudEvents returns the CloudEvents encoding of the publisher, or nil if
+// it sends messages in its own format.
+func (r *outboxRouter) cloudEvents(name string) *publisher.CloudEvents {
+	mode, ok := r.cfg.CloudEvents[name]
+	if !ok {
+		return nil
+	}
+
+	return &publisher.CloudEvents{
+		Source: r.cfg.CloudEventsSource,
+		Mode:   mode,
 	}
 }
-func send(ctx context.Context, client *http.Client, body []byte, url string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+
+// webhookURL keeps the split of webhooks into the book and the author
+// ones.
+func (r *outboxRouter) webhookURL(kind repository.OutboxKind) string {
+	switch kind {
+	case repository.OutboxKindAuthor,
+		repository.OutboxKindAuthorDeleted,
+		repository.OutboxKindAuthorRestored,
+		repository.OutboxKindAuthorPurged,
+		repository.OutboxKindAuthorUpdated:
+		return r.cfg.AuthorSendURL
+	default:
+		return r.cfg.BookSendURL
 	}
-	req.Header.Set("Content-Type", "application/json")
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+func (r *outboxRouter) handler(kind repository.OutboxKind) (outbox.KindHandler, error) {
+	p, ok := r.publishers[kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported outbox kind: %d", kind)
 	}
-	defer resp.Body.Close()
 
-	if !checkSuccessResponse(resp) {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("non success response: %d, body: %s", resp.StatusCode, string(respBody))
+	return func(ctx context.Context, message repository.OutboxData) error {
+		// Messages stored before aggregates were have their key decoded
+		// from the payload.
+		key := message.AggregateKey
+		if key == "" {
+			var err error
+			if key, err = messageKey(kind, message.RawData); err != nil {
+				return err
+			}
+		}
+
+		return p.Publish(ctx, publisher.Message{
+			Key:            key,
+			Kind:           kind.String(),
+			Type:           kind.EventType(),
+			IdempotencyKey: message.IdempotencyKey,
+			TraceID:        message.TraceID,
+			Sequence:       message.Sequence,
+			OccurredAt:     message.CreatedAt,
+			Body:           message.RawData,
+		})
+	}, nil
+}
+
+func (r *outboxRouter) Close() error {
+	var errs []error
+	for _, p := range r.byDestination {
+		errs = append(errs, p.Close())
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-func checkSuccessResponse(resp *http.Response) bool {
-	return resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices
+// messageKey is the ID of the entity the message is about. Copy, loan and
+// hold events change the availability of a book, so they are keyed by the
+// book: the changes of one book's availability stay in order. Changes
+// carry the entity in their after state.
+func messageKey(kind repository.OutboxKind, data []byte) (string, error) {
+	switch kind {
+	case repository.OutboxKindBook,
+		repository.OutboxKindBookDeleted,
+		repository.OutboxKindBookRestored,
+		repository.OutboxKindBookPurged:
+		return decodeKey(kind, data, func(book *entity.Book) string { return book.ID })
+	case repository.OutboxKindBookUpdated:
+		return decodeKey(kind, data, func(change *entity.BookChange) string {
+			if change.After == nil {
+				return ""
+			}
+
+			return change.After.ID
+		})
+	case repository.OutboxKindAuthor,
+		repository.OutboxKindAuthorDeleted,
+		repository.OutboxKindAuthorRestored,
+		repository.OutboxKindAuthorPurged:
+		return decodeKey(kind, data, func(author *entity.Author) string { return author.ID })
+	case repository.OutboxKindAuthorUpdated:
+		return decodeKey(kind, data, func(change *entity.AuthorChange) string {
+			if change.After == nil {
+				return ""
+			}
+
+			return change.After.ID
+		})
+	case repository.OutboxKindCopy,
+		repository.OutboxKindCopyDeleted:
+		return decodeKey(kind, data, func(bookCopy *entity.Copy) string { return bookCopy.BookID })
+	case repository.OutboxKindCopyUpdated:
+		return decodeKey(kind, data, func(change *entity.CopyChange) string {
+			if change.After == nil {
+				return ""
+			}
+
+			return change.After.BookID
+		})
+	case repository.OutboxKindLoanCheckedOut,
+		repository.OutboxKindLoanReturned,
+		repository.OutboxKindLoanRenewed:
+		return decodeKey(kind, data, func(loan *entity.Loan) string { return loan.BookID })
+	case repository.OutboxKindHoldReady:
+		return decodeKey(kind, data, func(hold *entity.Hold) string { return hold.BookID })
+	case repository.OutboxKindLoanOverdue:
+		return decodeKey(kind, data, func(notice *entity.OverdueNotice) string {
+			if notice.Loan == nil {
+				return ""
+			}
+
+			return notice.Loan.BookID
+		})
+	default:
+		return "", fmt.Errorf("unsupported outbox kind: %d", kind)
+	}
+}
+
+func decodeKey[T any](kind repository.OutboxKind, data []byte, id func(*T) string) (string, error) {
+	var payload T
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", fmt.Errorf("can not deserialize data in %s outbox message: %w", kind, err)
+	}
+
+	key := id(&payload)
+	if key == "" {
+		return "", fmt.Errorf("%s outbox message has no entity id", kind)
+	}
+
+	return key, nil
 }
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/usecase/library"
+)
+
+// runOutboxRetention periodically deletes the outbox messages delivered
+// more than retention ago. Replicas can run it at the same time: each
+// batch skips the rows another replica is deleting.
+func runOutboxRetention(
+	ctx context.Context,
+	logger *zap.Logger,
+	outboxAdminUseCase library.OutboxAdminUseCase,
+	retention time.Duration,
+	interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := outboxAdminUseCase.PurgeOutbox(ctx, retention)
+			if err != nil {
+				logger.Error("can not purge outbox", zap.Error(err))
+			}
+			if deleted > 0 {
+				logger.Info("purged outbox", zap.Int64("count", deleted))
+			}
+		}
+	}
+}
@@ -8,9 +8,9 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
 )
 
 func (i *impl) AddBook(
@@ -37,7 +37,18 @@ func (i *impl) AddBook(
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	book, err := i.booksUseCase.AddBook(ctx, req.GetName(), req.GetAuthorId())
+	book, err := i.booksUseCase.AddBook(ctx, &entity.Book{
+		Name:            req.GetName(),
+		AuthorIDs:       req.GetAuthorId(),
+		GenreIDs:        req.GetGenreIds(),
+		Tags:            req.GetTags(),
+		ISBN:            req.GetIsbn(),
+		Publisher:       req.GetPublisher(),
+		PublicationYear: int(req.GetPublicationYear()),
+		Language:        req.GetLanguage(),
+		PageCount:       int(req.GetPageCount()),
+		Description:     req.GetDescription(),
+	})
 	if err != nil {
 		return nil, i.handleError(span, err, "AddBook")
 	}
@@ -46,12 +57,6 @@ func (i *impl) AddBook(
 	span.SetAttributes(attribute.String("book.id", book.ID))
 
 	return &library.AddBookResponse{
-		Book: &library.Book{
-			Id:        book.ID,
-			Name:      book.Name,
-			AuthorId:  book.AuthorIDs,
-			CreatedAt: timestamppb.New(book.CreatedAt),
-			UpdatedAt: timestamppb.New(book.UpdatedAt),
-		},
+		Book: newBook(book),
 	}, nil
 }
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+)
+
+func (i *impl) AddCopy(
+	ctx context.Context,
+	req *library.AddCopyRequest,
+) (*library.AddCopyResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("book.id", req.GetBookId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("book_id", req.GetBookId()),
+	)
+
+	log.Info("start AddCopy")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	acquiredOn, err := parseDate("acquired_on", req.GetAcquiredOn())
+	if err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	bookCopy, err := i.copyUseCase.AddCopy(ctx, &entity.Copy{
+		BookID:        req.GetBookId(),
+		Barcode:       req.GetBarcode(),
+		Branch:        req.GetBranch(),
+		ShelfLocation: req.GetShelfLocation(),
+		Condition:     copyConditionFromProto[req.GetCondition()],
+		AcquiredOn:    acquiredOn,
+		Status:        copyStatusFromProto[req.GetStatus()],
+	})
+	if err != nil {
+		return nil, i.handleError(span, err, "AddCopy")
+	}
+
+	log.Info("successfully finished AddCopy")
+
+	return &library.AddCopyResponse{
+		Copy: newCopy(bookCopy),
+	}, nil
+}
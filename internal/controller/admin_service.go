@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/project/library/generated/api/admin"
+	"github.com/project/library/internal/usecase/library"
+)
+
+var _ admin.AdminServiceServer = (*adminImpl)(nil)
+
+type adminImpl struct {
+	admin.UnimplementedAdminServiceServer
+	logger             *zap.Logger
+	outboxAdminUseCase library.OutboxAdminUseCase
+}
+
+func NewAdmin(
+	logger *zap.Logger,
+	outboxAdminUseCase library.OutboxAdminUseCase,
+) *adminImpl {
+	return &adminImpl{
+		logger:             logger,
+		outboxAdminUseCase: outboxAdminUseCase,
+	}
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) CancelHold(
+	ctx context.Context,
+	req *library.CancelHoldRequest,
+) (*library.CancelHoldResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("hold.id", req.GetId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("hold_id", req.GetId()),
+	)
+
+	log.Info("start CancelHold")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	hold, err := i.holdUseCase.CancelHold(ctx, req.GetId())
+	if err != nil {
+		return nil, i.handleError(span, err, "CancelHold")
+	}
+
+	log.Info("successfully finished CancelHold")
+
+	return &library.CancelHoldResponse{
+		Hold: newHold(hold),
+	}, nil
+}
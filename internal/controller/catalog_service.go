@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"go.uber.org/zap"
+
+	generated "github.com/project/library/generated/api/catalog"
+	"github.com/project/library/internal/usecase/catalog"
+)
+
+var _ generated.CatalogServiceServer = (*catalogImpl)(nil)
+
+type catalogImpl struct {
+	generated.UnimplementedCatalogServiceServer
+	logger  *zap.Logger
+	catalog catalog.Catalog
+}
+
+func NewCatalog(
+	logger *zap.Logger,
+	catalog catalog.Catalog,
+) *catalogImpl {
+	return &catalogImpl{
+		logger:  logger,
+		catalog: catalog,
+	}
+}
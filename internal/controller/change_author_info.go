@@ -2,6 +2,8 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -10,6 +12,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
 )
 
 func (i *impl) ChangeAuthorInfo(
@@ -37,12 +40,82 @@ func (i *impl) ChangeAuthorInfo(
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	err := i.authorUseCase.ChangeAuthor(ctx, req.GetId(), req.GetName())
+	update, err := newAuthorUpdate(req)
+	if err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	expected, err := expectedVersion(ctx, req.GetExpectedVersion())
+	if err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, err
+	}
+
+	author, err := i.authorUseCase.ChangeAuthor(ctx, req.GetId(), update, expected)
 	if err != nil {
 		return nil, i.handleError(span, err, "ChangeAuthorInfo")
 	}
 
+	i.setETag(ctx, author.Version)
+
 	log.Info("successfully finished ChangeAuthorInfo")
 
-	return &library.ChangeAuthorInfoResponse{}, nil
+	return &library.ChangeAuthorInfoResponse{
+		Version: author.Version,
+	}, nil
+}
+
+const (
+	authorNamePath           = "name"
+	authorBirthDatePath      = "birth_date"
+	authorDeathDatePath      = "death_date"
+	authorNationalityPath    = "nationality"
+	authorBiographyPath      = "biography"
+	authorAlternateNamesPath = "alternate_names"
+)
+
+// newAuthorUpdate applies the update_mask rules of ChangeAuthorInfoRequest.
+func newAuthorUpdate(req *library.ChangeAuthorInfoRequest) (entity.AuthorUpdate, error) {
+	var update entity.AuthorUpdate
+
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = []string{authorNamePath}
+	}
+
+	for _, path := range paths {
+		var err error
+
+		switch path {
+		case authorNamePath:
+			if req.GetName() == "" {
+				return entity.AuthorUpdate{}, errors.New("name must not be empty")
+			}
+			update.Name = ptr(req.GetName())
+		case authorBirthDatePath:
+			update.BirthDate, err = parseDate(authorBirthDatePath, req.GetBirthDate())
+			update.SetBirthDate = true
+		case authorDeathDatePath:
+			update.DeathDate, err = parseDate(authorDeathDatePath, req.GetDeathDate())
+			update.SetDeathDate = true
+		case authorNationalityPath:
+			update.Nationality = ptr(req.GetNationality())
+		case authorBiographyPath:
+			update.Biography = ptr(req.GetBiography())
+		case authorAlternateNamesPath:
+			update.AlternateNames = req.GetAlternateNames()
+			update.SetAlternateNames = true
+		default:
+			return entity.AuthorUpdate{}, fmt.Errorf("unknown update_mask path %q", path)
+		}
+
+		if err != nil {
+			return entity.AuthorUpdate{}, err
+		}
+	}
+
+	return update, nil
 }
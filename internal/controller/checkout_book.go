@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) CheckoutBook(
+	ctx context.Context,
+	req *library.CheckoutBookRequest,
+) (*library.CheckoutBookResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(
+		attribute.String("copy.id", req.GetCopyId()),
+		attribute.String("patron.id", req.GetPatronId()),
+	)
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("copy_id", req.GetCopyId()),
+		zap.String("patron_id", req.GetPatronId()),
+	)
+
+	log.Info("start CheckoutBook")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	loan, err := i.loanUseCase.CheckoutBook(ctx, req.GetCopyId(), req.GetPatronId())
+	if err != nil {
+		return nil, i.handleError(span, err, "CheckoutBook")
+	}
+
+	log.Info("successfully finished CheckoutBook")
+
+	return &library.CheckoutBookResponse{
+		Loan: newLoan(loan),
+	}, nil
+}
@@ -0,0 +1,285 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/project/library/generated/api/admin"
+	"github.com/project/library/generated/api/catalog"
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func newBook(book *entity.Book) *library.Book {
+	return &library.Book{
+		Id:              book.ID,
+		Name:            book.Name,
+		AuthorId:        book.AuthorIDs,
+		GenreIds:        book.GenreIDs,
+		Tags:            book.Tags,
+		Isbn:            book.ISBN,
+		Publisher:       book.Publisher,
+		PublicationYear: int32(book.PublicationYear),
+		Language:        book.Language,
+		PageCount:       int32(book.PageCount),
+		Description:     book.Description,
+		CreatedAt:       timestamppb.New(book.CreatedAt),
+		UpdatedAt:       timestamppb.New(book.UpdatedAt),
+		DeletedAt:       optionalTimestamp(book.DeletedAt),
+		Version:         book.Version,
+	}
+}
+
+func newAuthor(author *entity.Author) *library.Author {
+	return &library.Author{
+		Id:        author.ID,
+		Name:      author.Name,
+		BookCount: int64(author.BookCount),
+	}
+}
+
+func newGenre(genre *entity.Genre) *library.Genre {
+	return &library.Genre{
+		Id:        genre.ID,
+		Name:      genre.Name,
+		ParentId:  genre.ParentID,
+		CreatedAt: timestamppb.New(genre.CreatedAt),
+	}
+}
+
+func newCopy(bookCopy *entity.Copy) *library.Copy {
+	return &library.Copy{
+		Id:            bookCopy.ID,
+		BookId:        bookCopy.BookID,
+		Barcode:       bookCopy.Barcode,
+		Branch:        bookCopy.Branch,
+		ShelfLocation: bookCopy.ShelfLocation,
+		Condition:     copyConditionToProto[bookCopy.Condition],
+		AcquiredOn:    formatDate(bookCopy.AcquiredOn),
+		Status:        copyStatusToProto[bookCopy.Status],
+		CreatedAt:     timestamppb.New(bookCopy.CreatedAt),
+		UpdatedAt:     timestamppb.New(bookCopy.UpdatedAt),
+	}
+}
+
+func newBookAvailability(availability *entity.BookAvailability) *library.BookAvailability {
+	return &library.BookAvailability{
+		Total:     int64(availability.Total),
+		Available: int64(availability.Available),
+		OnLoan:    int64(availability.OnLoan),
+		OnHold:    int64(availability.OnHold),
+		Lost:      int64(availability.Lost),
+		InRepair:  int64(availability.InRepair),
+	}
+}
+
+func newPatron(patron *entity.Patron) *library.Patron {
+	patronStatus := library.PatronStatus_PATRON_STATUS_ACTIVE
+	if patron.Status == entity.PatronStatusBlocked {
+		patronStatus = library.PatronStatus_PATRON_STATUS_BLOCKED
+	}
+
+	return &library.Patron{
+		Id:                  patron.ID,
+		CardNumber:          patron.CardNumber,
+		Name:                patron.Name,
+		Email:               patron.Email,
+		Phone:               patron.Phone,
+		Address:             patron.Address,
+		MembershipExpiresOn: formatDate(&patron.MembershipExpiresOn),
+		Status:              patronStatus,
+		BlockReason:         patron.BlockReason,
+		CreatedAt:           timestamppb.New(patron.CreatedAt),
+		UpdatedAt:           timestamppb.New(patron.UpdatedAt),
+	}
+}
+
+func newLoan(loan *entity.Loan) *library.Loan {
+	return &library.Loan{
+		Id:           loan.ID,
+		CopyId:       loan.CopyID,
+		BookId:       loan.BookID,
+		PatronId:     loan.PatronID,
+		CheckedOutAt: timestamppb.New(loan.CheckedOutAt),
+		DueOn:        formatDate(&loan.DueOn),
+		ReturnedAt:   optionalTimestamp(loan.ReturnedAt),
+		Renewals:     int32(loan.Renewals),
+	}
+}
+
+func newFine(fine *entity.Fine) *library.Fine {
+	return &library.Fine{
+		Id:          fine.ID,
+		LoanId:      fine.LoanID,
+		PatronId:    fine.PatronID,
+		Amount:      fine.Amount,
+		Paid:        fine.Paid,
+		Outstanding: fine.Outstanding(),
+		CreatedAt:   timestamppb.New(fine.CreatedAt),
+		UpdatedAt:   timestamppb.New(fine.UpdatedAt),
+	}
+}
+
+func newHold(hold *entity.Hold) *library.Hold {
+	return &library.Hold{
+		Id:            hold.ID,
+		BookId:        hold.BookID,
+		PatronId:      hold.PatronID,
+		CopyId:        hold.CopyID,
+		Status:        holdStatusToProto[hold.Status],
+		QueuePosition: int32(hold.QueuePosition),
+		PlacedAt:      timestamppb.New(hold.PlacedAt),
+		ReadyAt:       optionalTimestamp(hold.ReadyAt),
+		ExpiresOn:     formatDate(hold.ExpiresOn),
+	}
+}
+
+// Unspecified enum values map to the empty status and condition.
+var (
+	copyStatusFromProto = map[library.CopyStatus]entity.CopyStatus{
+		library.CopyStatus_COPY_STATUS_AVAILABLE: entity.CopyStatusAvailable,
+		library.CopyStatus_COPY_STATUS_ON_LOAN:   entity.CopyStatusOnLoan,
+		library.CopyStatus_COPY_STATUS_LOST:      entity.CopyStatusLost,
+		library.CopyStatus_COPY_STATUS_IN_REPAIR: entity.CopyStatusInRepair,
+		library.CopyStatus_COPY_STATUS_ON_HOLD:   entity.CopyStatusOnHold,
+	}
+	copyStatusToProto = invert(copyStatusFromProto)
+
+	holdStatusToProto = map[entity.HoldStatus]library.HoldStatus{
+		entity.HoldStatusWaiting:   library.HoldStatus_HOLD_STATUS_WAITING,
+		entity.HoldStatusReady:     library.HoldStatus_HOLD_STATUS_READY,
+		entity.HoldStatusFulfilled: library.HoldStatus_HOLD_STATUS_FULFILLED,
+		entity.HoldStatusCancelled: library.HoldStatus_HOLD_STATUS_CANCELLED,
+		entity.HoldStatusExpired:   library.HoldStatus_HOLD_STATUS_EXPIRED,
+	}
+
+	copyConditionFromProto = map[library.CopyCondition]entity.CopyCondition{
+		library.CopyCondition_COPY_CONDITION_NEW:     entity.CopyConditionNew,
+		library.CopyCondition_COPY_CONDITION_GOOD:    entity.CopyConditionGood,
+		library.CopyCondition_COPY_CONDITION_FAIR:    entity.CopyConditionFair,
+		library.CopyCondition_COPY_CONDITION_POOR:    entity.CopyConditionPoor,
+		library.CopyCondition_COPY_CONDITION_DAMAGED: entity.CopyConditionDamaged,
+	}
+	copyConditionToProto = invert(copyConditionFromProto)
+
+	outboxStatusFromProto = map[admin.OutboxStatus]repository.OutboxStatus{
+		admin.OutboxStatus_OUTBOX_STATUS_CREATED:     repository.OutboxStatusCreated,
+		admin.OutboxStatus_OUTBOX_STATUS_IN_PROGRESS: repository.OutboxStatusInProgress,
+		admin.OutboxStatus_OUTBOX_STATUS_SUCCESS:     repository.OutboxStatusSuccess,
+		admin.OutboxStatus_OUTBOX_STATUS_FAILED:      repository.OutboxStatusFailed,
+		admin.OutboxStatus_OUTBOX_STATUS_DEAD:        repository.OutboxStatusDead,
+	}
+	outboxStatusToProto = invert(outboxStatusFromProto)
+)
+
+func invert[K, V comparable](m map[K]V) map[V]K {
+	inverted := make(map[V]K, len(m))
+	for k, v := range m {
+		inverted[v] = k
+	}
+
+	return inverted
+}
+
+func newOutboxMessage(message *repository.OutboxMessage) *admin.OutboxMessage {
+	return &admin.OutboxMessage{
+		IdempotencyKey: message.IdempotencyKey,
+		Kind:           message.Kind.String(),
+		Status:         outboxStatusToProto[message.Status],
+		Data:           string(message.RawData),
+		TraceId:        message.TraceID,
+		Attempts:       int32(message.Attempts),
+		NextAttemptAt:  timestamppb.New(message.NextAttemptAt),
+		LastError:      message.LastError,
+		CreatedAt:      timestamppb.New(message.CreatedAt),
+		UpdatedAt:      timestamppb.New(message.UpdatedAt),
+	}
+}
+
+func newCatalogChange(change *repository.CatalogChange, resumeToken string) *catalog.CatalogChange {
+	return &catalog.CatalogChange{
+		ResumeToken: resumeToken,
+		Type:        change.Kind.EventType(),
+		EntityId:    change.EntityID,
+		Data:        string(change.RawData),
+		TraceId:     change.TraceID,
+		OccurredAt:  timestamppb.New(change.CreatedAt),
+	}
+}
+
+func newSearchResult(result *entity.SearchResult) *library.SearchResult {
+	kind := library.SearchResultKind_SEARCH_RESULT_KIND_BOOK
+	if result.Kind == entity.SearchResultAuthor {
+		kind = library.SearchResultKind_SEARCH_RESULT_KIND_AUTHOR
+	}
+
+	return &library.SearchResult{
+		Kind:    kind,
+		Id:      result.ID,
+		Name:    result.Name,
+		Snippet: result.Snippet,
+		Score:   result.Score,
+	}
+}
+
+func newSortOrder(sortOrder library.SortOrder) entity.SortOrder {
+	if sortOrder == library.SortOrder_SORT_ORDER_DESC {
+		return entity.SortOrderDesc
+	}
+
+	return entity.SortOrderAsc
+}
+
+func newNameMatch(nameMatch library.NameMatch) entity.NameMatch {
+	if nameMatch == library.NameMatch_NAME_MATCH_SUBSTRING {
+		return entity.NameMatchSubstring
+	}
+
+	return entity.NameMatchPrefix
+}
+
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+
+	t := ts.AsTime()
+	return &t
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+// parseDate reads a YYYY-MM-DD date; an empty string means no date.
+func parseDate(field string, date string) (*time.Time, error) {
+	if date == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", field, err)
+	}
+
+	return &t, nil
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.DateOnly)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+)
+
+func (i *impl) CreateGenre(
+	ctx context.Context,
+	req *library.CreateGenreRequest,
+) (*library.CreateGenreResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("parent_id", req.GetParentId()),
+	)
+
+	log.Info("start CreateGenre")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	genre, err := i.genreUseCase.CreateGenre(ctx, &entity.Genre{
+		Name:     req.GetName(),
+		ParentID: req.GetParentId(),
+	})
+	if err != nil {
+		return nil, i.handleError(span, err, "CreateGenre")
+	}
+
+	log.Info("successfully finished CreateGenre", zap.String("genre_id", genre.ID))
+	span.SetAttributes(attribute.String("genre.id", genre.ID))
+
+	return &library.CreateGenreResponse{
+		Genre: newGenre(genre),
+	}, nil
+}
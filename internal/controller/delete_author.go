@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) DeleteAuthor(
+	ctx context.Context,
+	req *library.DeleteAuthorRequest,
+) (*library.DeleteAuthorResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(
+		attribute.String("author.id", req.GetId()),
+		attribute.Bool("purge", req.GetPurge()),
+	)
+
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("author_id", req.GetId()),
+		zap.Bool("purge", req.GetPurge()),
+	)
+
+	log.Info("start DeleteAuthor")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err := i.authorUseCase.DeleteAuthor(ctx, req.GetId(), req.GetPurge())
+	if err != nil {
+		return nil, i.handleError(span, err, "DeleteAuthor")
+	}
+
+	log.Info("successfully finished DeleteAuthor")
+
+	return &library.DeleteAuthorResponse{}, nil
+}
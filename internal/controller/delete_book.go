@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) DeleteBook(
+	ctx context.Context,
+	req *library.DeleteBookRequest,
+) (*library.DeleteBookResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(
+		attribute.String("book.id", req.GetId()),
+		attribute.Bool("purge", req.GetPurge()),
+	)
+
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("book_id", req.GetId()),
+		zap.Bool("purge", req.GetPurge()),
+	)
+
+	log.Info("start DeleteBook")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err := i.booksUseCase.DeleteBook(ctx, req.GetId(), req.GetPurge())
+	if err != nil {
+		return nil, i.handleError(span, err, "DeleteBook")
+	}
+
+	log.Info("successfully finished DeleteBook")
+
+	return &library.DeleteBookResponse{}, nil
+}
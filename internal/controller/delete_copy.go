@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) DeleteCopy(
+	ctx context.Context,
+	req *library.DeleteCopyRequest,
+) (*library.DeleteCopyResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("copy.id", req.GetId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("copy_id", req.GetId()),
+	)
+
+	log.Info("start DeleteCopy")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := i.copyUseCase.DeleteCopy(ctx, req.GetId()); err != nil {
+		return nil, i.handleError(span, err, "DeleteCopy")
+	}
+
+	log.Info("successfully finished DeleteCopy")
+
+	return &library.DeleteCopyResponse{}, nil
+}
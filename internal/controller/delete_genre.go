@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) DeleteGenre(
+	ctx context.Context,
+	req *library.DeleteGenreRequest,
+) (*library.DeleteGenreResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("genre.id", req.GetId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("genre_id", req.GetId()),
+	)
+
+	log.Info("start DeleteGenre")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := i.genreUseCase.DeleteGenre(ctx, req.GetId()); err != nil {
+		return nil, i.handleError(span, err, "DeleteGenre")
+	}
+
+	log.Info("successfully finished DeleteGenre")
+
+	return &library.DeleteGenreResponse{}, nil
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ifMatchHeader = "if-match"
+	etagHeader    = "etag"
+)
+
+var errInvalidIfMatch = status.Error(codes.InvalidArgument, "invalid If-Match header")
+
+// formatETag renders a version as a strong HTTP entity tag.
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// expectedVersion returns the version an update is conditioned on.
+// An explicit expected_version wins over the If-Match metadata.
+func expectedVersion(ctx context.Context, version int64) (int64, error) {
+	if version != 0 {
+		return version, nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+
+	values := md.Get(ifMatchHeader)
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	tag := strings.TrimSpace(values[0])
+	if tag == "*" {
+		return 0, nil
+	}
+
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, errInvalidIfMatch
+	}
+
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+
+	return version, nil
+}
+
+// setETag sends the version back as an etag header. It is best effort:
+// there is no stream to attach it to when the handler is called directly.
+func (i *impl) setETag(ctx context.Context, version int64) {
+	if err := grpc.SetHeader(ctx, metadata.Pairs(etagHeader, formatETag(version))); err != nil {
+		i.logger.Debug("failed to set etag header", zap.Error(err))
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/project/library/generated/api/library"
 )
@@ -36,19 +35,13 @@ func (i *impl) GetAuthorBooks(
 		return status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	books, err := i.authorUseCase.GetAuthorBooks(server.Context(), req.GetAuthorId())
+	books, err := i.authorUseCase.GetAuthorBooks(server.Context(), req.GetAuthorId(), req.GetShowDeleted())
 	if err != nil {
 		return i.handleError(span, err, "GetAuthorBooks")
 	}
 
 	for _, book := range books {
-		err = server.Send(&library.Book{
-			Id:        book.ID,
-			Name:      book.Name,
-			AuthorId:  book.AuthorIDs,
-			CreatedAt: timestamppb.New(book.CreatedAt),
-			UpdatedAt: timestamppb.New(book.UpdatedAt),
-		})
+		err = server.Send(newBook(book))
 		if err != nil {
 			log.Error("failed to send book in stream",
 				zap.Error(err),
@@ -41,10 +41,18 @@ func (i *impl) GetAuthorInfo(
 		return nil, i.handleError(span, err, "GetAuthorInfo")
 	}
 
+	i.setETag(ctx, author.Version)
+
 	log.Info("successfully finished GetAuthorInfo")
 
 	return &library.GetAuthorInfoResponse{
-		Id:   author.ID,
-		Name: author.Name,
+		Id:             author.ID,
+		Name:           author.Name,
+		Version:        author.Version,
+		BirthDate:      formatDate(author.BirthDate),
+		DeathDate:      formatDate(author.DeathDate),
+		Nationality:    author.Nationality,
+		Biography:      author.Biography,
+		AlternateNames: author.AlternateNames,
 	}, nil
 }
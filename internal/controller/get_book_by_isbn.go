@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) GetBookByISBN(
+	ctx context.Context,
+	req *library.GetBookByISBNRequest,
+) (*library.GetBookByISBNResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("book.isbn", req.GetIsbn()))
+
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("isbn", req.GetIsbn()),
+	)
+
+	log.Info("start GetBookByISBN")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	book, err := i.booksUseCase.GetBookByISBN(ctx, req.GetIsbn())
+	if err != nil {
+		return nil, i.handleError(span, err, "GetBookByISBN")
+	}
+
+	i.setETag(ctx, book.Version)
+
+	log.Info("successfully finished GetBookByISBN")
+
+	return &library.GetBookByISBNResponse{
+		Book: newBook(book),
+	}, nil
+}
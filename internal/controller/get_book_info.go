@@ -8,7 +8,6 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/project/library/generated/api/library"
 )
@@ -38,20 +37,22 @@ func (i *impl) GetBookInfo(
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	book, err := i.booksUseCase.GetBook(ctx, req.GetId())
+	book, err := i.booksUseCase.GetBook(ctx, req.GetId(), req.GetShowDeleted())
 	if err != nil {
 		return nil, i.handleError(span, err, "GetBookInfo")
 	}
 
+	availability, err := i.copyUseCase.GetBookAvailability(ctx, book.ID)
+	if err != nil {
+		return nil, i.handleError(span, err, "GetBookInfo")
+	}
+
+	i.setETag(ctx, book.Version)
+
 	log.Info("successfully finished GetBookInfo")
 
 	return &library.GetBookInfoResponse{
-		Book: &library.Book{
-			Id:        book.ID,
-			Name:      book.Name,
-			AuthorId:  book.AuthorIDs,
-			CreatedAt: timestamppb.New(book.CreatedAt),
-			UpdatedAt: timestamppb.New(book.UpdatedAt),
-		},
+		Book:         newBook(book),
+		Availability: newBookAvailability(availability),
 	}, nil
 }
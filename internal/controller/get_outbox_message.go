@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/admin"
+)
+
+func (a *adminImpl) GetOutboxMessage(
+	ctx context.Context,
+	req *admin.GetOutboxMessageRequest,
+) (*admin.GetOutboxMessageResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("outbox.idempotency_key", req.GetIdempotencyKey()))
+	defer span.End()
+
+	log := a.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("idempotency_key", req.GetIdempotencyKey()),
+	)
+
+	log.Info("start GetOutboxMessage")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	message, err := a.outboxAdminUseCase.GetOutboxMessage(ctx, req.GetIdempotencyKey())
+	if err != nil {
+		return nil, a.handleError(span, err, "GetOutboxMessage")
+	}
+
+	log.Info("successfully finished GetOutboxMessage")
+
+	return &admin.GetOutboxMessageResponse{
+		Message: newOutboxMessage(message),
+	}, nil
+}
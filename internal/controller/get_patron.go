@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) GetPatron(
+	ctx context.Context,
+	req *library.GetPatronRequest,
+) (*library.GetPatronResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("patron.id", req.GetId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("patron_id", req.GetId()),
+	)
+
+	log.Info("start GetPatron")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	patron, err := i.patronUseCase.GetPatron(ctx, req.GetId())
+	if err != nil {
+		return nil, i.handleError(span, err, "GetPatron")
+	}
+
+	log.Info("successfully finished GetPatron")
+
+	return &library.GetPatronResponse{
+		Patron: newPatron(patron),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) GetPatronAccount(
+	ctx context.Context,
+	req *library.GetPatronAccountRequest,
+) (*library.GetPatronAccountResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("patron.id", req.GetId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("patron_id", req.GetId()),
+	)
+
+	log.Info("start GetPatronAccount")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	account, err := i.fineUseCase.GetPatronAccount(ctx, req.GetId())
+	if err != nil {
+		return nil, i.handleError(span, err, "GetPatronAccount")
+	}
+
+	log.Info("successfully finished GetPatronAccount", zap.Int("fines", len(account.Fines)))
+
+	response := &library.GetPatronAccountResponse{
+		Patron:  newPatron(account.Patron),
+		Fines:   make([]*library.Fine, 0, len(account.Fines)),
+		Balance: account.Balance,
+	}
+	for _, fine := range account.Fines {
+		response.Fines = append(response.Fines, newFine(fine))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) GetPatronByCard(
+	ctx context.Context,
+	req *library.GetPatronByCardRequest,
+) (*library.GetPatronByCardResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+	)
+
+	log.Info("start GetPatronByCard")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	patron, err := i.patronUseCase.GetPatronByCardNumber(ctx, req.GetCardNumber())
+	if err != nil {
+		return nil, i.handleError(span, err, "GetPatronByCard")
+	}
+
+	log.Info("successfully finished GetPatronByCard", zap.String("patron_id", patron.ID))
+
+	return &library.GetPatronByCardResponse{
+		Patron: newPatron(patron),
+	}, nil
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"go.uber.org/zap"
+
+	generated "github.com/project/library/generated/api/inbox"
+	"github.com/project/library/internal/usecase/inbox"
+)
+
+var _ generated.InboxServiceServer = (*inboxImpl)(nil)
+
+type inboxImpl struct {
+	generated.UnimplementedInboxServiceServer
+	logger *zap.Logger
+	inbox  inbox.Inbox
+}
+
+func NewInbox(
+	logger *zap.Logger,
+	inbox inbox.Inbox,
+) *inboxImpl {
+	return &inboxImpl{
+		logger: logger,
+		inbox:  inbox,
+	}
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/inbox"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func (i *inboxImpl) IngestEvent(
+	ctx context.Context,
+	req *inbox.IngestEventRequest,
+) (*inbox.IngestEventResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("inbox.message_id", req.GetMessageId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("message_id", req.GetMessageId()),
+	)
+
+	log.Info("start IngestEvent")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var (
+		kind  repository.InboxKind
+		event any
+	)
+
+	switch e := req.GetEvent().(type) {
+	case *inbox.IngestEventRequest_BookPurchased:
+		kind = repository.InboxKindBookPurchased
+		event = entity.BookPurchased{
+			Name:            e.BookPurchased.GetName(),
+			AuthorIDs:       e.BookPurchased.GetAuthorId(),
+			ISBN:            e.BookPurchased.GetIsbn(),
+			Publisher:       e.BookPurchased.GetPublisher(),
+			PublicationYear: int(e.BookPurchased.GetPublicationYear()),
+			Language:        e.BookPurchased.GetLanguage(),
+			PageCount:       int(e.BookPurchased.GetPageCount()),
+			Description:     e.BookPurchased.GetDescription(),
+		}
+
+	case *inbox.IngestEventRequest_AuthorMerged:
+		if e.AuthorMerged.GetSourceId() == e.AuthorMerged.GetTargetId() {
+			return nil, i.handleError(span, entity.ErrAuthorMergedIntoItself, "IngestEvent")
+		}
+
+		kind = repository.InboxKindAuthorMerged
+		event = entity.AuthorMerged{
+			SourceID: e.AuthorMerged.GetSourceId(),
+			TargetID: e.AuthorMerged.GetTargetId(),
+		}
+	}
+
+	span.SetAttributes(attribute.String("inbox.kind", kind.String()))
+
+	received, err := i.inbox.Receive(ctx, req.GetMessageId(), kind, event)
+	if err != nil {
+		return nil, i.handleError(span, err, "IngestEvent")
+	}
+
+	log.Info("successfully finished IngestEvent",
+		zap.String("kind", kind.String()),
+		zap.Bool("duplicate", !received))
+
+	return &inbox.IngestEventResponse{
+		Duplicate: !received,
+	}, nil
+}
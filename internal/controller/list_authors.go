@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+)
+
+func (i *impl) ListAuthors(
+	ctx context.Context,
+	req *library.ListAuthorsRequest,
+) (*library.ListAuthorsResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+	)
+
+	log.Info("start ListAuthors")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := entity.AuthorsFilter{
+		NameQuery: req.GetNameQuery(),
+		NameMatch: newNameMatch(req.GetNameMatch()),
+	}
+
+	authors, nextPageToken, err := i.authorUseCase.ListAuthors(ctx, filter,
+		int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, i.handleError(span, err, "ListAuthors")
+	}
+
+	log.Info("successfully finished ListAuthors", zap.Int("count", len(authors)))
+
+	response := &library.ListAuthorsResponse{
+		Authors:       make([]*library.Author, 0, len(authors)),
+		NextPageToken: nextPageToken,
+	}
+	for _, author := range authors {
+		response.Authors = append(response.Authors, newAuthor(author))
+	}
+
+	return response, nil
+}
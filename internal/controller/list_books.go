@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+)
+
+func (i *impl) ListBooks(
+	ctx context.Context,
+	req *library.ListBooksRequest,
+) (*library.ListBooksResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+	)
+
+	log.Info("start ListBooks")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := entity.BooksFilter{
+		NamePrefix:    req.GetNamePrefix(),
+		AuthorID:      req.GetAuthorId(),
+		GenreID:       req.GetGenreId(),
+		Tag:           req.GetTag(),
+		CreatedAfter:  optionalTime(req.GetCreatedAfter()),
+		CreatedBefore: optionalTime(req.GetCreatedBefore()),
+		UpdatedAfter:  optionalTime(req.GetUpdatedAfter()),
+		UpdatedBefore: optionalTime(req.GetUpdatedBefore()),
+		SortOrder:     newSortOrder(req.GetSortOrder()),
+	}
+
+	books, nextPageToken, err := i.booksUseCase.ListBooks(ctx, filter,
+		int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, i.handleError(span, err, "ListBooks")
+	}
+
+	log.Info("successfully finished ListBooks", zap.Int("count", len(books)))
+
+	response := &library.ListBooksResponse{
+		Books:         make([]*library.Book, 0, len(books)),
+		NextPageToken: nextPageToken,
+	}
+	for _, book := range books {
+		response.Books = append(response.Books, newBook(book))
+	}
+
+	return response, nil
+}
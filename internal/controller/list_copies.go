@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+)
+
+func (i *impl) ListCopies(
+	ctx context.Context,
+	req *library.ListCopiesRequest,
+) (*library.ListCopiesResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("book.id", req.GetBookId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("book_id", req.GetBookId()),
+	)
+
+	log.Info("start ListCopies")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	copies, err := i.copyUseCase.ListCopies(ctx, req.GetBookId(), entity.CopiesFilter{
+		Branch: req.GetBranch(),
+		Status: copyStatusFromProto[req.GetStatus()],
+	})
+	if err != nil {
+		return nil, i.handleError(span, err, "ListCopies")
+	}
+
+	log.Info("successfully finished ListCopies", zap.Int("count", len(copies)))
+
+	response := &library.ListCopiesResponse{
+		Copies: make([]*library.Copy, 0, len(copies)),
+	}
+	for _, bookCopy := range copies {
+		response.Copies = append(response.Copies, newCopy(bookCopy))
+	}
+
+	return response, nil
+}
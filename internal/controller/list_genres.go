@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) ListGenres(
+	ctx context.Context,
+	req *library.ListGenresRequest,
+) (*library.ListGenresResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("root_id", req.GetRootId()),
+	)
+
+	log.Info("start ListGenres")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	genres, err := i.genreUseCase.ListGenres(ctx, req.GetRootId())
+	if err != nil {
+		return nil, i.handleError(span, err, "ListGenres")
+	}
+
+	log.Info("successfully finished ListGenres", zap.Int("count", len(genres)))
+
+	response := &library.ListGenresResponse{
+		Genres: make([]*library.Genre, 0, len(genres)),
+	}
+	for _, genre := range genres {
+		response.Genres = append(response.Genres, newGenre(genre))
+	}
+
+	return response, nil
+}
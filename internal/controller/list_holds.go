@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+)
+
+func (i *impl) ListHolds(
+	ctx context.Context,
+	req *library.ListHoldsRequest,
+) (*library.ListHoldsResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(
+		attribute.String("book.id", req.GetBookId()),
+		attribute.String("patron.id", req.GetPatronId()),
+	)
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("book_id", req.GetBookId()),
+		zap.String("patron_id", req.GetPatronId()),
+	)
+
+	log.Info("start ListHolds")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	holds, err := i.holdUseCase.ListHolds(ctx, entity.HoldsFilter{
+		BookID:   req.GetBookId(),
+		PatronID: req.GetPatronId(),
+	})
+	if err != nil {
+		return nil, i.handleError(span, err, "ListHolds")
+	}
+
+	log.Info("successfully finished ListHolds", zap.Int("count", len(holds)))
+
+	response := &library.ListHoldsResponse{
+		Holds: make([]*library.Hold, 0, len(holds)),
+	}
+	for _, hold := range holds {
+		response.Holds = append(response.Holds, newHold(hold))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/admin"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func (a *adminImpl) ListOutboxMessages(
+	ctx context.Context,
+	req *admin.ListOutboxMessagesRequest,
+) (*admin.ListOutboxMessagesResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	defer span.End()
+
+	log := a.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+	)
+
+	log.Info("start ListOutboxMessages")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := repository.OutboxFilter{
+		Status: outboxStatusFromProto[req.GetStatus()],
+	}
+	if req.GetKind() != "" {
+		kind, ok := repository.ParseOutboxKind(req.GetKind())
+		if !ok {
+			log.Warn("invalid data", zap.String("kind", req.GetKind()))
+			return nil, entity.ErrInvalidOutboxKind
+		}
+		filter.Kind = kind
+	}
+	if req.GetCreatedAfter() != nil {
+		filter.CreatedAfter = req.GetCreatedAfter().AsTime()
+	}
+	if req.GetCreatedBefore() != nil {
+		filter.CreatedBefore = req.GetCreatedBefore().AsTime()
+	}
+
+	messages, nextPageToken, err := a.outboxAdminUseCase.ListOutboxMessages(ctx, filter,
+		int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, a.handleError(span, err, "ListOutboxMessages")
+	}
+
+	log.Info("successfully finished ListOutboxMessages", zap.Int("count", len(messages)))
+
+	response := &admin.ListOutboxMessagesResponse{
+		Messages:      make([]*admin.OutboxMessage, 0, len(messages)),
+		NextPageToken: nextPageToken,
+	}
+	for _, message := range messages {
+		response.Messages = append(response.Messages, newOutboxMessage(message))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) PayFine(
+	ctx context.Context,
+	req *library.PayFineRequest,
+) (*library.PayFineResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("fine.id", req.GetId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("fine_id", req.GetId()),
+		zap.Int64("amount", req.GetAmount()),
+	)
+
+	log.Info("start PayFine")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	fine, err := i.fineUseCase.PayFine(ctx, req.GetId(), req.GetAmount())
+	if err != nil {
+		return nil, i.handleError(span, err, "PayFine")
+	}
+
+	log.Info("successfully finished PayFine")
+
+	return &library.PayFineResponse{
+		Fine: newFine(fine),
+	}, nil
+}
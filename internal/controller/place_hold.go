@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) PlaceHold(
+	ctx context.Context,
+	req *library.PlaceHoldRequest,
+) (*library.PlaceHoldResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(
+		attribute.String("book.id", req.GetBookId()),
+		attribute.String("patron.id", req.GetPatronId()),
+	)
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("book.id", req.GetBookId()),
+		zap.String("patron_id", req.GetPatronId()),
+	)
+
+	log.Info("start PlaceHold")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	hold, err := i.holdUseCase.PlaceHold(ctx, req.GetBookId(), req.GetPatronId())
+	if err != nil {
+		return nil, i.handleError(span, err, "PlaceHold")
+	}
+
+	log.Info("successfully finished PlaceHold")
+
+	return &library.PlaceHoldResponse{
+		Hold: newHold(hold),
+	}, nil
+}
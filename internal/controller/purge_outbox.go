@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/admin"
+)
+
+func (a *adminImpl) PurgeOutbox(
+	ctx context.Context,
+	req *admin.PurgeOutboxRequest,
+) (*admin.PurgeOutboxResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	defer span.End()
+
+	log := a.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.Duration("older_than", req.GetOlderThan().AsDuration()),
+	)
+
+	log.Info("start PurgeOutbox")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	deleted, err := a.outboxAdminUseCase.PurgeOutbox(ctx, req.GetOlderThan().AsDuration())
+	if err != nil {
+		return nil, a.handleError(span, err, "PurgeOutbox")
+	}
+
+	log.Info("successfully finished PurgeOutbox", zap.Int64("deleted", deleted))
+
+	return &admin.PurgeOutboxResponse{
+		Deleted: deleted,
+	}, nil
+}
@@ -10,6 +10,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
 )
 
 func (i *impl) RegisterAuthor(
@@ -33,7 +34,14 @@ func (i *impl) RegisterAuthor(
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	author, err := i.authorUseCase.RegisterAuthor(ctx, req.GetName())
+	newAuthor, err := newAuthorFromRequest(req)
+	if err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	author, err := i.authorUseCase.RegisterAuthor(ctx, newAuthor)
 	if err != nil {
 		return nil, i.handleError(span, err, "RegisterAuthor")
 	}
@@ -45,3 +53,24 @@ func (i *impl) RegisterAuthor(
 		Id: author.ID,
 	}, nil
 }
+
+func newAuthorFromRequest(req *library.RegisterAuthorRequest) (*entity.Author, error) {
+	birthDate, err := parseDate("birth_date", req.GetBirthDate())
+	if err != nil {
+		return nil, err
+	}
+
+	deathDate, err := parseDate("death_date", req.GetDeathDate())
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.Author{
+		Name:           req.GetName(),
+		BirthDate:      birthDate,
+		DeathDate:      deathDate,
+		Nationality:    req.GetNationality(),
+		Biography:      req.GetBiography(),
+		AlternateNames: req.GetAlternateNames(),
+	}, nil
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+)
+
+func (i *impl) RegisterPatron(
+	ctx context.Context,
+	req *library.RegisterPatronRequest,
+) (*library.RegisterPatronResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+	)
+
+	log.Info("start RegisterPatron")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	patron, err := newPatronFromRequest(req)
+	if err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	patron, err = i.patronUseCase.RegisterPatron(ctx, patron)
+	if err != nil {
+		return nil, i.handleError(span, err, "RegisterPatron")
+	}
+
+	log.Info("successfully finished RegisterPatron", zap.String("patron_id", patron.ID))
+
+	return &library.RegisterPatronResponse{
+		Patron: newPatron(patron),
+	}, nil
+}
+
+func newPatronFromRequest(req *library.RegisterPatronRequest) (*entity.Patron, error) {
+	patron := &entity.Patron{
+		Name:    req.GetName(),
+		Email:   req.GetEmail(),
+		Phone:   req.GetPhone(),
+		Address: req.GetAddress(),
+	}
+
+	expiresOn, err := parseDate("membership_expires_on", req.GetMembershipExpiresOn())
+	if err != nil {
+		return nil, err
+	}
+	if expiresOn != nil {
+		patron.MembershipExpiresOn = *expiresOn
+	}
+
+	return patron, nil
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/project/library/generated/api/admin"
+)
+
+func (a *adminImpl) RetryAllDead(
+	ctx context.Context,
+	_ *admin.RetryAllDeadRequest,
+) (*admin.RetryAllDeadResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	defer span.End()
+
+	log := a.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+	)
+
+	log.Info("start RetryAllDead")
+
+	retried, err := a.outboxAdminUseCase.RetryAllDead(ctx)
+	if err != nil {
+		return nil, a.handleError(span, err, "RetryAllDead")
+	}
+
+	log.Info("successfully finished RetryAllDead", zap.Int64("retried", retried))
+
+	return &admin.RetryAllDeadResponse{
+		Retried: retried,
+	}, nil
+}
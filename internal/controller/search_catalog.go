@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+)
+
+func (i *impl) SearchCatalog(
+	ctx context.Context,
+	req *library.SearchCatalogRequest,
+) (*library.SearchCatalogResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+	)
+
+	log.Info("start SearchCatalog")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := entity.SearchFilter{
+		Query:   req.GetQuery(),
+		GenreID: req.GetGenreId(),
+	}
+
+	results, nextPageToken, err := i.booksUseCase.SearchCatalog(ctx, filter,
+		int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, i.handleError(span, err, "SearchCatalog")
+	}
+
+	log.Info("successfully finished SearchCatalog", zap.Int("count", len(results)))
+
+	response := &library.SearchCatalogResponse{
+		Results:       make([]*library.SearchResult, 0, len(results)),
+		NextPageToken: nextPageToken,
+	}
+	for _, result := range results {
+		response.Results = append(response.Results, newSearchResult(result))
+	}
+
+	return response, nil
+}
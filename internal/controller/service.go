@@ -14,16 +14,40 @@ type impl struct {
 	logger        *zap.Logger
 	booksUseCase  library.BooksUseCase
 	authorUseCase library.AuthorUseCase
+	genreUseCase  library.GenreUseCase
+	copyUseCase   library.CopyUseCase
+	patronUseCase library.PatronUseCase
+	loanUseCase   library.LoanUseCase
+	holdUseCase   library.HoldUseCase
+	fineUseCase   library.FineUseCase
+}
+
+// UseCases are the use cases the service calls. The ones a caller has no
+// use for may be left nil.
+type UseCases struct {
+	Books  library.BooksUseCase
+	Author library.AuthorUseCase
+	Genre  library.GenreUseCase
+	Copy   library.CopyUseCase
+	Patron library.PatronUseCase
+	Loan   library.LoanUseCase
+	Hold   library.HoldUseCase
+	Fine   library.FineUseCase
 }
 
 func New(
 	logger *zap.Logger,
-	booksUseCase library.BooksUseCase,
-	authorUseCase library.AuthorUseCase,
+	useCases UseCases,
 ) *impl {
 	return &impl{
 		logger:        logger,
-		booksUseCase:  booksUseCase,
-		authorUseCase: authorUseCase,
+		booksUseCase:  useCases.Books,
+		authorUseCase: useCases.Author,
+		genreUseCase:  useCases.Genre,
+		copyUseCase:   useCases.Copy,
+		patronUseCase: useCases.Patron,
+		loanUseCase:   useCases.Loan,
+		holdUseCase:   useCases.Hold,
+		fineUseCase:   useCases.Fine,
 	}
 }
@@ -48,6 +48,46 @@ func Test_AddBook(t *testing.T) {
 			wantErrCode: codes.OK,
 			mocksUsed:   true,
 		},
+		{
+			name: "add book | with metadata",
+			args: args{
+				req: &library.AddBookRequest{
+					Name:            "book",
+					AuthorId:        make([]string, 0),
+					Isbn:            "0-306-40615-2",
+					Publisher:       "Publisher",
+					PublicationYear: 1999,
+					Language:        "ru",
+					PageCount:       320,
+					Description:     "description",
+				},
+			},
+			want: &entity.Book{
+				ID:              uuid.NewString(),
+				Name:            "book",
+				AuthorIDs:       make([]string, 0),
+				ISBN:            "9780306406157",
+				Publisher:       "Publisher",
+				PublicationYear: 1999,
+				Language:        "ru",
+				PageCount:       320,
+				Description:     "description",
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "add book | with invalid language",
+			args: args{
+				req: &library.AddBookRequest{
+					Name:     "book",
+					AuthorId: make([]string, 0),
+					Language: "Russian",
+				},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
 		{
 			name: "add book | with err",
 			args: args{
@@ -94,14 +134,21 @@ func Test_AddBook(t *testing.T) {
 			t.Cleanup(ctrl.Finish)
 
 			logger, _ := zap.NewProduction()
-			authorUseCase := mocks.NewMockAuthorUseCase(ctrl)
 			bookUseCase := mocks.NewMockBooksUseCase(ctrl)
-			service := controller.New(logger, bookUseCase, authorUseCase)
+			service := controller.New(logger, controller.UseCases{Books: bookUseCase})
 			ctx := t.Context()
 
 			if tt.mocksUsed {
-				bookUseCase.EXPECT().AddBook(ctx, tt.args.req.GetName(),
-					tt.args.req.GetAuthorId()).
+				bookUseCase.EXPECT().AddBook(ctx, &entity.Book{
+					Name:            tt.args.req.GetName(),
+					AuthorIDs:       tt.args.req.GetAuthorId(),
+					ISBN:            tt.args.req.GetIsbn(),
+					Publisher:       tt.args.req.GetPublisher(),
+					PublicationYear: int(tt.args.req.GetPublicationYear()),
+					Language:        tt.args.req.GetLanguage(),
+					PageCount:       int(tt.args.req.GetPageCount()),
+					Description:     tt.args.req.GetDescription(),
+				}).
 					Return(tt.want, tt.wantErr)
 			}
 
@@ -112,6 +159,9 @@ func Test_AddBook(t *testing.T) {
 				assert.Equal(t, tt.want.ID, got.GetBook().GetId())
 				assert.Equal(t, tt.want.Name, got.GetBook().GetName())
 				assert.Equal(t, tt.want.AuthorIDs, got.GetBook().GetAuthorId())
+				assert.Equal(t, tt.want.ISBN, got.GetBook().GetIsbn())
+				assert.Equal(t, tt.want.PublicationYear, int(got.GetBook().GetPublicationYear()))
+				assert.Equal(t, tt.want.Language, got.GetBook().GetLanguage())
 			}
 		})
 	}
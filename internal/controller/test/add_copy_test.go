@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_AddCopy(t *testing.T) {
+	t.Parallel()
+
+	bookID := uuid.NewString()
+
+	tests := []struct {
+		name           string
+		req            *library.AddCopyRequest
+		wantCondition  entity.CopyCondition
+		wantStatus     entity.CopyStatus
+		wantAcquiredOn string
+		wantErrCode    codes.Code
+		wantErr        error
+		mocksUsed      bool
+	}{
+		{
+			name: "add copy",
+			req: &library.AddCopyRequest{
+				BookId:        bookID,
+				Barcode:       "LIB-000123",
+				Branch:        "Central",
+				ShelfLocation: "A-12",
+				Condition:     library.CopyCondition_COPY_CONDITION_NEW,
+				AcquiredOn:    "2023-09-01",
+			},
+			wantCondition:  entity.CopyConditionNew,
+			wantAcquiredOn: "2023-09-01",
+			wantErrCode:    codes.OK,
+			mocksUsed:      true,
+		},
+		{
+			name: "add copy | in repair",
+			req: &library.AddCopyRequest{
+				BookId:  bookID,
+				Barcode: "LIB-000124",
+				Branch:  "Central",
+				Status:  library.CopyStatus_COPY_STATUS_IN_REPAIR,
+			},
+			wantStatus:  entity.CopyStatusInRepair,
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "add copy | barcode already exists",
+			req: &library.AddCopyRequest{
+				BookId:  bookID,
+				Barcode: "LIB-000123",
+				Branch:  "Central",
+			},
+			wantErrCode: codes.AlreadyExists,
+			wantErr:     entity.ErrBarcodeAlreadyExists,
+			mocksUsed:   true,
+		},
+		{
+			name: "add copy | book not found",
+			req: &library.AddCopyRequest{
+				BookId:  bookID,
+				Barcode: "LIB-000123",
+				Branch:  "Central",
+			},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrBookNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name: "add copy | invalid barcode",
+			req: &library.AddCopyRequest{
+				BookId:  bookID,
+				Barcode: "LIB 000123",
+				Branch:  "Central",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "add copy | empty branch",
+			req: &library.AddCopyRequest{
+				BookId:  bookID,
+				Barcode: "LIB-000123",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "add copy | invalid acquisition date",
+			req: &library.AddCopyRequest{
+				BookId:     bookID,
+				Barcode:    "LIB-000123",
+				Branch:     "Central",
+				AcquiredOn: "2023-02-30",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			copyUseCase := mocks.NewMockCopyUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Copy: copyUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				copyUseCase.EXPECT().AddCopy(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, bookCopy *entity.Copy) (*entity.Copy, error) {
+						assert.Equal(t, tt.req.GetBookId(), bookCopy.BookID)
+						assert.Equal(t, tt.req.GetBarcode(), bookCopy.Barcode)
+						assert.Equal(t, tt.req.GetBranch(), bookCopy.Branch)
+						assert.Equal(t, tt.req.GetShelfLocation(), bookCopy.ShelfLocation)
+						assert.Equal(t, tt.wantCondition, bookCopy.Condition)
+						assert.Equal(t, tt.wantStatus, bookCopy.Status)
+						assert.Equal(t, tt.wantAcquiredOn, formatDate(bookCopy.AcquiredOn))
+						if tt.wantErr != nil {
+							return nil, tt.wantErr
+						}
+
+						bookCopy.ID = uuid.NewString()
+						return bookCopy, nil
+					})
+			}
+
+			got, err := service.AddCopy(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.NotEmpty(t, got.GetCopy().GetId())
+				assert.Equal(t, tt.req.GetBarcode(), got.GetCopy().GetBarcode())
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_BlockPatron(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.BlockPatronRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "block patron",
+			req: &library.BlockPatronRequest{
+				Id:     uuid.NewString(),
+				Reason: "unpaid fines",
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "block patron | not found",
+			req:         &library.BlockPatronRequest{Id: uuid.NewString()},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrPatronNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "block patron | invalid id",
+			req:         &library.BlockPatronRequest{Id: "patron"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			patronUseCase := mocks.NewMockPatronUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Patron: patronUseCase})
+			ctx := t.Context()
+
+			var want *entity.Patron
+			if tt.wantErr == nil {
+				want = &entity.Patron{
+					ID:          tt.req.GetId(),
+					Status:      entity.PatronStatusBlocked,
+					BlockReason: tt.req.GetReason(),
+				}
+			}
+
+			if tt.mocksUsed {
+				patronUseCase.EXPECT().BlockPatron(ctx, tt.req.GetId(), tt.req.GetReason()).
+					Return(want, tt.wantErr)
+			}
+
+			got, err := service.BlockPatron(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, library.PatronStatus_PATRON_STATUS_BLOCKED, got.GetPatron().GetStatus())
+				assert.Equal(t, tt.req.GetReason(), got.GetPatron().GetBlockReason())
+			}
+		})
+	}
+}
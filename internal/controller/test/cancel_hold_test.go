@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_CancelHold(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.CancelHoldRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "cancel hold",
+			req:         &library.CancelHoldRequest{Id: uuid.NewString()},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "cancel hold | not active",
+			req:         &library.CancelHoldRequest{Id: uuid.NewString()},
+			wantErrCode: codes.FailedPrecondition,
+			wantErr:     entity.ErrHoldNotActive,
+			mocksUsed:   true,
+		},
+		{
+			name:        "cancel hold | not found",
+			req:         &library.CancelHoldRequest{Id: uuid.NewString()},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrHoldNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "cancel hold | invalid id",
+			req:         &library.CancelHoldRequest{Id: "hold"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			holdUseCase := mocks.NewMockHoldUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Hold: holdUseCase})
+			ctx := t.Context()
+
+			var want *entity.Hold
+			if tt.wantErr == nil {
+				want = &entity.Hold{
+					ID:     tt.req.GetId(),
+					Status: entity.HoldStatusCancelled,
+				}
+			}
+
+			if tt.mocksUsed {
+				holdUseCase.EXPECT().CancelHold(ctx, tt.req.GetId()).Return(want, tt.wantErr)
+			}
+
+			got, err := service.CancelHold(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, library.HoldStatus_HOLD_STATUS_CANCELLED, got.GetHold().GetStatus())
+			}
+		})
+	}
+}
@@ -2,12 +2,17 @@ package controller
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/project/library/generated/api/library"
 	"github.com/project/library/internal/controller"
@@ -19,16 +24,21 @@ import (
 func Test_ChangeAuthorInfo(t *testing.T) {
 	t.Parallel()
 
+	birthDate := time.Date(1927, time.March, 6, 0, 0, 0, 0, time.UTC)
+
 	type args struct {
-		req *library.ChangeAuthorInfoRequest
+		req     *library.ChangeAuthorInfoRequest
+		ifMatch string
 	}
 
 	tests := []struct {
-		name        string
-		args        args
-		wantErrCode codes.Code
-		wantErr     error
-		mocksUsed   bool
+		name                string
+		args                args
+		wantExpectedVersion int64
+		wantUpdate          *entity.AuthorUpdate
+		wantErrCode         codes.Code
+		wantErr             error
+		mocksUsed           bool
 	}{
 		{
 			name: "change author info",
@@ -42,6 +52,45 @@ func Test_ChangeAuthorInfo(t *testing.T) {
 			wantErr:     nil,
 			mocksUsed:   true,
 		},
+		{
+			name: "change author info | with If-Match",
+			args: args{
+				req: &library.ChangeAuthorInfoRequest{
+					Id:   uuid.NewString(),
+					Name: "New Name",
+				},
+				ifMatch: `"2"`,
+			},
+			wantExpectedVersion: 2,
+			wantErrCode:         codes.OK,
+			mocksUsed:           true,
+		},
+		{
+			name: "change author info | version mismatch",
+			args: args{
+				req: &library.ChangeAuthorInfoRequest{
+					Id:              uuid.NewString(),
+					Name:            "New Name",
+					ExpectedVersion: 1,
+				},
+			},
+			wantExpectedVersion: 1,
+			wantErrCode:         codes.Aborted,
+			wantErr:             entity.ErrVersionMismatch,
+			mocksUsed:           true,
+		},
+		{
+			name: "change author info | invalid If-Match",
+			args: args{
+				req: &library.ChangeAuthorInfoRequest{
+					Id:   uuid.NewString(),
+					Name: "New Name",
+				},
+				ifMatch: "2",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
 		{
 			name: "change author info | with err",
 			args: args{
@@ -54,6 +103,53 @@ func Test_ChangeAuthorInfo(t *testing.T) {
 			wantErr:     entity.ErrAuthorNotFound,
 			mocksUsed:   true,
 		},
+		{
+			name: "change author info | profile with update mask",
+			args: args{
+				req: &library.ChangeAuthorInfoRequest{
+					Id:             uuid.NewString(),
+					BirthDate:      "1927-03-06",
+					Nationality:    "Colombian",
+					AlternateNames: []string{"Gabo"},
+					UpdateMask: &fieldmaskpb.FieldMask{
+						Paths: []string{"birth_date", "death_date", "nationality", "alternate_names"},
+					},
+				},
+			},
+			wantUpdate: &entity.AuthorUpdate{
+				BirthDate:         &birthDate,
+				SetBirthDate:      true,
+				SetDeathDate:      true,
+				Nationality:       proto.String("Colombian"),
+				AlternateNames:    []string{"Gabo"},
+				SetAlternateNames: true,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "change author info | unknown update mask path",
+			args: args{
+				req: &library.ChangeAuthorInfoRequest{
+					Id:         uuid.NewString(),
+					UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"country"}},
+				},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "change author info | invalid date",
+			args: args{
+				req: &library.ChangeAuthorInfoRequest{
+					Id:         uuid.NewString(),
+					DeathDate:  "2014-02-30",
+					UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"death_date"}},
+				},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
 		{
 			name: "change author info | with invalid name",
 			args: args{
@@ -77,17 +173,31 @@ func Test_ChangeAuthorInfo(t *testing.T) {
 
 			logger, _ := zap.NewProduction()
 			authorUseCase := mocks.NewMockAuthorUseCase(ctrl)
-			bookUseCase := mocks.NewMockBooksUseCase(ctrl)
-			service := controller.New(logger, bookUseCase, authorUseCase)
+			service := controller.New(logger, controller.UseCases{Author: authorUseCase})
 			ctx := t.Context()
+			if tt.args.ifMatch != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("if-match", tt.args.ifMatch))
+			}
 
 			if tt.mocksUsed {
-				authorUseCase.EXPECT().ChangeAuthor(ctx, tt.args.req.GetId(), tt.args.req.GetName()).
-					Return(tt.wantErr)
+				update := tt.wantUpdate
+				if update == nil {
+					update = &entity.AuthorUpdate{Name: proto.String(tt.args.req.GetName())}
+				}
+
+				authorUseCase.EXPECT().ChangeAuthor(ctx, tt.args.req.GetId(), *update,
+					tt.wantExpectedVersion).
+					Return(&entity.Author{
+						ID:      tt.args.req.GetId(),
+						Version: tt.wantExpectedVersion + 1,
+					}, tt.wantErr)
 			}
 
-			_, err := service.ChangeAuthorInfo(ctx, tt.args.req)
+			got, err := service.ChangeAuthorInfo(ctx, tt.args.req)
 			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.wantExpectedVersion+1, got.GetVersion())
+			}
 		})
 	}
 }
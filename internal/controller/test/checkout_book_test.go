@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_CheckoutBook(t *testing.T) {
+	t.Parallel()
+
+	dueOn := time.Date(2030, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		req         *library.CheckoutBookRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "checkout book",
+			req: &library.CheckoutBookRequest{
+				CopyId:   uuid.NewString(),
+				PatronId: uuid.NewString(),
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "checkout book | copy not available",
+			req: &library.CheckoutBookRequest{
+				CopyId:   uuid.NewString(),
+				PatronId: uuid.NewString(),
+			},
+			wantErrCode: codes.FailedPrecondition,
+			wantErr:     entity.ErrCopyNotAvailable,
+			mocksUsed:   true,
+		},
+		{
+			name: "checkout book | loan limit reached",
+			req: &library.CheckoutBookRequest{
+				CopyId:   uuid.NewString(),
+				PatronId: uuid.NewString(),
+			},
+			wantErrCode: codes.FailedPrecondition,
+			wantErr:     entity.ErrLoanLimitReached,
+			mocksUsed:   true,
+		},
+		{
+			name: "checkout book | patron not found",
+			req: &library.CheckoutBookRequest{
+				CopyId:   uuid.NewString(),
+				PatronId: uuid.NewString(),
+			},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrPatronNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name: "checkout book | invalid copy id",
+			req: &library.CheckoutBookRequest{
+				CopyId:   "copy",
+				PatronId: uuid.NewString(),
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "checkout book | invalid patron id",
+			req: &library.CheckoutBookRequest{
+				CopyId:   uuid.NewString(),
+				PatronId: "patron",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			loanUseCase := mocks.NewMockLoanUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Loan: loanUseCase})
+			ctx := t.Context()
+
+			var want *entity.Loan
+			if tt.wantErr == nil {
+				want = &entity.Loan{
+					ID:       uuid.NewString(),
+					CopyID:   tt.req.GetCopyId(),
+					BookID:   uuid.NewString(),
+					PatronID: tt.req.GetPatronId(),
+					DueOn:    dueOn,
+				}
+			}
+
+			if tt.mocksUsed {
+				loanUseCase.EXPECT().CheckoutBook(ctx, tt.req.GetCopyId(), tt.req.GetPatronId()).
+					Return(want, tt.wantErr)
+			}
+
+			got, err := service.CheckoutBook(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, want.ID, got.GetLoan().GetId())
+				assert.Equal(t, want.CopyID, got.GetLoan().GetCopyId())
+				assert.Equal(t, want.BookID, got.GetLoan().GetBookId())
+				assert.Equal(t, want.PatronID, got.GetLoan().GetPatronId())
+				assert.Equal(t, "2030-01-31", got.GetLoan().GetDueOn())
+				assert.Nil(t, got.GetLoan().GetReturnedAt())
+			}
+		})
+	}
+}
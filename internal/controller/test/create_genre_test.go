@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_CreateGenre(t *testing.T) {
+	t.Parallel()
+
+	parentID := uuid.NewString()
+
+	tests := []struct {
+		name        string
+		req         *library.CreateGenreRequest
+		want        *entity.Genre
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "create root genre",
+			req:  &library.CreateGenreRequest{Name: "Fiction"},
+			want: &entity.Genre{
+				ID:   uuid.NewString(),
+				Name: "Fiction",
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "create subgenre",
+			req: &library.CreateGenreRequest{
+				Name:     "Science Fiction",
+				ParentId: parentID,
+			},
+			want: &entity.Genre{
+				ID:       uuid.NewString(),
+				Name:     "Science Fiction",
+				ParentID: parentID,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "create genre | parent not found",
+			req: &library.CreateGenreRequest{
+				Name:     "Science Fiction",
+				ParentId: parentID,
+			},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrGenreNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "create genre | already exists",
+			req:         &library.CreateGenreRequest{Name: "Fiction"},
+			wantErrCode: codes.AlreadyExists,
+			wantErr:     entity.ErrGenreAlreadyExists,
+			mocksUsed:   true,
+		},
+		{
+			name:        "create genre | empty name",
+			req:         &library.CreateGenreRequest{},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "create genre | invalid parent id",
+			req: &library.CreateGenreRequest{
+				Name:     "Science Fiction",
+				ParentId: "fiction",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			genreUseCase := mocks.NewMockGenreUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Genre: genreUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				genreUseCase.EXPECT().CreateGenre(ctx, &entity.Genre{
+					Name:     tt.req.GetName(),
+					ParentID: tt.req.GetParentId(),
+				}).Return(tt.want, tt.wantErr)
+			}
+
+			got, err := service.CreateGenre(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.want.ID, got.GetGenre().GetId())
+				assert.Equal(t, tt.want.Name, got.GetGenre().GetName())
+				assert.Equal(t, tt.want.ParentID, got.GetGenre().GetParentId())
+			}
+		})
+	}
+}
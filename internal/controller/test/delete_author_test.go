@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_DeleteAuthor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.DeleteAuthorRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "soft delete author",
+			req: &library.DeleteAuthorRequest{
+				Id: uuid.NewString(),
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "purge author",
+			req: &library.DeleteAuthorRequest{
+				Id:    uuid.NewString(),
+				Purge: true,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "delete author | not found",
+			req: &library.DeleteAuthorRequest{
+				Id: uuid.NewString(),
+			},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrAuthorNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name: "delete author | invalid argument",
+			req: &library.DeleteAuthorRequest{
+				Id: "invalid-id",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			authorUseCase := mocks.NewMockAuthorUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Author: authorUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				authorUseCase.EXPECT().DeleteAuthor(ctx, tt.req.GetId(), tt.req.GetPurge()).Return(tt.wantErr)
+			}
+
+			_, err := service.DeleteAuthor(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_DeleteBook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.DeleteBookRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "soft delete book",
+			req: &library.DeleteBookRequest{
+				Id: uuid.NewString(),
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "purge book",
+			req: &library.DeleteBookRequest{
+				Id:    uuid.NewString(),
+				Purge: true,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "delete book | not found",
+			req: &library.DeleteBookRequest{
+				Id: uuid.NewString(),
+			},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrBookNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name: "delete book | invalid argument",
+			req: &library.DeleteBookRequest{
+				Id: "invalid-id",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			bookUseCase := mocks.NewMockBooksUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Books: bookUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				bookUseCase.EXPECT().DeleteBook(ctx, tt.req.GetId(), tt.req.GetPurge()).Return(tt.wantErr)
+			}
+
+			_, err := service.DeleteBook(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_DeleteCopy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.DeleteCopyRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "delete copy",
+			req:         &library.DeleteCopyRequest{Id: uuid.NewString()},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "delete copy | storage error",
+			req:         &library.DeleteCopyRequest{Id: uuid.NewString()},
+			wantErrCode: codes.Internal,
+			wantErr:     status.Error(codes.Internal, "error"),
+			mocksUsed:   true,
+		},
+		{
+			name:        "delete copy | not found",
+			req:         &library.DeleteCopyRequest{Id: uuid.NewString()},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrCopyNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "delete copy | invalid id",
+			req:         &library.DeleteCopyRequest{Id: "barcode"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			copyUseCase := mocks.NewMockCopyUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Copy: copyUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				copyUseCase.EXPECT().DeleteCopy(ctx, tt.req.GetId()).Return(tt.wantErr)
+			}
+
+			_, err := service.DeleteCopy(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+		})
+	}
+}
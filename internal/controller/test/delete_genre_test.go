@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_DeleteGenre(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.DeleteGenreRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "delete genre",
+			req:         &library.DeleteGenreRequest{Id: uuid.NewString()},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "delete genre | in use",
+			req:         &library.DeleteGenreRequest{Id: uuid.NewString()},
+			wantErrCode: codes.FailedPrecondition,
+			wantErr:     entity.ErrGenreInUse,
+			mocksUsed:   true,
+		},
+		{
+			name:        "delete genre | not found",
+			req:         &library.DeleteGenreRequest{Id: uuid.NewString()},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrGenreNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "delete genre | invalid id",
+			req:         &library.DeleteGenreRequest{Id: "poetry"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			genreUseCase := mocks.NewMockGenreUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Genre: genreUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				genreUseCase.EXPECT().DeleteGenre(ctx, tt.req.GetId()).Return(tt.wantErr)
+			}
+
+			_, err := service.DeleteGenre(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+		})
+	}
+}
@@ -111,11 +111,10 @@ func Test_GetAuthorBooks(t *testing.T) {
 
 			logger, _ := zap.NewProduction()
 			authorUseCase := mocks.NewMockAuthorUseCase(ctrl)
-			bookUseCase := mocks.NewMockBooksUseCase(ctrl)
-			service := controller.New(logger, bookUseCase, authorUseCase)
+			service := controller.New(logger, controller.UseCases{Author: authorUseCase})
 
 			if tt.mocksUsed {
-				authorUseCase.EXPECT().GetAuthorBooks(gomock.Any(), tt.req.GetAuthorId()).Return(nil, tt.wantErr)
+				authorUseCase.EXPECT().GetAuthorBooks(gomock.Any(), tt.req.GetAuthorId(), tt.req.GetShowDeleted()).Return(nil, tt.wantErr)
 			}
 
 			err := service.GetAuthorBooks(tt.req, tt.server)
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -20,10 +21,15 @@ import (
 func Test_GetAuthorInfo(t *testing.T) {
 	t.Parallel()
 
+	birthDate := time.Date(1821, time.November, 11, 0, 0, 0, 0, time.UTC)
+	deathDate := time.Date(1881, time.February, 9, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name        string
 		req         *library.GetAuthorInfoRequest
 		want        *entity.Author
+		wantBirth   string
+		wantDeath   string
 		wantErrCode codes.Code
 		wantErr     error
 		mocksUsed   bool
@@ -34,9 +40,30 @@ func Test_GetAuthorInfo(t *testing.T) {
 				Id: uuid.NewString(),
 			},
 			want: &entity.Author{
-				Name: "name",
-				ID:   uuid.NewString(),
+				Name:    "name",
+				ID:      uuid.NewString(),
+				Version: 3,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "get author info | with profile",
+			req: &library.GetAuthorInfoRequest{
+				Id: uuid.NewString(),
+			},
+			want: &entity.Author{
+				ID:             uuid.NewString(),
+				Name:           "Фёдор Достоевский",
+				BirthDate:      &birthDate,
+				DeathDate:      &deathDate,
+				Nationality:    "Russian",
+				Biography:      "Novelist",
+				AlternateNames: []string{"Fyodor Dostoevsky"},
+				Version:        1,
 			},
+			wantBirth:   "1821-11-11",
+			wantDeath:   "1881-02-09",
 			wantErrCode: codes.OK,
 			mocksUsed:   true,
 		},
@@ -69,8 +96,7 @@ func Test_GetAuthorInfo(t *testing.T) {
 
 			logger, _ := zap.NewProduction()
 			authorUseCase := mocks.NewMockAuthorUseCase(ctrl)
-			bookUseCase := mocks.NewMockBooksUseCase(ctrl)
-			service := controller.New(logger, bookUseCase, authorUseCase)
+			service := controller.New(logger, controller.UseCases{Author: authorUseCase})
 			ctx := t.Context()
 
 			if tt.mocksUsed {
@@ -82,6 +108,12 @@ func Test_GetAuthorInfo(t *testing.T) {
 			if err == nil && tt.want != nil {
 				assert.Equal(t, tt.want.Name, got.GetName())
 				assert.Equal(t, tt.want.ID, got.GetId())
+				assert.Equal(t, tt.want.Version, got.GetVersion())
+				assert.Equal(t, tt.wantBirth, got.GetBirthDate())
+				assert.Equal(t, tt.wantDeath, got.GetDeathDate())
+				assert.Equal(t, tt.want.Nationality, got.GetNationality())
+				assert.Equal(t, tt.want.Biography, got.GetBiography())
+				assert.Equal(t, tt.want.AlternateNames, got.GetAlternateNames())
 			}
 		})
 	}
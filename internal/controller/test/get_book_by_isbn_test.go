@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_GetBookByISBN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.GetBookByISBNRequest
+		want        *entity.Book
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "get book by isbn",
+			req: &library.GetBookByISBNRequest{
+				Isbn: "978-0-306-40615-7",
+			},
+			want: &entity.Book{
+				ID:        uuid.NewString(),
+				Name:      "Book Name",
+				AuthorIDs: []string{uuid.NewString()},
+				ISBN:      "9780306406157",
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "get book by isbn | not found",
+			req: &library.GetBookByISBNRequest{
+				Isbn: "9780306406157",
+			},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrBookNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name: "get book by isbn | invalid isbn",
+			req: &library.GetBookByISBNRequest{
+				Isbn: "9780306406158",
+			},
+			wantErrCode: codes.InvalidArgument,
+			wantErr:     entity.ErrInvalidISBN,
+			mocksUsed:   true,
+		},
+		{
+			name: "get book by isbn | too short",
+			req: &library.GetBookByISBNRequest{
+				Isbn: "123",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			bookUseCase := mocks.NewMockBooksUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Books: bookUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				bookUseCase.EXPECT().GetBookByISBN(ctx, tt.req.GetIsbn()).Return(tt.want, tt.wantErr)
+			}
+
+			got, err := service.GetBookByISBN(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil && tt.want != nil {
+				assert.Equal(t, tt.want.ID, got.GetBook().GetId())
+				assert.Equal(t, tt.want.ISBN, got.GetBook().GetIsbn())
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -20,10 +21,13 @@ import (
 func Test_GetBookInfo(t *testing.T) {
 	t.Parallel()
 
+	deletedAt := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name        string
 		req         *library.GetBookInfoRequest
 		want        *entity.Book
+		wantCopies  *entity.BookAvailability
 		wantErrCode codes.Code
 		wantErr     error
 		mocksUsed   bool
@@ -38,6 +42,29 @@ func Test_GetBookInfo(t *testing.T) {
 				Name:      "Book Name",
 				AuthorIDs: []string{uuid.NewString()},
 			},
+			wantCopies: &entity.BookAvailability{
+				Total:     5,
+				Available: 1,
+				OnLoan:    2,
+				OnHold:    1,
+				InRepair:  1,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "get book info | show deleted",
+			req: &library.GetBookInfoRequest{
+				Id:          uuid.NewString(),
+				ShowDeleted: true,
+			},
+			want: &entity.Book{
+				ID:        uuid.NewString(),
+				Name:      "Book Name",
+				AuthorIDs: []string{uuid.NewString()},
+				DeletedAt: &deletedAt,
+			},
+			wantCopies:  &entity.BookAvailability{},
 			wantErrCode: codes.OK,
 			mocksUsed:   true,
 		},
@@ -69,13 +96,19 @@ func Test_GetBookInfo(t *testing.T) {
 			t.Cleanup(ctrl.Finish)
 
 			logger, _ := zap.NewProduction()
-			authorUseCase := mocks.NewMockAuthorUseCase(ctrl)
 			bookUseCase := mocks.NewMockBooksUseCase(ctrl)
-			service := controller.New(logger, bookUseCase, authorUseCase)
+			copyUseCase := mocks.NewMockCopyUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{
+				Books: bookUseCase,
+				Copy:  copyUseCase,
+			})
 			ctx := t.Context()
 
 			if tt.mocksUsed {
-				bookUseCase.EXPECT().GetBook(ctx, tt.req.GetId()).Return(tt.want, tt.wantErr)
+				bookUseCase.EXPECT().GetBook(ctx, tt.req.GetId(), tt.req.GetShowDeleted()).Return(tt.want, tt.wantErr)
+			}
+			if tt.wantCopies != nil {
+				copyUseCase.EXPECT().GetBookAvailability(ctx, tt.want.ID).Return(tt.wantCopies, nil)
 			}
 
 			got, err := service.GetBookInfo(ctx, tt.req)
@@ -84,6 +117,16 @@ func Test_GetBookInfo(t *testing.T) {
 				assert.Equal(t, tt.want.ID, got.GetBook().GetId())
 				assert.Equal(t, tt.want.Name, got.GetBook().GetName())
 				assert.Equal(t, tt.want.AuthorIDs, got.GetBook().GetAuthorId())
+				assert.Equal(t, int64(tt.wantCopies.Total), got.GetAvailability().GetTotal())
+				assert.Equal(t, int64(tt.wantCopies.Available), got.GetAvailability().GetAvailable())
+				assert.Equal(t, int64(tt.wantCopies.OnLoan), got.GetAvailability().GetOnLoan())
+				assert.Equal(t, int64(tt.wantCopies.OnHold), got.GetAvailability().GetOnHold())
+				assert.Equal(t, int64(tt.wantCopies.InRepair), got.GetAvailability().GetInRepair())
+				if tt.want.DeletedAt != nil {
+					assert.Equal(t, *tt.want.DeletedAt, got.GetBook().GetDeletedAt().AsTime())
+				} else {
+					assert.Nil(t, got.GetBook().GetDeletedAt())
+				}
 			}
 		})
 	}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_GetCopy(t *testing.T) {
+	t.Parallel()
+
+	acquiredOn := time.Date(2023, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		req         *library.GetCopyRequest
+		want        *entity.Copy
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "get copy",
+			req:  &library.GetCopyRequest{Id: uuid.NewString()},
+			want: &entity.Copy{
+				ID:            uuid.NewString(),
+				BookID:        uuid.NewString(),
+				Barcode:       "LIB-000123",
+				Branch:        "Central",
+				ShelfLocation: "A-12",
+				Condition:     entity.CopyConditionFair,
+				AcquiredOn:    &acquiredOn,
+				Status:        entity.CopyStatusInRepair,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get copy | not found",
+			req:         &library.GetCopyRequest{Id: uuid.NewString()},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrCopyNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get copy | invalid id",
+			req:         &library.GetCopyRequest{Id: "barcode"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			copyUseCase := mocks.NewMockCopyUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Copy: copyUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				copyUseCase.EXPECT().GetCopy(ctx, tt.req.GetId()).Return(tt.want, tt.wantErr)
+			}
+
+			got, err := service.GetCopy(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.want.ID, got.GetCopy().GetId())
+				assert.Equal(t, tt.want.BookID, got.GetCopy().GetBookId())
+				assert.Equal(t, tt.want.Barcode, got.GetCopy().GetBarcode())
+				assert.Equal(t, tt.want.Branch, got.GetCopy().GetBranch())
+				assert.Equal(t, tt.want.ShelfLocation, got.GetCopy().GetShelfLocation())
+				assert.Equal(t, library.CopyCondition_COPY_CONDITION_FAIR, got.GetCopy().GetCondition())
+				assert.Equal(t, "2023-09-01", got.GetCopy().GetAcquiredOn())
+				assert.Equal(t, library.CopyStatus_COPY_STATUS_IN_REPAIR, got.GetCopy().GetStatus())
+			}
+		})
+	}
+}
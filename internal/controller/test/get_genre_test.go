@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_GetGenre(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.GetGenreRequest
+		want        *entity.Genre
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "get genre",
+			req:  &library.GetGenreRequest{Id: uuid.NewString()},
+			want: &entity.Genre{
+				ID:       uuid.NewString(),
+				Name:     "Poetry",
+				ParentID: uuid.NewString(),
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get genre | not found",
+			req:         &library.GetGenreRequest{Id: uuid.NewString()},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrGenreNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get genre | invalid id",
+			req:         &library.GetGenreRequest{Id: "poetry"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			genreUseCase := mocks.NewMockGenreUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Genre: genreUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				genreUseCase.EXPECT().GetGenre(ctx, tt.req.GetId()).Return(tt.want, tt.wantErr)
+			}
+
+			got, err := service.GetGenre(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.want.ID, got.GetGenre().GetId())
+				assert.Equal(t, tt.want.Name, got.GetGenre().GetName())
+				assert.Equal(t, tt.want.ParentID, got.GetGenre().GetParentId())
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/admin"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func Test_GetOutboxMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *admin.GetOutboxMessageRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "get outbox message",
+			req:         &admin.GetOutboxMessageRequest{IdempotencyKey: "key"},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get outbox message | not found",
+			req:         &admin.GetOutboxMessageRequest{IdempotencyKey: "key"},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrOutboxMessageNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get outbox message | empty key",
+			req:         &admin.GetOutboxMessageRequest{},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			outboxAdminUseCase := mocks.NewMockOutboxAdminUseCase(ctrl)
+			service := controller.NewAdmin(logger, outboxAdminUseCase)
+			ctx := t.Context()
+
+			var want *repository.OutboxMessage
+			if tt.wantErr == nil {
+				want = &repository.OutboxMessage{
+					IdempotencyKey: tt.req.GetIdempotencyKey(),
+					Kind:           repository.OutboxKindBook,
+					Status:         repository.OutboxStatusFailed,
+					Attempts:       3,
+					LastError:      "non success response: 503",
+				}
+			}
+
+			if tt.mocksUsed {
+				outboxAdminUseCase.EXPECT().GetOutboxMessage(ctx, tt.req.GetIdempotencyKey()).Return(want, tt.wantErr)
+			}
+
+			got, err := service.GetOutboxMessage(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.req.GetIdempotencyKey(), got.GetMessage().GetIdempotencyKey())
+				assert.Equal(t, "book", got.GetMessage().GetKind())
+				assert.Equal(t, admin.OutboxStatus_OUTBOX_STATUS_FAILED, got.GetMessage().GetStatus())
+				assert.Equal(t, int32(3), got.GetMessage().GetAttempts())
+				assert.Equal(t, want.LastError, got.GetMessage().GetLastError())
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_GetPatronAccount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.GetPatronAccountRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "get patron account",
+			req:         &library.GetPatronAccountRequest{Id: uuid.NewString()},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get patron account | not found",
+			req:         &library.GetPatronAccountRequest{Id: uuid.NewString()},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrPatronNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get patron account | invalid id",
+			req:         &library.GetPatronAccountRequest{Id: "patron"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			fineUseCase := mocks.NewMockFineUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Fine: fineUseCase})
+			ctx := t.Context()
+
+			var want *entity.PatronAccount
+			if tt.wantErr == nil {
+				want = &entity.PatronAccount{
+					Patron: &entity.Patron{ID: tt.req.GetId(), Name: "Patron"},
+					Fines: []*entity.Fine{
+						{ID: uuid.NewString(), PatronID: tt.req.GetId(), Amount: 500, Paid: 200},
+						{ID: uuid.NewString(), PatronID: tt.req.GetId(), Amount: 75},
+					},
+					Balance: 375,
+				}
+			}
+
+			if tt.mocksUsed {
+				fineUseCase.EXPECT().GetPatronAccount(ctx, tt.req.GetId()).Return(want, tt.wantErr)
+			}
+
+			got, err := service.GetPatronAccount(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.req.GetId(), got.GetPatron().GetId())
+				assert.Equal(t, int64(375), got.GetBalance())
+				assert.Len(t, got.GetFines(), 2)
+				assert.Equal(t, want.Fines[0].ID, got.GetFines()[0].GetId())
+				assert.Equal(t, int64(300), got.GetFines()[0].GetOutstanding())
+				assert.Equal(t, int64(75), got.GetFines()[1].GetOutstanding())
+			}
+		})
+	}
+}
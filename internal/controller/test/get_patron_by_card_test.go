@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_GetPatronByCard(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.GetPatronByCardRequest
+		want        *entity.Patron
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "get patron by card",
+			req:  &library.GetPatronByCardRequest{CardNumber: "2900-0000-0000-07"},
+			want: &entity.Patron{
+				ID:         uuid.NewString(),
+				CardNumber: "29000000000007",
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get patron by card | invalid card number",
+			req:         &library.GetPatronByCardRequest{CardNumber: "29000000000002"},
+			wantErrCode: codes.InvalidArgument,
+			wantErr:     entity.ErrInvalidCardNumber,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get patron by card | too short",
+			req:         &library.GetPatronByCardRequest{CardNumber: "2900"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			patronUseCase := mocks.NewMockPatronUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Patron: patronUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				patronUseCase.EXPECT().GetPatronByCardNumber(ctx, tt.req.GetCardNumber()).
+					Return(tt.want, tt.wantErr)
+			}
+
+			got, err := service.GetPatronByCard(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.want.ID, got.GetPatron().GetId())
+				assert.Equal(t, tt.want.CardNumber, got.GetPatron().GetCardNumber())
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_GetPatron(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.GetPatronRequest
+		want        *entity.Patron
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "get patron",
+			req:  &library.GetPatronRequest{Id: uuid.NewString()},
+			want: &entity.Patron{
+				ID:                  uuid.NewString(),
+				CardNumber:          "29000000000007",
+				Name:                "Ada Lovelace",
+				Email:               "ada@example.com",
+				MembershipExpiresOn: time.Date(2030, time.January, 31, 0, 0, 0, 0, time.UTC),
+				Status:              entity.PatronStatusActive,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get patron | not found",
+			req:         &library.GetPatronRequest{Id: uuid.NewString()},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrPatronNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "get patron | invalid id",
+			req:         &library.GetPatronRequest{Id: "patron"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			patronUseCase := mocks.NewMockPatronUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Patron: patronUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				patronUseCase.EXPECT().GetPatron(ctx, tt.req.GetId()).Return(tt.want, tt.wantErr)
+			}
+
+			got, err := service.GetPatron(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.want.ID, got.GetPatron().GetId())
+				assert.Equal(t, tt.want.CardNumber, got.GetPatron().GetCardNumber())
+				assert.Equal(t, tt.want.Email, got.GetPatron().GetEmail())
+				assert.Equal(t, "2030-01-31", got.GetPatron().GetMembershipExpiresOn())
+				assert.Equal(t, library.PatronStatus_PATRON_STATUS_ACTIVE, got.GetPatron().GetStatus())
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/inbox"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/inbox/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func Test_IngestEvent(t *testing.T) {
+	t.Parallel()
+
+	authorID := uuid.NewString()
+	targetID := uuid.NewString()
+
+	bookPurchased := &inbox.IngestEventRequest_BookPurchased{
+		BookPurchased: &inbox.BookPurchased{
+			Name:            "Dune",
+			AuthorId:        []string{authorID},
+			Isbn:            "978-0-441-17271-9",
+			PublicationYear: 1965,
+			Language:        "en",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		req           *inbox.IngestEventRequest
+		wantKind      repository.InboxKind
+		wantEvent     any
+		received      bool
+		receiveErr    error
+		wantErrCode   codes.Code
+		wantDuplicate bool
+		mocksUsed     bool
+	}{
+		{
+			name:     "ingest book purchased",
+			req:      &inbox.IngestEventRequest{MessageId: "acq-1", Event: bookPurchased},
+			wantKind: repository.InboxKindBookPurchased,
+			wantEvent: entity.BookPurchased{
+				Name:            "Dune",
+				AuthorIDs:       []string{authorID},
+				ISBN:            "978-0-441-17271-9",
+				PublicationYear: 1965,
+				Language:        "en",
+			},
+			received:    true,
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "ingest author merged",
+			req: &inbox.IngestEventRequest{
+				MessageId: "acq-2",
+				Event: &inbox.IngestEventRequest_AuthorMerged{
+					AuthorMerged: &inbox.AuthorMerged{SourceId: authorID, TargetId: targetID},
+				},
+			},
+			wantKind:    repository.InboxKindAuthorMerged,
+			wantEvent:   entity.AuthorMerged{SourceID: authorID, TargetID: targetID},
+			received:    true,
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:          "ingest event | duplicate",
+			req:           &inbox.IngestEventRequest{MessageId: "acq-1", Event: bookPurchased},
+			wantKind:      repository.InboxKindBookPurchased,
+			wantEvent:     gomock.Any(),
+			wantErrCode:   codes.OK,
+			wantDuplicate: true,
+			mocksUsed:     true,
+		},
+		{
+			name:        "ingest event | store error",
+			req:         &inbox.IngestEventRequest{MessageId: "acq-1", Event: bookPurchased},
+			wantKind:    repository.InboxKindBookPurchased,
+			wantEvent:   gomock.Any(),
+			receiveErr:  errors.New("db error"),
+			wantErrCode: codes.Internal,
+			mocksUsed:   true,
+		},
+		{
+			name:        "ingest event | no message id",
+			req:         &inbox.IngestEventRequest{Event: bookPurchased},
+			wantErrCode: codes.InvalidArgument,
+		},
+		{
+			name:        "ingest event | no event",
+			req:         &inbox.IngestEventRequest{MessageId: "acq-1"},
+			wantErrCode: codes.InvalidArgument,
+		},
+		{
+			name: "ingest event | invalid author id",
+			req: &inbox.IngestEventRequest{
+				MessageId: "acq-1",
+				Event: &inbox.IngestEventRequest_AuthorMerged{
+					AuthorMerged: &inbox.AuthorMerged{SourceId: "1", TargetId: targetID},
+				},
+			},
+			wantErrCode: codes.InvalidArgument,
+		},
+		{
+			name: "ingest event | author merged into itself",
+			req: &inbox.IngestEventRequest{
+				MessageId: "acq-1",
+				Event: &inbox.IngestEventRequest_AuthorMerged{
+					AuthorMerged: &inbox.AuthorMerged{SourceId: authorID, TargetId: authorID},
+				},
+			},
+			wantErrCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			inboxService := mocks.NewMockInbox(ctrl)
+			service := controller.NewInbox(logger, inboxService)
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				inboxService.EXPECT().Receive(ctx, tt.req.GetMessageId(), tt.wantKind, tt.wantEvent).
+					Return(tt.received, tt.receiveErr)
+			}
+
+			got, err := service.IngestEvent(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.wantDuplicate, got.GetDuplicate())
+			}
+		})
+	}
+}
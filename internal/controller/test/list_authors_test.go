@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_ListAuthors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		req           *library.ListAuthorsRequest
+		wantFilter    entity.AuthorsFilter
+		want          []*entity.Author
+		nextPageToken string
+		wantErrCode   codes.Code
+		wantErr       error
+		mocksUsed     bool
+	}{
+		{
+			name: "list authors",
+			req: &library.ListAuthorsRequest{
+				PageSize:  2,
+				NameQuery: "tolst",
+				NameMatch: library.NameMatch_NAME_MATCH_SUBSTRING,
+			},
+			wantFilter: entity.AuthorsFilter{
+				NameQuery: "tolst",
+				NameMatch: entity.NameMatchSubstring,
+			},
+			want: []*entity.Author{
+				{ID: uuid.NewString(), Name: "Aleksey Tolstoy", BookCount: 3},
+				{ID: uuid.NewString(), Name: "Leo Tolstoy", BookCount: 12},
+			},
+			nextPageToken: "next",
+			wantErrCode:   codes.OK,
+			mocksUsed:     true,
+		},
+		{
+			name: "list authors | prefix by default",
+			req:  &library.ListAuthorsRequest{NameQuery: "leo"},
+			wantFilter: entity.AuthorsFilter{
+				NameQuery: "leo",
+				NameMatch: entity.NameMatchPrefix,
+			},
+			want:        []*entity.Author{},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list authors | invalid page token",
+			req:         &library.ListAuthorsRequest{PageToken: "broken"},
+			wantErrCode: codes.InvalidArgument,
+			wantErr:     entity.ErrInvalidPageToken,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list authors | invalid name match",
+			req:         &library.ListAuthorsRequest{NameMatch: 42},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			authorUseCase := mocks.NewMockAuthorUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Author: authorUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				authorUseCase.EXPECT().ListAuthors(ctx, tt.wantFilter,
+					int(tt.req.GetPageSize()), tt.req.GetPageToken()).
+					Return(tt.want, tt.nextPageToken, tt.wantErr)
+			}
+
+			got, err := service.ListAuthors(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Len(t, got.GetAuthors(), len(tt.want))
+				for i, author := range tt.want {
+					assert.Equal(t, author.ID, got.GetAuthors()[i].GetId())
+					assert.Equal(t, author.Name, got.GetAuthors()[i].GetName())
+					assert.Equal(t, int64(author.BookCount), got.GetAuthors()[i].GetBookCount())
+				}
+				assert.Equal(t, tt.nextPageToken, got.GetNextPageToken())
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_ListBooks(t *testing.T) {
+	t.Parallel()
+
+	authorID := uuid.NewString()
+	genreID := uuid.NewString()
+	createdAfter := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		req           *library.ListBooksRequest
+		wantFilter    entity.BooksFilter
+		want          []*entity.Book
+		nextPageToken string
+		wantErrCode   codes.Code
+		wantErr       error
+		mocksUsed     bool
+	}{
+		{
+			name: "list books",
+			req: &library.ListBooksRequest{
+				PageSize:     2,
+				PageToken:    "token",
+				NamePrefix:   "Harry",
+				AuthorId:     authorID,
+				CreatedAfter: timestamppb.New(createdAfter),
+				SortOrder:    library.SortOrder_SORT_ORDER_DESC,
+			},
+			wantFilter: entity.BooksFilter{
+				NamePrefix:   "Harry",
+				AuthorID:     authorID,
+				CreatedAfter: &createdAfter,
+				SortOrder:    entity.SortOrderDesc,
+			},
+			want: []*entity.Book{
+				{ID: uuid.NewString(), Name: "Harry Potter 2"},
+				{ID: uuid.NewString(), Name: "Harry Potter 1"},
+			},
+			nextPageToken: "next",
+			wantErrCode:   codes.OK,
+			mocksUsed:     true,
+		},
+		{
+			name: "list books | genre and tag",
+			req: &library.ListBooksRequest{
+				GenreId: genreID,
+				Tag:     "classic",
+			},
+			wantFilter: entity.BooksFilter{
+				GenreID:   genreID,
+				Tag:       "classic",
+				SortOrder: entity.SortOrderAsc,
+			},
+			want: []*entity.Book{
+				{ID: uuid.NewString(), Name: "Dune", GenreIDs: []string{genreID}, Tags: []string{"classic"}},
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "list books | invalid page token",
+			req:  &library.ListBooksRequest{PageToken: "broken"},
+			wantFilter: entity.BooksFilter{
+				SortOrder: entity.SortOrderAsc,
+			},
+			wantErrCode: codes.InvalidArgument,
+			wantErr:     entity.ErrInvalidPageToken,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list books | invalid author id",
+			req:         &library.ListBooksRequest{AuthorId: "1"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name:        "list books | page size too large",
+			req:         &library.ListBooksRequest{PageSize: 1001},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			bookUseCase := mocks.NewMockBooksUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Books: bookUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				bookUseCase.EXPECT().ListBooks(ctx, tt.wantFilter,
+					int(tt.req.GetPageSize()), tt.req.GetPageToken()).
+					Return(tt.want, tt.nextPageToken, tt.wantErr)
+			}
+
+			got, err := service.ListBooks(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Len(t, got.GetBooks(), len(tt.want))
+				for i, book := range tt.want {
+					assert.Equal(t, book.ID, got.GetBooks()[i].GetId())
+				}
+				assert.Equal(t, tt.nextPageToken, got.GetNextPageToken())
+			}
+		})
+	}
+}
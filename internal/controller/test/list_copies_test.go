@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_ListCopies(t *testing.T) {
+	t.Parallel()
+
+	bookID := uuid.NewString()
+
+	tests := []struct {
+		name        string
+		req         *library.ListCopiesRequest
+		wantFilter  entity.CopiesFilter
+		want        []*entity.Copy
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "list copies",
+			req:  &library.ListCopiesRequest{BookId: bookID},
+			want: []*entity.Copy{
+				{ID: uuid.NewString(), BookID: bookID, Barcode: "LIB-1", Branch: "Central"},
+				{ID: uuid.NewString(), BookID: bookID, Barcode: "LIB-2", Branch: "North"},
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "list copies | available in branch",
+			req: &library.ListCopiesRequest{
+				BookId: bookID,
+				Branch: "Central",
+				Status: library.CopyStatus_COPY_STATUS_AVAILABLE,
+			},
+			wantFilter: entity.CopiesFilter{
+				Branch: "Central",
+				Status: entity.CopyStatusAvailable,
+			},
+			want:        []*entity.Copy{},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list copies | book not found",
+			req:         &library.ListCopiesRequest{BookId: bookID},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrBookNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list copies | invalid book id",
+			req:         &library.ListCopiesRequest{BookId: "book"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			copyUseCase := mocks.NewMockCopyUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Copy: copyUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				copyUseCase.EXPECT().ListCopies(ctx, tt.req.GetBookId(), tt.wantFilter).
+					Return(tt.want, tt.wantErr)
+			}
+
+			got, err := service.ListCopies(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Len(t, got.GetCopies(), len(tt.want))
+				for i, bookCopy := range tt.want {
+					assert.Equal(t, bookCopy.ID, got.GetCopies()[i].GetId())
+					assert.Equal(t, bookCopy.Barcode, got.GetCopies()[i].GetBarcode())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_ListGenres(t *testing.T) {
+	t.Parallel()
+
+	rootID := uuid.NewString()
+
+	tests := []struct {
+		name        string
+		req         *library.ListGenresRequest
+		want        []*entity.Genre
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "list genres",
+			req:  &library.ListGenresRequest{},
+			want: []*entity.Genre{
+				{ID: rootID, Name: "Fiction"},
+				{ID: uuid.NewString(), Name: "Science Fiction", ParentID: rootID},
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "list genres | subtree",
+			req:  &library.ListGenresRequest{RootId: rootID},
+			want: []*entity.Genre{
+				{ID: rootID, Name: "Fiction"},
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list genres | root not found",
+			req:         &library.ListGenresRequest{RootId: uuid.NewString()},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrGenreNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list genres | invalid root id",
+			req:         &library.ListGenresRequest{RootId: "fiction"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			genreUseCase := mocks.NewMockGenreUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Genre: genreUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				genreUseCase.EXPECT().ListGenres(ctx, tt.req.GetRootId()).Return(tt.want, tt.wantErr)
+			}
+
+			got, err := service.ListGenres(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Len(t, got.GetGenres(), len(tt.want))
+				for i, genre := range tt.want {
+					assert.Equal(t, genre.ID, got.GetGenres()[i].GetId())
+					assert.Equal(t, genre.ParentID, got.GetGenres()[i].GetParentId())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_ListHolds(t *testing.T) {
+	t.Parallel()
+
+	readyAt := time.Date(2030, time.January, 10, 9, 0, 0, 0, time.UTC)
+	expiresOn := time.Date(2030, time.January, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		req         *library.ListHoldsRequest
+		want        []*entity.Hold
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "list holds of a book",
+			req:  &library.ListHoldsRequest{BookId: uuid.NewString()},
+			want: []*entity.Hold{
+				{
+					ID:        uuid.NewString(),
+					CopyID:    uuid.NewString(),
+					Status:    entity.HoldStatusReady,
+					ReadyAt:   &readyAt,
+					ExpiresOn: &expiresOn,
+				},
+				{
+					ID:            uuid.NewString(),
+					Status:        entity.HoldStatusWaiting,
+					QueuePosition: 1,
+				},
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list holds of a patron",
+			req:         &library.ListHoldsRequest{PatronId: uuid.NewString()},
+			want:        []*entity.Hold{},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list holds | no filter",
+			req:         &library.ListHoldsRequest{},
+			wantErrCode: codes.InvalidArgument,
+			wantErr:     entity.ErrInvalidHoldsFilter,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list holds | invalid patron id",
+			req:         &library.ListHoldsRequest{PatronId: "patron"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			holdUseCase := mocks.NewMockHoldUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Hold: holdUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				holdUseCase.EXPECT().ListHolds(ctx, entity.HoldsFilter{
+					BookID:   tt.req.GetBookId(),
+					PatronID: tt.req.GetPatronId(),
+				}).Return(tt.want, tt.wantErr)
+			}
+
+			got, err := service.ListHolds(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Len(t, got.GetHolds(), len(tt.want))
+				for i, hold := range tt.want {
+					assert.Equal(t, hold.ID, got.GetHolds()[i].GetId())
+					assert.Equal(t, hold.CopyID, got.GetHolds()[i].GetCopyId())
+					assert.Equal(t, int32(hold.QueuePosition), got.GetHolds()[i].GetQueuePosition())
+				}
+				if len(tt.want) > 0 {
+					assert.Equal(t, library.HoldStatus_HOLD_STATUS_READY, got.GetHolds()[0].GetStatus())
+					assert.Equal(t, "2030-01-17", got.GetHolds()[0].GetExpiresOn())
+					assert.Equal(t, readyAt, got.GetHolds()[0].GetReadyAt().AsTime())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/project/library/generated/api/admin"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func Test_ListOutboxMessages(t *testing.T) {
+	t.Parallel()
+
+	createdAfter := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		req         *admin.ListOutboxMessagesRequest
+		wantFilter  repository.OutboxFilter
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "list outbox messages",
+			req:         &admin.ListOutboxMessagesRequest{},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "list outbox messages | filtered",
+			req: &admin.ListOutboxMessagesRequest{
+				Status:       admin.OutboxStatus_OUTBOX_STATUS_DEAD,
+				Kind:         "book_updated",
+				CreatedAfter: timestamppb.New(createdAfter),
+				PageSize:     10,
+			},
+			wantFilter: repository.OutboxFilter{
+				Status:       repository.OutboxStatusDead,
+				Kind:         repository.OutboxKindBookUpdated,
+				CreatedAfter: createdAfter,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list outbox messages | invalid page token",
+			req:         &admin.ListOutboxMessagesRequest{PageToken: "token"},
+			wantErrCode: codes.InvalidArgument,
+			wantErr:     entity.ErrInvalidPageToken,
+			mocksUsed:   true,
+		},
+		{
+			name:        "list outbox messages | unknown kind",
+			req:         &admin.ListOutboxMessagesRequest{Kind: "book_borrowed"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name:        "list outbox messages | undefined status",
+			req:         &admin.ListOutboxMessagesRequest{Status: 42},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name:        "list outbox messages | page size too big",
+			req:         &admin.ListOutboxMessagesRequest{PageSize: 1001},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			outboxAdminUseCase := mocks.NewMockOutboxAdminUseCase(ctrl)
+			service := controller.NewAdmin(logger, outboxAdminUseCase)
+			ctx := t.Context()
+
+			var want []*repository.OutboxMessage
+			if tt.wantErr == nil {
+				want = []*repository.OutboxMessage{
+					{IdempotencyKey: "key", Kind: repository.OutboxKindBookUpdated,
+						Status: repository.OutboxStatusDead, RawData: []byte(`{}`)},
+				}
+			}
+
+			if tt.mocksUsed {
+				outboxAdminUseCase.EXPECT().ListOutboxMessages(ctx, tt.wantFilter,
+					int(tt.req.GetPageSize()), tt.req.GetPageToken()).Return(want, "next", tt.wantErr)
+			}
+
+			got, err := service.ListOutboxMessages(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, "next", got.GetNextPageToken())
+				assert.Len(t, got.GetMessages(), 1)
+				assert.Equal(t, "book_updated", got.GetMessages()[0].GetKind())
+				assert.Equal(t, admin.OutboxStatus_OUTBOX_STATUS_DEAD, got.GetMessages()[0].GetStatus())
+				assert.JSONEq(t, `{}`, got.GetMessages()[0].GetData())
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_PayFine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.PayFineRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "pay fine",
+			req:         &library.PayFineRequest{Id: uuid.NewString(), Amount: 100},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "pay fine | more than outstanding",
+			req:         &library.PayFineRequest{Id: uuid.NewString(), Amount: 1000},
+			wantErrCode: codes.InvalidArgument,
+			wantErr:     entity.ErrInvalidPayment,
+			mocksUsed:   true,
+		},
+		{
+			name:        "pay fine | not found",
+			req:         &library.PayFineRequest{Id: uuid.NewString(), Amount: 100},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrFineNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "pay fine | zero amount",
+			req:         &library.PayFineRequest{Id: uuid.NewString()},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name:        "pay fine | invalid id",
+			req:         &library.PayFineRequest{Id: "fine", Amount: 100},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			fineUseCase := mocks.NewMockFineUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Fine: fineUseCase})
+			ctx := t.Context()
+
+			var want *entity.Fine
+			if tt.wantErr == nil {
+				want = &entity.Fine{
+					ID:     tt.req.GetId(),
+					Amount: 500,
+					Paid:   200 + tt.req.GetAmount(),
+				}
+			}
+
+			if tt.mocksUsed {
+				fineUseCase.EXPECT().PayFine(ctx, tt.req.GetId(), tt.req.GetAmount()).Return(want, tt.wantErr)
+			}
+
+			got, err := service.PayFine(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.req.GetId(), got.GetFine().GetId())
+				assert.Equal(t, int64(300), got.GetFine().GetPaid())
+				assert.Equal(t, int64(200), got.GetFine().GetOutstanding())
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_PlaceHold(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.PlaceHoldRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "place hold",
+			req: &library.PlaceHoldRequest{
+				BookId:   uuid.NewString(),
+				PatronId: uuid.NewString(),
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "place hold | book available",
+			req: &library.PlaceHoldRequest{
+				BookId:   uuid.NewString(),
+				PatronId: uuid.NewString(),
+			},
+			wantErrCode: codes.FailedPrecondition,
+			wantErr:     entity.ErrBookAvailable,
+			mocksUsed:   true,
+		},
+		{
+			name: "place hold | already exists",
+			req: &library.PlaceHoldRequest{
+				BookId:   uuid.NewString(),
+				PatronId: uuid.NewString(),
+			},
+			wantErrCode: codes.AlreadyExists,
+			wantErr:     entity.ErrHoldAlreadyExists,
+			mocksUsed:   true,
+		},
+		{
+			name: "place hold | invalid book id",
+			req: &library.PlaceHoldRequest{
+				BookId:   "book",
+				PatronId: uuid.NewString(),
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			holdUseCase := mocks.NewMockHoldUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Hold: holdUseCase})
+			ctx := t.Context()
+
+			var want *entity.Hold
+			if tt.wantErr == nil {
+				want = &entity.Hold{
+					ID:            uuid.NewString(),
+					BookID:        tt.req.GetBookId(),
+					PatronID:      tt.req.GetPatronId(),
+					Status:        entity.HoldStatusWaiting,
+					QueuePosition: 3,
+					PlacedAt:      time.Now(),
+				}
+			}
+
+			if tt.mocksUsed {
+				holdUseCase.EXPECT().PlaceHold(ctx, tt.req.GetBookId(), tt.req.GetPatronId()).
+					Return(want, tt.wantErr)
+			}
+
+			got, err := service.PlaceHold(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, want.ID, got.GetHold().GetId())
+				assert.Equal(t, library.HoldStatus_HOLD_STATUS_WAITING, got.GetHold().GetStatus())
+				assert.Equal(t, int32(3), got.GetHold().GetQueuePosition())
+				assert.Empty(t, got.GetHold().GetCopyId())
+				assert.Empty(t, got.GetHold().GetExpiresOn())
+				assert.Nil(t, got.GetHold().GetReadyAt())
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/project/library/generated/api/admin"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_PurgeOutbox(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *admin.PurgeOutboxRequest
+		deleted     int64
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "purge outbox",
+			req:         &admin.PurgeOutboxRequest{OlderThan: durationpb.New(72 * time.Hour)},
+			deleted:     120,
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "purge outbox | internal error",
+			req:         &admin.PurgeOutboxRequest{OlderThan: durationpb.New(time.Hour)},
+			wantErrCode: codes.Internal,
+			wantErr:     errors.New("db error"),
+			mocksUsed:   true,
+		},
+		{
+			name:        "purge outbox | no age",
+			req:         &admin.PurgeOutboxRequest{},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name:        "purge outbox | negative age",
+			req:         &admin.PurgeOutboxRequest{OlderThan: durationpb.New(-time.Hour)},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			outboxAdminUseCase := mocks.NewMockOutboxAdminUseCase(ctrl)
+			service := controller.NewAdmin(logger, outboxAdminUseCase)
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				outboxAdminUseCase.EXPECT().PurgeOutbox(ctx, tt.req.GetOlderThan().AsDuration()).
+					Return(tt.deleted, tt.wantErr)
+			}
+
+			got, err := service.PurgeOutbox(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.deleted, got.GetDeleted())
+			}
+		})
+	}
+}
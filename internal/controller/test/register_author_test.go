@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -41,6 +43,41 @@ func Test_RegisterAuthor(t *testing.T) {
 			wantErr:     nil,
 			mocksUsed:   true,
 		},
+		{
+			name: "register author | unicode name with profile",
+			req: &library.RegisterAuthorRequest{
+				Name:           "Gabriel García Márquez",
+				BirthDate:      "1927-03-06",
+				DeathDate:      "2014-04-17",
+				Nationality:    "Colombian",
+				Biography:      "Novelist",
+				AlternateNames: []string{"Gabo"},
+			},
+			want: &entity.Author{
+				ID:   uuid.NewString(),
+				Name: "Gabriel García Márquez",
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "register author | invalid date",
+			req: &library.RegisterAuthorRequest{
+				Name:      "Name",
+				BirthDate: "1927-13-06",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "register author | invalid name",
+			req: &library.RegisterAuthorRequest{
+				Name: "Name!",
+			},
+			wantErrCode: codes.InvalidArgument,
+			wantErr:     entity.ErrInvalidAuthorName,
+			mocksUsed:   true,
+		},
 		{
 			name: "register author | invalid argument",
 			req: &library.RegisterAuthorRequest{
@@ -70,12 +107,20 @@ func Test_RegisterAuthor(t *testing.T) {
 
 			logger, _ := zap.NewProduction()
 			authorUseCase := mocks.NewMockAuthorUseCase(ctrl)
-			bookUseCase := mocks.NewMockBooksUseCase(ctrl)
-			service := controller.New(logger, bookUseCase, authorUseCase)
+			service := controller.New(logger, controller.UseCases{Author: authorUseCase})
 			ctx := t.Context()
 
 			if tt.mocksUsed {
-				authorUseCase.EXPECT().RegisterAuthor(ctx, tt.req.GetName()).Return(tt.want, tt.wantErr)
+				authorUseCase.EXPECT().RegisterAuthor(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, author *entity.Author) (*entity.Author, error) {
+						assert.Equal(t, tt.req.GetName(), author.Name)
+						assert.Equal(t, tt.req.GetBirthDate(), formatDate(author.BirthDate))
+						assert.Equal(t, tt.req.GetDeathDate(), formatDate(author.DeathDate))
+						assert.Equal(t, tt.req.GetNationality(), author.Nationality)
+						assert.Equal(t, tt.req.GetBiography(), author.Biography)
+						assert.Equal(t, tt.req.GetAlternateNames(), author.AlternateNames)
+						return tt.want, tt.wantErr
+					})
 			}
 
 			got, err := service.RegisterAuthor(ctx, tt.req)
@@ -86,3 +131,11 @@ func Test_RegisterAuthor(t *testing.T) {
 		})
 	}
 }
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.DateOnly)
+}
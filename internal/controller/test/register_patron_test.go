@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_RegisterPatron(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		req           *library.RegisterPatronRequest
+		wantExpiresOn string
+		wantErrCode   codes.Code
+		wantErr       error
+		mocksUsed     bool
+	}{
+		{
+			name: "register patron",
+			req: &library.RegisterPatronRequest{
+				Name:                "Ada Lovelace",
+				Email:               "ada@example.com",
+				Phone:               "+44 20 7946 0000",
+				Address:             "12 St James's Square, London",
+				MembershipExpiresOn: "2030-01-31",
+			},
+			wantExpiresOn: "2030-01-31",
+			wantErrCode:   codes.OK,
+			mocksUsed:     true,
+		},
+		{
+			name:        "register patron | name only",
+			req:         &library.RegisterPatronRequest{Name: "Ada Lovelace"},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "register patron | expiry in the past",
+			req: &library.RegisterPatronRequest{
+				Name:                "Ada Lovelace",
+				MembershipExpiresOn: "2001-01-31",
+			},
+			wantExpiresOn: "2001-01-31",
+			wantErrCode:   codes.InvalidArgument,
+			wantErr:       entity.ErrInvalidMembershipExpiry,
+			mocksUsed:     true,
+		},
+		{
+			name: "register patron | invalid email",
+			req: &library.RegisterPatronRequest{
+				Name:  "Ada Lovelace",
+				Email: "ada",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "register patron | invalid phone",
+			req: &library.RegisterPatronRequest{
+				Name:  "Ada Lovelace",
+				Phone: "call me",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "register patron | invalid expiry date",
+			req: &library.RegisterPatronRequest{
+				Name:                "Ada Lovelace",
+				MembershipExpiresOn: "2030-02-30",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name:        "register patron | empty name",
+			req:         &library.RegisterPatronRequest{},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			patronUseCase := mocks.NewMockPatronUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Patron: patronUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				patronUseCase.EXPECT().RegisterPatron(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, patron *entity.Patron) (*entity.Patron, error) {
+						assert.Equal(t, tt.req.GetName(), patron.Name)
+						assert.Equal(t, tt.req.GetEmail(), patron.Email)
+						assert.Equal(t, tt.req.GetPhone(), patron.Phone)
+						assert.Equal(t, tt.req.GetAddress(), patron.Address)
+						if tt.wantExpiresOn == "" {
+							assert.True(t, patron.MembershipExpiresOn.IsZero())
+						} else {
+							assert.Equal(t, tt.wantExpiresOn, formatDate(&patron.MembershipExpiresOn))
+						}
+						if tt.wantErr != nil {
+							return nil, tt.wantErr
+						}
+
+						patron.ID = uuid.NewString()
+						patron.CardNumber = "29000000000007"
+						return patron, nil
+					})
+			}
+
+			got, err := service.RegisterPatron(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.NotEmpty(t, got.GetPatron().GetId())
+				assert.Equal(t, "29000000000007", got.GetPatron().GetCardNumber())
+			}
+		})
+	}
+}
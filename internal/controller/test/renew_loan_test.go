@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_RenewLoan(t *testing.T) {
+	t.Parallel()
+
+	dueOn := time.Date(2030, time.February, 21, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		req         *library.RenewLoanRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "renew loan",
+			req:         &library.RenewLoanRequest{Id: uuid.NewString()},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "renew loan | renewal limit reached",
+			req:         &library.RenewLoanRequest{Id: uuid.NewString()},
+			wantErrCode: codes.FailedPrecondition,
+			wantErr:     entity.ErrRenewalLimitReached,
+			mocksUsed:   true,
+		},
+		{
+			name:        "renew loan | patron blocked",
+			req:         &library.RenewLoanRequest{Id: uuid.NewString()},
+			wantErrCode: codes.FailedPrecondition,
+			wantErr:     entity.ErrPatronBlocked,
+			mocksUsed:   true,
+		},
+		{
+			name:        "renew loan | invalid id",
+			req:         &library.RenewLoanRequest{Id: "loan"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			loanUseCase := mocks.NewMockLoanUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Loan: loanUseCase})
+			ctx := t.Context()
+
+			var want *entity.Loan
+			if tt.wantErr == nil {
+				want = &entity.Loan{
+					ID:       tt.req.GetId(),
+					DueOn:    dueOn,
+					Renewals: 1,
+				}
+			}
+
+			if tt.mocksUsed {
+				loanUseCase.EXPECT().RenewLoan(ctx, tt.req.GetId()).Return(want, tt.wantErr)
+			}
+
+			got, err := service.RenewLoan(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, "2030-02-21", got.GetLoan().GetDueOn())
+				assert.Equal(t, int32(1), got.GetLoan().GetRenewals())
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/admin"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_RetryAllDead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		retried     int64
+		wantErrCode codes.Code
+		wantErr     error
+	}{
+		{
+			name:        "retry all dead",
+			retried:     7,
+			wantErrCode: codes.OK,
+		},
+		{
+			name:        "retry all dead | nothing to retry",
+			wantErrCode: codes.OK,
+		},
+		{
+			name:        "retry all dead | internal error",
+			wantErrCode: codes.Internal,
+			wantErr:     errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			outboxAdminUseCase := mocks.NewMockOutboxAdminUseCase(ctrl)
+			service := controller.NewAdmin(logger, outboxAdminUseCase)
+			ctx := t.Context()
+
+			outboxAdminUseCase.EXPECT().RetryAllDead(ctx).Return(tt.retried, tt.wantErr)
+
+			got, err := service.RetryAllDead(ctx, &admin.RetryAllDeadRequest{})
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.retried, got.GetRetried())
+			}
+		})
+	}
+}
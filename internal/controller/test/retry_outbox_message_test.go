@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/admin"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func Test_RetryOutboxMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *admin.RetryOutboxMessageRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "retry outbox message",
+			req:         &admin.RetryOutboxMessageRequest{IdempotencyKey: "key"},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "retry outbox message | pending",
+			req:         &admin.RetryOutboxMessageRequest{IdempotencyKey: "key"},
+			wantErrCode: codes.FailedPrecondition,
+			wantErr:     entity.ErrOutboxMessagePending,
+			mocksUsed:   true,
+		},
+		{
+			name:        "retry outbox message | not found",
+			req:         &admin.RetryOutboxMessageRequest{IdempotencyKey: "key"},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrOutboxMessageNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "retry outbox message | empty key",
+			req:         &admin.RetryOutboxMessageRequest{},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			outboxAdminUseCase := mocks.NewMockOutboxAdminUseCase(ctrl)
+			service := controller.NewAdmin(logger, outboxAdminUseCase)
+			ctx := t.Context()
+
+			var want *repository.OutboxMessage
+			if tt.wantErr == nil {
+				want = &repository.OutboxMessage{
+					IdempotencyKey: tt.req.GetIdempotencyKey(),
+					Kind:           repository.OutboxKindAuthor,
+					Status:         repository.OutboxStatusCreated,
+				}
+			}
+
+			if tt.mocksUsed {
+				outboxAdminUseCase.EXPECT().RetryOutboxMessage(ctx, tt.req.GetIdempotencyKey()).Return(want, tt.wantErr)
+			}
+
+			got, err := service.RetryOutboxMessage(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.req.GetIdempotencyKey(), got.GetMessage().GetIdempotencyKey())
+				assert.Equal(t, admin.OutboxStatus_OUTBOX_STATUS_CREATED, got.GetMessage().GetStatus())
+				assert.Zero(t, got.GetMessage().GetAttempts())
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_ReturnBook(t *testing.T) {
+	t.Parallel()
+
+	returnedAt := time.Date(2030, time.January, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		req         *library.ReturnBookRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "return book",
+			req:         &library.ReturnBookRequest{Id: uuid.NewString()},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "return book | already returned",
+			req:         &library.ReturnBookRequest{Id: uuid.NewString()},
+			wantErrCode: codes.FailedPrecondition,
+			wantErr:     entity.ErrLoanAlreadyReturned,
+			mocksUsed:   true,
+		},
+		{
+			name:        "return book | not found",
+			req:         &library.ReturnBookRequest{Id: uuid.NewString()},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrLoanNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "return book | invalid id",
+			req:         &library.ReturnBookRequest{Id: "loan"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			loanUseCase := mocks.NewMockLoanUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Loan: loanUseCase})
+			ctx := t.Context()
+
+			var want *entity.Loan
+			if tt.wantErr == nil {
+				want = &entity.Loan{
+					ID:         tt.req.GetId(),
+					ReturnedAt: &returnedAt,
+				}
+			}
+
+			if tt.mocksUsed {
+				loanUseCase.EXPECT().ReturnBook(ctx, tt.req.GetId()).Return(want, tt.wantErr)
+			}
+
+			got, err := service.ReturnBook(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.req.GetId(), got.GetLoan().GetId())
+				assert.Equal(t, returnedAt, got.GetLoan().GetReturnedAt().AsTime())
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_SearchCatalog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		req           *library.SearchCatalogRequest
+		want          []*entity.SearchResult
+		wantKinds     []library.SearchResultKind
+		nextPageToken string
+		wantErrCode   codes.Code
+		wantErr       error
+		mocksUsed     bool
+	}{
+		{
+			name: "search catalog",
+			req: &library.SearchCatalogRequest{
+				Query:    "harry poter",
+				PageSize: 2,
+			},
+			want: []*entity.SearchResult{
+				{
+					Kind:    entity.SearchResultBook,
+					ID:      uuid.NewString(),
+					Name:    "Harry Potter and the Philosopher's Stone",
+					Snippet: "Harry Potter and the Philosopher's Stone",
+					Score:   0.72,
+				},
+				{
+					Kind:    entity.SearchResultAuthor,
+					ID:      uuid.NewString(),
+					Name:    "Harry Harrison",
+					Snippet: "<b>Harry</b> Harrison",
+					Score:   0.1,
+				},
+			},
+			wantKinds: []library.SearchResultKind{
+				library.SearchResultKind_SEARCH_RESULT_KIND_BOOK,
+				library.SearchResultKind_SEARCH_RESULT_KIND_AUTHOR,
+			},
+			nextPageToken: "next",
+			wantErrCode:   codes.OK,
+			mocksUsed:     true,
+		},
+		{
+			name:        "search catalog | nothing found",
+			req:         &library.SearchCatalogRequest{Query: "xyzzy"},
+			want:        []*entity.SearchResult{},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "search catalog | within genre",
+			req: &library.SearchCatalogRequest{
+				Query:   "dune",
+				GenreId: uuid.NewString(),
+			},
+			want:        []*entity.SearchResult{},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "search catalog | invalid genre id",
+			req:         &library.SearchCatalogRequest{Query: "dune", GenreId: "sci-fi"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name:        "search catalog | blank query",
+			req:         &library.SearchCatalogRequest{Query: "   "},
+			wantErrCode: codes.InvalidArgument,
+			wantErr:     entity.ErrEmptySearchQuery,
+			mocksUsed:   true,
+		},
+		{
+			name:        "search catalog | empty query",
+			req:         &library.SearchCatalogRequest{},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "search catalog | page size too large",
+			req: &library.SearchCatalogRequest{
+				Query:    "tolstoy",
+				PageSize: 1000,
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			bookUseCase := mocks.NewMockBooksUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Books: bookUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				filter := entity.SearchFilter{
+					Query:   tt.req.GetQuery(),
+					GenreID: tt.req.GetGenreId(),
+				}
+				bookUseCase.EXPECT().SearchCatalog(ctx, filter,
+					int(tt.req.GetPageSize()), tt.req.GetPageToken()).
+					Return(tt.want, tt.nextPageToken, tt.wantErr)
+			}
+
+			got, err := service.SearchCatalog(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Len(t, got.GetResults(), len(tt.want))
+				for i, result := range tt.want {
+					assert.Equal(t, tt.wantKinds[i], got.GetResults()[i].GetKind())
+					assert.Equal(t, result.ID, got.GetResults()[i].GetId())
+					assert.Equal(t, result.Snippet, got.GetResults()[i].GetSnippet())
+					assert.InDelta(t, result.Score, got.GetResults()[i].GetScore(), 1e-9)
+				}
+				assert.Equal(t, tt.nextPageToken, got.GetNextPageToken())
+			}
+		})
+	}
+}
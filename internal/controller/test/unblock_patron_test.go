@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_UnblockPatron(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.UnblockPatronRequest
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name:        "unblock patron",
+			req:         &library.UnblockPatronRequest{Id: uuid.NewString()},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name:        "unblock patron | not found",
+			req:         &library.UnblockPatronRequest{Id: uuid.NewString()},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrPatronNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name:        "unblock patron | invalid id",
+			req:         &library.UnblockPatronRequest{Id: "patron"},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			patronUseCase := mocks.NewMockPatronUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Patron: patronUseCase})
+			ctx := t.Context()
+
+			var want *entity.Patron
+			if tt.wantErr == nil {
+				want = &entity.Patron{
+					ID:     tt.req.GetId(),
+					Status: entity.PatronStatusActive,
+				}
+			}
+
+			if tt.mocksUsed {
+				patronUseCase.EXPECT().UnblockPatron(ctx, tt.req.GetId()).Return(want, tt.wantErr)
+			}
+
+			got, err := service.UnblockPatron(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, library.PatronStatus_PATRON_STATUS_ACTIVE, got.GetPatron().GetStatus())
+				assert.Empty(t, got.GetPatron().GetBlockReason())
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_UndeleteAuthor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *library.UndeleteAuthorRequest
+		want        *entity.Author
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "undelete author",
+			req: &library.UndeleteAuthorRequest{
+				Id: uuid.NewString(),
+			},
+			want: &entity.Author{
+				ID:   uuid.NewString(),
+				Name: "Name",
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "undelete author | not found",
+			req: &library.UndeleteAuthorRequest{
+				Id: uuid.NewString(),
+			},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrAuthorNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name: "undelete author | invalid argument",
+			req: &library.UndeleteAuthorRequest{
+				Id: "invalid-id",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			authorUseCase := mocks.NewMockAuthorUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Author: authorUseCase})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				authorUseCase.EXPECT().UndeleteAuthor(ctx, tt.req.GetId()).Return(tt.want, tt.wantErr)
+			}
+
+			got, err := service.UndeleteAuthor(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil && tt.want != nil {
+				assert.Equal(t, tt.want.ID, got.GetId())
+				assert.Equal(t, tt.want.Name, got.GetName())
+			}
+		})
+	}
+}
@@ -2,13 +2,18 @@ package controller
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/project/library/generated/api/library"
 	"github.com/project/library/internal/controller"
@@ -20,16 +25,25 @@ import (
 func Test_UpdateBook(t *testing.T) {
 	t.Parallel()
 
+	authorID := uuid.NewString()
+	otherAuthorID := uuid.NewString()
+	publicationYear := 2001
+
 	type args struct {
-		ctx context.Context
-		req *library.UpdateBookRequest
+		ctx     context.Context
+		req     *library.UpdateBookRequest
+		ifMatch string
+		// patchFields are the fields of the PATCH body, nil for PUT.
+		patchFields []string
 	}
 	tests := []struct {
-		name        string
-		args        args
-		wantErrCode codes.Code
-		wantErr     error
-		mocksUsed   bool
+		name                string
+		args                args
+		wantExpectedVersion int64
+		wantUpdate          *entity.BookUpdate
+		wantErrCode         codes.Code
+		wantErr             error
+		mocksUsed           bool
 	}{
 		{
 			name: "successful update",
@@ -45,6 +59,81 @@ func Test_UpdateBook(t *testing.T) {
 			wantErr:     nil,
 			mocksUsed:   true,
 		},
+		{
+			name: "update with expected version",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:              uuid.NewString(),
+					Name:            "New Book Name",
+					AuthorIds:       []string{uuid.NewString()},
+					ExpectedVersion: 3,
+				},
+				ifMatch: `"5"`,
+			},
+			wantExpectedVersion: 3,
+			wantErrCode:         codes.OK,
+			mocksUsed:           true,
+		},
+		{
+			name: "update with If-Match",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:        uuid.NewString(),
+					Name:      "New Book Name",
+					AuthorIds: []string{uuid.NewString()},
+				},
+				ifMatch: `"5"`,
+			},
+			wantExpectedVersion: 5,
+			wantErrCode:         codes.OK,
+			mocksUsed:           true,
+		},
+		{
+			name: "update with If-Match any",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:        uuid.NewString(),
+					Name:      "New Book Name",
+					AuthorIds: []string{uuid.NewString()},
+				},
+				ifMatch: "*",
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "invalid If-Match",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:        uuid.NewString(),
+					Name:      "New Book Name",
+					AuthorIds: []string{uuid.NewString()},
+				},
+				ifMatch: `W/"5"`,
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "version mismatch",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:              uuid.NewString(),
+					Name:            "New Book Name",
+					AuthorIds:       []string{uuid.NewString()},
+					ExpectedVersion: 1,
+				},
+			},
+			wantExpectedVersion: 1,
+			wantErrCode:         codes.Aborted,
+			wantErr:             entity.ErrVersionMismatch,
+			mocksUsed:           true,
+		},
 		{
 			name: "invalid request",
 			args: args{
@@ -73,6 +162,192 @@ func Test_UpdateBook(t *testing.T) {
 			wantErr:     entity.ErrBookNotFound,
 			mocksUsed:   true,
 		},
+		{
+			name: "rename with update mask",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:         uuid.NewString(),
+					Name:       "New Book Name",
+					UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+				},
+			},
+			wantUpdate: &entity.BookUpdate{
+				Name: proto.String("New Book Name"),
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "replace authors with update mask",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:         uuid.NewString(),
+					AuthorIds:  []string{authorID},
+					UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"author_ids"}},
+				},
+			},
+			wantUpdate: &entity.BookUpdate{
+				AuthorIDs:      []string{authorID},
+				ReplaceAuthors: true,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "add and remove authors",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:              uuid.NewString(),
+					AddAuthorIds:    []string{authorID},
+					RemoveAuthorIds: []string{otherAuthorID},
+				},
+			},
+			wantUpdate: &entity.BookUpdate{
+				AddAuthorIDs:    []string{authorID},
+				RemoveAuthorIDs: []string{otherAuthorID},
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "update metadata with update mask",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:              uuid.NewString(),
+					Isbn:            "9780306406157",
+					PublicationYear: 2001,
+					UpdateMask: &fieldmaskpb.FieldMask{
+						Paths: []string{"isbn", "publication_year"},
+					},
+				},
+			},
+			wantUpdate: &entity.BookUpdate{
+				ISBN:            proto.String("9780306406157"),
+				PublicationYear: &publicationYear,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "unknown update mask path",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:         uuid.NewString(),
+					Name:       "New Book Name",
+					UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+				},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "empty name in update mask",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:         uuid.NewString(),
+					UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+				},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "author_ids path with add_author_ids",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:           uuid.NewString(),
+					AuthorIds:    []string{authorID},
+					AddAuthorIds: []string{otherAuthorID},
+					UpdateMask:   &fieldmaskpb.FieldMask{Paths: []string{"author_ids"}},
+				},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "patch name keeps authors",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:   uuid.NewString(),
+					Name: "New Book Name",
+				},
+				patchFields: []string{"name"},
+			},
+			wantUpdate: &entity.BookUpdate{
+				Name: proto.String("New Book Name"),
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "patch json names",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:              uuid.NewString(),
+					PublicationYear: 2001,
+					AddAuthorIds:    []string{authorID},
+					ExpectedVersion: 2,
+				},
+				patchFields: []string{"addAuthorIds", "expectedVersion", "publicationYear"},
+			},
+			wantExpectedVersion: 2,
+			wantUpdate: &entity.BookUpdate{
+				AddAuthorIDs:    []string{authorID},
+				PublicationYear: &publicationYear,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "patch with update mask",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:         uuid.NewString(),
+					Name:       "New Book Name",
+					Isbn:       "9780306406157",
+					UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+				},
+				patchFields: []string{"isbn", "name", "update_mask"},
+			},
+			wantUpdate: &entity.BookUpdate{
+				Name: proto.String("New Book Name"),
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "patch without fields",
+			args: args{
+				ctx:         context.Background(),
+				req:         &library.UpdateBookRequest{Id: uuid.NewString()},
+				patchFields: []string{},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "author both added and removed",
+			args: args{
+				ctx: context.Background(),
+				req: &library.UpdateBookRequest{
+					Id:              uuid.NewString(),
+					AddAuthorIds:    []string{authorID},
+					RemoveAuthorIds: []string{authorID},
+				},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -83,20 +358,41 @@ func Test_UpdateBook(t *testing.T) {
 			t.Cleanup(ctrl.Finish)
 
 			logger, _ := zap.NewProduction()
-			authorUseCase := mocks.NewMockAuthorUseCase(ctrl)
 			bookUseCase := mocks.NewMockBooksUseCase(ctrl)
-			service := controller.New(logger, bookUseCase, authorUseCase)
+			service := controller.New(logger, controller.UseCases{Books: bookUseCase})
 
-			ctx := t.Context()
+			md := metadata.MD{}
+			if tt.args.ifMatch != "" {
+				md.Set("if-match", tt.args.ifMatch)
+			}
+			if tt.args.patchFields != nil {
+				md.Set("x-patch-fields", strings.Join(tt.args.patchFields, ","))
+			}
+			ctx := metadata.NewIncomingContext(t.Context(), md)
 
 			if tt.mocksUsed {
+				update := tt.wantUpdate
+				if update == nil {
+					update = &entity.BookUpdate{
+						Name:           proto.String(tt.args.req.GetName()),
+						AuthorIDs:      tt.args.req.GetAuthorIds(),
+						ReplaceAuthors: true,
+					}
+				}
+
 				bookUseCase.EXPECT().UpdateBook(ctx, tt.args.req.GetId(),
-					tt.args.req.GetName(), tt.args.req.GetAuthorIds()).
-					Return(tt.wantErr)
+					*update, tt.wantExpectedVersion).
+					Return(&entity.Book{
+						ID:      tt.args.req.GetId(),
+						Version: tt.wantExpectedVersion + 1,
+					}, tt.wantErr)
 			}
 
-			_, err := service.UpdateBook(ctx, tt.args.req)
+			got, err := service.UpdateBook(ctx, tt.args.req)
 			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, tt.wantExpectedVersion+1, got.GetVersion())
+			}
 		})
 	}
 }
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_UpdateCopy(t *testing.T) {
+	t.Parallel()
+
+	acquiredOn := time.Date(2023, time.September, 1, 0, 0, 0, 0, time.UTC)
+	lost := entity.CopyStatusLost
+	damaged := entity.CopyConditionDamaged
+
+	tests := []struct {
+		name        string
+		req         *library.UpdateCopyRequest
+		wantUpdate  entity.CopyUpdate
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "move copy to another shelf",
+			req: &library.UpdateCopyRequest{
+				Id:            uuid.NewString(),
+				Branch:        "North",
+				ShelfLocation: "B-3",
+				UpdateMask:    &fieldmaskpb.FieldMask{Paths: []string{"branch", "shelf_location"}},
+			},
+			wantUpdate: entity.CopyUpdate{
+				Branch:        proto.String("North"),
+				ShelfLocation: proto.String("B-3"),
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "mark copy lost and damaged",
+			req: &library.UpdateCopyRequest{
+				Id:         uuid.NewString(),
+				Status:     library.CopyStatus_COPY_STATUS_LOST,
+				Condition:  library.CopyCondition_COPY_CONDITION_DAMAGED,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status", "condition"}},
+			},
+			wantUpdate: entity.CopyUpdate{
+				Status:    &lost,
+				Condition: &damaged,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "set acquisition date",
+			req: &library.UpdateCopyRequest{
+				Id:         uuid.NewString(),
+				AcquiredOn: "2023-09-01",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"acquired_on"}},
+			},
+			wantUpdate: entity.CopyUpdate{
+				AcquiredOn:    &acquiredOn,
+				SetAcquiredOn: true,
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "clear acquisition date",
+			req: &library.UpdateCopyRequest{
+				Id:         uuid.NewString(),
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"acquired_on"}},
+			},
+			wantUpdate:  entity.CopyUpdate{SetAcquiredOn: true},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "update copy | not found",
+			req: &library.UpdateCopyRequest{
+				Id:            uuid.NewString(),
+				ShelfLocation: "B-3",
+				UpdateMask:    &fieldmaskpb.FieldMask{Paths: []string{"shelf_location"}},
+			},
+			wantUpdate:  entity.CopyUpdate{ShelfLocation: proto.String("B-3")},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrCopyNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name: "update copy | without update mask",
+			req: &library.UpdateCopyRequest{
+				Id:     uuid.NewString(),
+				Branch: "North",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "update copy | unspecified status",
+			req: &library.UpdateCopyRequest{
+				Id:         uuid.NewString(),
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"status"}},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "update copy | empty branch",
+			req: &library.UpdateCopyRequest{
+				Id:         uuid.NewString(),
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"branch"}},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "update copy | unknown path",
+			req: &library.UpdateCopyRequest{
+				Id:         uuid.NewString(),
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"barcode"}},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			copyUseCase := mocks.NewMockCopyUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Copy: copyUseCase})
+			ctx := t.Context()
+
+			var want *entity.Copy
+			if tt.wantErr == nil {
+				want = &entity.Copy{
+					ID:     tt.req.GetId(),
+					Status: entity.CopyStatusAvailable,
+				}
+			}
+
+			if tt.mocksUsed {
+				copyUseCase.EXPECT().UpdateCopy(ctx, tt.req.GetId(), tt.wantUpdate).
+					Return(want, tt.wantErr)
+			}
+
+			got, err := service.UpdateCopy(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, want.ID, got.GetCopy().GetId())
+			}
+		})
+	}
+}
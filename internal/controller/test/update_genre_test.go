@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_UpdateGenre(t *testing.T) {
+	t.Parallel()
+
+	parentID := uuid.NewString()
+
+	tests := []struct {
+		name        string
+		req         *library.UpdateGenreRequest
+		wantUpdate  entity.GenreUpdate
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "rename genre",
+			req: &library.UpdateGenreRequest{
+				Id:         uuid.NewString(),
+				Name:       "Sci-Fi",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+			},
+			wantUpdate:  entity.GenreUpdate{Name: proto.String("Sci-Fi")},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "move genre",
+			req: &library.UpdateGenreRequest{
+				Id:         uuid.NewString(),
+				ParentId:   parentID,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"parent_id"}},
+			},
+			wantUpdate:  entity.GenreUpdate{ParentID: proto.String(parentID)},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "move genre to the root",
+			req: &library.UpdateGenreRequest{
+				Id:         uuid.NewString(),
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"parent_id"}},
+			},
+			wantUpdate:  entity.GenreUpdate{ParentID: proto.String("")},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "move genre under its subgenre",
+			req: &library.UpdateGenreRequest{
+				Id:         uuid.NewString(),
+				ParentId:   parentID,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"parent_id"}},
+			},
+			wantUpdate:  entity.GenreUpdate{ParentID: proto.String(parentID)},
+			wantErrCode: codes.InvalidArgument,
+			wantErr:     entity.ErrGenreCycle,
+			mocksUsed:   true,
+		},
+		{
+			name: "update genre | without update mask",
+			req: &library.UpdateGenreRequest{
+				Id:   uuid.NewString(),
+				Name: "Sci-Fi",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "update genre | empty name",
+			req: &library.UpdateGenreRequest{
+				Id:         uuid.NewString(),
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "update genre | unknown path",
+			req: &library.UpdateGenreRequest{
+				Id:         uuid.NewString(),
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"books"}},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			genreUseCase := mocks.NewMockGenreUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Genre: genreUseCase})
+			ctx := t.Context()
+
+			want := &entity.Genre{
+				ID:       tt.req.GetId(),
+				Name:     tt.req.GetName(),
+				ParentID: tt.req.GetParentId(),
+			}
+			if tt.wantErr != nil {
+				want = nil
+			}
+
+			if tt.mocksUsed {
+				genreUseCase.EXPECT().UpdateGenre(ctx, tt.req.GetId(), tt.wantUpdate).
+					Return(want, tt.wantErr)
+			}
+
+			got, err := service.UpdateGenre(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, want.ID, got.GetGenre().GetId())
+				assert.Equal(t, want.ParentID, got.GetGenre().GetParentId())
+			}
+		})
+	}
+}
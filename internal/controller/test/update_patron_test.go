@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+)
+
+func Test_UpdatePatron(t *testing.T) {
+	t.Parallel()
+
+	expiresOn := time.Date(2031, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		req         *library.UpdatePatronRequest
+		wantUpdate  entity.PatronUpdate
+		wantErrCode codes.Code
+		wantErr     error
+		mocksUsed   bool
+	}{
+		{
+			name: "update contact details",
+			req: &library.UpdatePatronRequest{
+				Id:         uuid.NewString(),
+				Email:      "ada@example.org",
+				Phone:      "+44 20 7946 0001",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"email", "phone", "address"}},
+			},
+			wantUpdate: entity.PatronUpdate{
+				Email:   proto.String("ada@example.org"),
+				Phone:   proto.String("+44 20 7946 0001"),
+				Address: proto.String(""),
+			},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "renew membership",
+			req: &library.UpdatePatronRequest{
+				Id:                  uuid.NewString(),
+				MembershipExpiresOn: "2031-06-30",
+				UpdateMask:          &fieldmaskpb.FieldMask{Paths: []string{"membership_expires_on"}},
+			},
+			wantUpdate:  entity.PatronUpdate{MembershipExpiresOn: &expiresOn},
+			wantErrCode: codes.OK,
+			mocksUsed:   true,
+		},
+		{
+			name: "update patron | not found",
+			req: &library.UpdatePatronRequest{
+				Id:         uuid.NewString(),
+				Name:       "Ada King",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+			},
+			wantUpdate:  entity.PatronUpdate{Name: proto.String("Ada King")},
+			wantErrCode: codes.NotFound,
+			wantErr:     entity.ErrPatronNotFound,
+			mocksUsed:   true,
+		},
+		{
+			name: "update patron | without update mask",
+			req: &library.UpdatePatronRequest{
+				Id:   uuid.NewString(),
+				Name: "Ada King",
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "update patron | empty expiry date",
+			req: &library.UpdatePatronRequest{
+				Id:         uuid.NewString(),
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"membership_expires_on"}},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+		{
+			name: "update patron | card number is read only",
+			req: &library.UpdatePatronRequest{
+				Id:         uuid.NewString(),
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"card_number"}},
+			},
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			patronUseCase := mocks.NewMockPatronUseCase(ctrl)
+			service := controller.New(logger, controller.UseCases{Patron: patronUseCase})
+			ctx := t.Context()
+
+			var want *entity.Patron
+			if tt.wantErr == nil {
+				want = &entity.Patron{ID: tt.req.GetId()}
+			}
+
+			if tt.mocksUsed {
+				patronUseCase.EXPECT().UpdatePatron(ctx, tt.req.GetId(), tt.wantUpdate).
+					Return(want, tt.wantErr)
+			}
+
+			got, err := service.UpdatePatron(ctx, tt.req)
+			testutils.CheckError(t, err, tt.wantErrCode)
+			if err == nil {
+				assert.Equal(t, want.ID, got.GetPatron().GetId())
+			}
+		})
+	}
+}
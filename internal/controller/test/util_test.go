@@ -5,24 +5,19 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	service_ "github.com/project/library/internal/controller"
 	"github.com/project/library/internal/entity"
-	"github.com/project/library/internal/usecase/library/mocks"
 )
 
 func TestConvertErr(t *testing.T) {
 	t.Parallel()
 
-	ctrl := gomock.NewController(t)
 	logger, _ := zap.NewProduction()
-	authorUseCase := mocks.NewMockAuthorUseCase(ctrl)
-	bookUseCase := mocks.NewMockBooksUseCase(ctrl)
-	service := service_.New(logger, bookUseCase, authorUseCase)
+	service := service_.New(logger, service_.UseCases{})
 
 	tests := []struct {
 		name     string
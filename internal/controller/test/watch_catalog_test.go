@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/project/library/generated/api/catalog"
+	"github.com/project/library/internal/controller"
+	"github.com/project/library/internal/entity"
+	catalogusecase "github.com/project/library/internal/usecase/catalog"
+	"github.com/project/library/internal/usecase/catalog/mocks"
+	testutils "github.com/project/library/internal/usecase/library/test"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+type mockCatalogWatchServer struct {
+	grpc.ServerStream
+	changes []*catalog.CatalogChange
+	ctx     context.Context
+}
+
+func (m *mockCatalogWatchServer) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockCatalogWatchServer) Send(change *catalog.CatalogChange) error {
+	m.changes = append(m.changes, change)
+	return nil
+}
+
+func Test_WatchCatalog(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	change := &repository.CatalogChange{
+		Position:  7,
+		Kind:      repository.OutboxKindBookUpdated,
+		EntityID:  "book-1",
+		RawData:   []byte(`{"Before":null,"After":null}`),
+		TraceID:   "trace-1",
+		CreatedAt: createdAt,
+	}
+
+	tests := []struct {
+		name        string
+		req         *catalog.WatchCatalogRequest
+		watchErr    error
+		wantErrCode codes.Code
+		wantSent    int
+		mocksUsed   bool
+	}{
+		{
+			name:        "watch catalog | until the client leaves",
+			req:         &catalog.WatchCatalogRequest{ResumeToken: "token-6"},
+			wantErrCode: codes.Canceled,
+			wantSent:    1,
+			mocksUsed:   true,
+		},
+		{
+			name:        "watch catalog | invalid resume token",
+			req:         &catalog.WatchCatalogRequest{ResumeToken: "token"},
+			watchErr:    entity.ErrInvalidResumeToken,
+			wantErrCode: codes.InvalidArgument,
+			mocksUsed:   true,
+		},
+		{
+			name:        "watch catalog | too long resume token",
+			req:         &catalog.WatchCatalogRequest{ResumeToken: strings.Repeat("a", 257)},
+			wantErrCode: codes.InvalidArgument,
+		},
+		{
+			name:        "watch catalog | database error",
+			req:         &catalog.WatchCatalogRequest{},
+			watchErr:    errors.New("db error"),
+			wantErrCode: codes.Internal,
+			mocksUsed:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			logger, _ := zap.NewProduction()
+			catalogService := mocks.NewMockCatalog(ctrl)
+			service := controller.NewCatalog(logger, catalogService)
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+			server := &mockCatalogWatchServer{ctx: ctx}
+
+			if tt.mocksUsed {
+				catalogService.EXPECT().Watch(ctx, tt.req.GetResumeToken(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, _ string, send catalogusecase.SendFunc) error {
+						if tt.watchErr != nil {
+							return tt.watchErr
+						}
+
+						if err := send(change, "token-7"); err != nil {
+							return err
+						}
+
+						cancel()
+						return ctx.Err()
+					},
+				)
+			}
+
+			err := service.WatchCatalog(tt.req, server)
+			testutils.CheckError(t, err, tt.wantErrCode)
+
+			assert.Len(t, server.changes, tt.wantSent)
+			if tt.wantSent > 0 {
+				got := server.changes[0]
+				assert.Equal(t, "token-7", got.GetResumeToken())
+				assert.Equal(t, "library.book.updated", got.GetType())
+				assert.Equal(t, "book-1", got.GetEntityId())
+				assert.Equal(t, string(change.RawData), got.GetData())
+				assert.Equal(t, "trace-1", got.GetTraceId())
+				assert.Equal(t, createdAt, got.GetOccurredAt().AsTime())
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+)
+
+func (i *impl) UndeleteAuthor(
+	ctx context.Context,
+	req *library.UndeleteAuthorRequest,
+) (*library.UndeleteAuthorResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("author.id", req.GetId()))
+
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("author_id", req.GetId()),
+	)
+
+	log.Info("start UndeleteAuthor")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	author, err := i.authorUseCase.UndeleteAuthor(ctx, req.GetId())
+	if err != nil {
+		return nil, i.handleError(span, err, "UndeleteAuthor")
+	}
+
+	log.Info("successfully finished UndeleteAuthor")
+
+	return &library.UndeleteAuthorResponse{
+		Id:   author.ID,
+		Name: author.Name,
+	}, nil
+}
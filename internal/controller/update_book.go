@@ -2,14 +2,21 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
 )
 
 func (i *impl) UpdateBook(
@@ -35,13 +42,171 @@ func (i *impl) UpdateBook(
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	err := i.booksUseCase.UpdateBook(ctx, req.GetId(),
-		req.GetName(), req.GetAuthorIds())
+	update, err := newBookUpdate(ctx, req)
+	if err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	expected, err := expectedVersion(ctx, req.GetExpectedVersion())
+	if err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, err
+	}
+
+	book, err := i.booksUseCase.UpdateBook(ctx, req.GetId(), update, expected)
 	if err != nil {
 		return nil, i.handleError(span, err, "UpdateBook")
 	}
 
+	i.setETag(ctx, book.Version)
+
 	log.Info("successfully finished UpdateBook")
 
-	return &library.UpdateBookResponse{}, nil
+	return &library.UpdateBookResponse{
+		Version: book.Version,
+	}, nil
+}
+
+const (
+	bookNamePath            = "name"
+	bookAuthorIDsPath       = "author_ids"
+	bookISBNPath            = "isbn"
+	bookPublisherPath       = "publisher"
+	bookPublicationYearPath = "publication_year"
+	bookLanguagePath        = "language"
+	bookPageCountPath       = "page_count"
+	bookDescriptionPath     = "description"
+	bookGenreIDsPath        = "genre_ids"
+	bookTagsPath            = "tags"
+)
+
+// patchFieldsHeader lists the top-level fields of a PATCH body; the
+// gateway sets it because proto3 can't tell an absent field from an empty
+// one.
+const patchFieldsHeader = "x-patch-fields"
+
+// newBookUpdate applies the update_mask rules of UpdateBookRequest. A PATCH
+// without update_mask updates the fields present in the body; only the
+// legacy PUT falls back to replacing the name and the authors.
+func newBookUpdate(ctx context.Context, req *library.UpdateBookRequest) (entity.BookUpdate, error) {
+	update := entity.BookUpdate{
+		AddAuthorIDs:    req.GetAddAuthorIds(),
+		RemoveAuthorIDs: req.GetRemoveAuthorIds(),
+	}
+	changesAuthors := len(update.AddAuthorIDs) > 0 || len(update.RemoveAuthorIDs) > 0
+
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		fields, isPatch := patchFields(ctx)
+		switch {
+		case isPatch:
+			paths = bookPathsFromFields(fields)
+			if len(paths) == 0 && !changesAuthors {
+				return entity.BookUpdate{}, errors.New("nothing to update: no fields and no update_mask")
+			}
+		case !changesAuthors:
+			paths = []string{bookNamePath, bookAuthorIDsPath}
+		}
+	}
+
+	for _, path := range paths {
+		switch path {
+		case bookNamePath:
+			if req.GetName() == "" {
+				return entity.BookUpdate{}, errors.New("name must not be empty")
+			}
+			update.Name = ptr(req.GetName())
+		case bookAuthorIDsPath:
+			update.AuthorIDs = req.GetAuthorIds()
+			update.ReplaceAuthors = true
+		case bookISBNPath:
+			update.ISBN = ptr(req.GetIsbn())
+		case bookPublisherPath:
+			update.Publisher = ptr(req.GetPublisher())
+		case bookPublicationYearPath:
+			update.PublicationYear = ptr(int(req.GetPublicationYear()))
+		case bookLanguagePath:
+			update.Language = ptr(req.GetLanguage())
+		case bookPageCountPath:
+			update.PageCount = ptr(int(req.GetPageCount()))
+		case bookDescriptionPath:
+			update.Description = ptr(req.GetDescription())
+		case bookGenreIDsPath:
+			update.GenreIDs = req.GetGenreIds()
+			update.ReplaceGenres = true
+		case bookTagsPath:
+			update.Tags = req.GetTags()
+			update.ReplaceTags = true
+		default:
+			return entity.BookUpdate{}, fmt.Errorf("unknown update_mask path %q", path)
+		}
+	}
+
+	if update.ReplaceAuthors && (len(update.AddAuthorIDs) > 0 || len(update.RemoveAuthorIDs) > 0) {
+		return entity.BookUpdate{}, errors.New(
+			"add_author_ids and remove_author_ids can't be combined with author_ids")
+	}
+
+	for _, removed := range update.RemoveAuthorIDs {
+		if slices.Contains(update.AddAuthorIDs, removed) {
+			return entity.BookUpdate{}, fmt.Errorf("author %s is both added and removed", removed)
+		}
+	}
+
+	return update, nil
+}
+
+// patchFields returns the fields of the PATCH body the request came with,
+// and false when it didn't come through PATCH.
+func patchFields(ctx context.Context) ([]string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	values := md.Get(patchFieldsHeader)
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	if values[0] == "" {
+		return nil, true
+	}
+
+	return strings.Split(values[0], ","), true
+}
+
+// bookPathsFromFields turns the JSON fields of a PATCH body, in either
+// the proto or the JSON name, into update_mask paths.
+func bookPathsFromFields(fields []string) []string {
+	paths := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		path := snakeCase(field)
+		switch path {
+		case "id", "expected_version", "update_mask", "add_author_ids", "remove_author_ids":
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+
+	for _, r := range name {
+		if unicode.IsUpper(r) {
+			b.WriteByte('_')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
 }
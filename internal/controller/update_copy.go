@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+)
+
+func (i *impl) UpdateCopy(
+	ctx context.Context,
+	req *library.UpdateCopyRequest,
+) (*library.UpdateCopyResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("copy.id", req.GetId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("copy_id", req.GetId()),
+	)
+
+	log.Info("start UpdateCopy")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	update, err := newCopyUpdate(req)
+	if err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	bookCopy, err := i.copyUseCase.UpdateCopy(ctx, req.GetId(), update)
+	if err != nil {
+		return nil, i.handleError(span, err, "UpdateCopy")
+	}
+
+	log.Info("successfully finished UpdateCopy")
+
+	return &library.UpdateCopyResponse{
+		Copy: newCopy(bookCopy),
+	}, nil
+}
+
+const (
+	copyBranchPath        = "branch"
+	copyShelfLocationPath = "shelf_location"
+	copyConditionPath     = "condition"
+	copyAcquiredOnPath    = "acquired_on"
+	copyStatusPath        = "status"
+)
+
+// newCopyUpdate applies the update_mask rules of UpdateCopyRequest.
+func newCopyUpdate(req *library.UpdateCopyRequest) (entity.CopyUpdate, error) {
+	var update entity.CopyUpdate
+
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return entity.CopyUpdate{}, errors.New("update_mask must not be empty")
+	}
+
+	for _, path := range paths {
+		switch path {
+		case copyBranchPath:
+			if req.GetBranch() == "" {
+				return entity.CopyUpdate{}, errors.New("branch must not be empty")
+			}
+			update.Branch = ptr(req.GetBranch())
+		case copyShelfLocationPath:
+			update.ShelfLocation = ptr(req.GetShelfLocation())
+		case copyConditionPath:
+			condition, ok := copyConditionFromProto[req.GetCondition()]
+			if !ok {
+				return entity.CopyUpdate{}, errors.New("condition must be specified")
+			}
+			update.Condition = &condition
+		case copyAcquiredOnPath:
+			acquiredOn, err := parseDate("acquired_on", req.GetAcquiredOn())
+			if err != nil {
+				return entity.CopyUpdate{}, err
+			}
+			update.AcquiredOn = acquiredOn
+			update.SetAcquiredOn = true
+		case copyStatusPath:
+			copyStatus, ok := copyStatusFromProto[req.GetStatus()]
+			if !ok {
+				return entity.CopyUpdate{}, errors.New("status must be specified")
+			}
+			update.Status = &copyStatus
+		default:
+			return entity.CopyUpdate{}, fmt.Errorf("unknown update_mask path %q", path)
+		}
+	}
+
+	return update, nil
+}
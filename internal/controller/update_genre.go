@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+)
+
+func (i *impl) UpdateGenre(
+	ctx context.Context,
+	req *library.UpdateGenreRequest,
+) (*library.UpdateGenreResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("genre.id", req.GetId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("genre_id", req.GetId()),
+	)
+
+	log.Info("start UpdateGenre")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	update, err := newGenreUpdate(req)
+	if err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	genre, err := i.genreUseCase.UpdateGenre(ctx, req.GetId(), update)
+	if err != nil {
+		return nil, i.handleError(span, err, "UpdateGenre")
+	}
+
+	log.Info("successfully finished UpdateGenre")
+
+	return &library.UpdateGenreResponse{
+		Genre: newGenre(genre),
+	}, nil
+}
+
+const (
+	genreNamePath     = "name"
+	genreParentIDPath = "parent_id"
+)
+
+// newGenreUpdate applies the update_mask rules of UpdateGenreRequest.
+func newGenreUpdate(req *library.UpdateGenreRequest) (entity.GenreUpdate, error) {
+	var update entity.GenreUpdate
+
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return entity.GenreUpdate{}, errors.New("update_mask must not be empty")
+	}
+
+	for _, path := range paths {
+		switch path {
+		case genreNamePath:
+			if req.GetName() == "" {
+				return entity.GenreUpdate{}, errors.New("name must not be empty")
+			}
+			update.Name = ptr(req.GetName())
+		case genreParentIDPath:
+			update.ParentID = ptr(req.GetParentId())
+		default:
+			return entity.GenreUpdate{}, fmt.Errorf("unknown update_mask path %q", path)
+		}
+	}
+
+	return update, nil
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/library"
+	"github.com/project/library/internal/entity"
+)
+
+func (i *impl) UpdatePatron(
+	ctx context.Context,
+	req *library.UpdatePatronRequest,
+) (*library.UpdatePatronResponse, error) {
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+	span.SetAttributes(attribute.String("patron.id", req.GetId()))
+	defer span.End()
+
+	log := i.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+		zap.String("patron_id", req.GetId()),
+	)
+
+	log.Info("start UpdatePatron")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	update, err := newPatronUpdate(req)
+	if err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	patron, err := i.patronUseCase.UpdatePatron(ctx, req.GetId(), update)
+	if err != nil {
+		return nil, i.handleError(span, err, "UpdatePatron")
+	}
+
+	log.Info("successfully finished UpdatePatron")
+
+	return &library.UpdatePatronResponse{
+		Patron: newPatron(patron),
+	}, nil
+}
+
+const (
+	patronNamePath                = "name"
+	patronEmailPath               = "email"
+	patronPhonePath               = "phone"
+	patronAddressPath             = "address"
+	patronMembershipExpiresOnPath = "membership_expires_on"
+)
+
+// newPatronUpdate applies the update_mask rules of UpdatePatronRequest.
+func newPatronUpdate(req *library.UpdatePatronRequest) (entity.PatronUpdate, error) {
+	var update entity.PatronUpdate
+
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return entity.PatronUpdate{}, errors.New("update_mask must not be empty")
+	}
+
+	for _, path := range paths {
+		switch path {
+		case patronNamePath:
+			if req.GetName() == "" {
+				return entity.PatronUpdate{}, errors.New("name must not be empty")
+			}
+			update.Name = ptr(req.GetName())
+		case patronEmailPath:
+			update.Email = ptr(req.GetEmail())
+		case patronPhonePath:
+			update.Phone = ptr(req.GetPhone())
+		case patronAddressPath:
+			update.Address = ptr(req.GetAddress())
+		case patronMembershipExpiresOnPath:
+			expiresOn, err := parseDate("membership_expires_on", req.GetMembershipExpiresOn())
+			if err != nil {
+				return entity.PatronUpdate{}, err
+			}
+			if expiresOn == nil {
+				return entity.PatronUpdate{}, errors.New("membership_expires_on must not be empty")
+			}
+			update.MembershipExpiresOn = expiresOn
+		default:
+			return entity.PatronUpdate{}, fmt.Errorf("unknown update_mask path %q", path)
+		}
+	}
+
+	return update, nil
+}
@@ -26,12 +26,46 @@ func (i *impl) handleError(
 	span trace.Span,
 	err error,
 	operation string,
+) error {
+	return handleError(i.logger, span, err, operation)
+}
+
+func (a *adminImpl) handleError(
+	span trace.Span,
+	err error,
+	operation string,
+) error {
+	return handleError(a.logger, span, err, operation)
+}
+
+func (i *inboxImpl) handleError(
+	span trace.Span,
+	err error,
+	operation string,
+) error {
+	return handleError(i.logger, span, err, operation)
+}
+
+func (c *catalogImpl) handleError(
+	span trace.Span,
+	err error,
+	operation string,
+) error {
+	return handleError(c.logger, span, err, operation)
+}
+
+// handleError logs err and turns it into a gRPC status.
+func handleError(
+	logger *zap.Logger,
+	span trace.Span,
+	err error,
+	operation string,
 ) error {
 	if err == nil {
 		return nil
 	}
 
-	log := i.logger.With(
+	log := logger.With(
 		zap.String("operation", operation),
 		zap.Error(err),
 	)
@@ -72,6 +106,7 @@ func isClientError(code codes.Code) bool {
 		codes.PermissionDenied,
 		codes.Unauthenticated,
 		codes.FailedPrecondition,
+		codes.Aborted,
 		codes.OutOfRange,
 		codes.Unimplemented,
 		codes.ResourceExhausted:
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/generated/api/catalog"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func (c *catalogImpl) WatchCatalog(
+	req *catalog.WatchCatalogRequest,
+	server catalog.CatalogService_WatchCatalogServer,
+) error {
+	ctx := server.Context()
+	span := trace.SpanFromContext(ctx)
+	spanCtx := span.SpanContext()
+
+	defer span.End()
+
+	log := c.logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+		zap.String("layer", "controller"),
+	)
+
+	log.Info("start WatchCatalog")
+
+	if err := req.ValidateAll(); err != nil {
+		log.Warn("invalid data", zap.Error(err))
+		span.RecordError(err)
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sent := 0
+	err := c.catalog.Watch(ctx, req.GetResumeToken(),
+		func(change *repository.CatalogChange, resumeToken string) error {
+			sent++
+			return server.Send(newCatalogChange(change, resumeToken))
+		})
+
+	// The watch goes on until the client leaves.
+	if ctx.Err() != nil {
+		log.Info("finished WatchCatalog", zap.Int("sent", sent))
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	return c.handleError(span, err, "WatchCatalog")
+}
@@ -1,15 +1,66 @@
 package entity
 
 import (
+	"time"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type Author struct {
-	ID   string
+	ID             string
+	Name           string
+	BirthDate      *time.Time
+	DeathDate      *time.Time
+	Nationality    string
+	Biography      string
+	AlternateNames []string
+	BookCount      int
+	DeletedAt      *time.Time
+	Version        int64
+}
+
+// AuthorUpdate is a partial author update. Nil fields are kept; dates and
+// alternate names are written only when their Set flag is on, so that they
+// can be cleared as well.
+type AuthorUpdate struct {
+	Name              *string
+	BirthDate         *time.Time
+	SetBirthDate      bool
+	DeathDate         *time.Time
+	SetDeathDate      bool
+	Nationality       *string
+	Biography         *string
+	AlternateNames    []string
+	SetAlternateNames bool
+}
+
+// AuthorChange is the state of an author before and after an update.
+type AuthorChange struct {
+	Before *Author
+	After  *Author
+}
+
+type NameMatch int
+
+const (
+	NameMatchPrefix NameMatch = iota
+	NameMatchSubstring
+)
+
+type AuthorsFilter struct {
+	NameQuery string
+	NameMatch NameMatch
+}
+
+// AuthorCursor points at the last author of a page in (lower(name), id) order.
+type AuthorCursor struct {
 	Name string
+	ID   string
 }
 
 var (
-	ErrAuthorNotFound = status.Error(codes.NotFound, "author not found")
+	ErrAuthorNotFound     = status.Error(codes.NotFound, "author not found")
+	ErrInvalidAuthorName  = status.Error(codes.InvalidArgument, "invalid author name")
+	ErrInvalidAuthorDates = status.Error(codes.InvalidArgument, "death date is before birth date")
 )
@@ -8,13 +8,70 @@ import (
 )
 
 type Book struct {
-	ID        string
-	Name      string
-	AuthorIDs []string
+	ID              string
+	Name            string
+	AuthorIDs       []string
+	GenreIDs        []string
+	Tags            []string
+	ISBN            string
+	Publisher       string
+	PublicationYear int
+	Language        string
+	PageCount       int
+	Description     string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       *time.Time
+	Version         int64
+}
+
+type BooksFilter struct {
+	NamePrefix    string
+	AuthorID      string
+	GenreID       string
+	Tag           string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	SortOrder     SortOrder
+}
+
+// BookUpdate is a partial book update. Nil fields are kept; the author
+// list, genres and tags are replaced only when their Replace flag is set.
+type BookUpdate struct {
+	Name            *string
+	ISBN            *string
+	Publisher       *string
+	PublicationYear *int
+	Language        *string
+	PageCount       *int
+	Description     *string
+	AuthorIDs       []string
+	ReplaceAuthors  bool
+	AddAuthorIDs    []string
+	RemoveAuthorIDs []string
+	GenreIDs        []string
+	ReplaceGenres   bool
+	Tags            []string
+	ReplaceTags     bool
+}
+
+// BookChange is the state of a book before and after an update.
+type BookChange struct {
+	Before *Book
+	After  *Book
+}
+
+// BookCursor points at the last book of a page in (created_at, id) order.
+type BookCursor struct {
 	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID        string
 }
 
 var (
-	ErrBookNotFound = status.Error(codes.NotFound, "book not found")
+	ErrBookNotFound      = status.Error(codes.NotFound, "book not found")
+	ErrInvalidISBN       = status.Error(codes.InvalidArgument, "invalid ISBN")
+	ErrISBNAlreadyExists = status.Error(codes.AlreadyExists, "book with this ISBN already exists")
+	ErrBookHasLoans      = status.Error(codes.FailedPrecondition, "book was lent and can not be purged")
 )
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type CopyStatus string
+
+const (
+	CopyStatusAvailable CopyStatus = "available"
+	CopyStatusOnLoan    CopyStatus = "on_loan"
+	CopyStatusOnHold    CopyStatus = "on_hold"
+	CopyStatusLost      CopyStatus = "lost"
+	CopyStatusInRepair  CopyStatus = "in_repair"
+)
+
+type CopyCondition string
+
+const (
+	CopyConditionNew     CopyCondition = "new"
+	CopyConditionGood    CopyCondition = "good"
+	CopyConditionFair    CopyCondition = "fair"
+	CopyConditionPoor    CopyCondition = "poor"
+	CopyConditionDamaged CopyCondition = "damaged"
+)
+
+// Copy is a physical item of a book owned by the library.
+type Copy struct {
+	ID            string
+	BookID        string
+	Barcode       string
+	Branch        string
+	ShelfLocation string
+	Condition     CopyCondition
+	AcquiredOn    *time.Time
+	Status        CopyStatus
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// CopyUpdate is a partial copy update. Nil fields are kept; the
+// acquisition date is replaced only when SetAcquiredOn is set.
+type CopyUpdate struct {
+	Branch        *string
+	ShelfLocation *string
+	Condition     *CopyCondition
+	AcquiredOn    *time.Time
+	SetAcquiredOn bool
+	Status        *CopyStatus
+}
+
+// CopyChange is the state of a copy before and after an update.
+type CopyChange struct {
+	Before *Copy
+	After  *Copy
+}
+
+type CopiesFilter struct {
+	Branch string
+	Status CopyStatus
+}
+
+// BookAvailability counts the copies of a book by status.
+type BookAvailability struct {
+	Total     int
+	Available int
+	OnLoan    int
+	OnHold    int
+	Lost      int
+	InRepair  int
+}
+
+var (
+	ErrCopyNotFound          = status.Error(codes.NotFound, "copy not found")
+	ErrBarcodeAlreadyExists  = status.Error(codes.AlreadyExists, "copy with this barcode already exists")
+	ErrInvalidCopyBranch     = status.Error(codes.InvalidArgument, "invalid copy branch")
+	ErrCopyInUse             = status.Error(codes.FailedPrecondition, "copy is on loan or on hold")
+	ErrCopyHasLoans          = status.Error(codes.FailedPrecondition, "copy was lent and can not be deleted")
+	ErrCopyStatusNotSettable = status.Error(codes.InvalidArgument,
+		"copy status can only be set to available, lost or in_repair")
+)
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fine is charged for an overdue loan. Amounts are in minor currency
+// units; AccruedOn is the last day the fine was accrued for.
+type Fine struct {
+	ID        string
+	LoanID    string
+	PatronID  string
+	Amount    int64
+	Paid      int64
+	AccruedOn time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Outstanding is the part of the fine still to be paid.
+func (f *Fine) Outstanding() int64 {
+	return f.Amount - f.Paid
+}
+
+// FinePolicy charges DailyRate for every day a loan is overdue past the
+// first GraceDays, up to MaxPerLoan. A zero MaxPerLoan means no cap.
+type FinePolicy struct {
+	DailyRate  int64
+	MaxPerLoan int64
+	GraceDays  int
+}
+
+// Amount is the fine for a loan overdue by the given number of days.
+func (p FinePolicy) Amount(daysOverdue int) int64 {
+	if daysOverdue <= p.GraceDays {
+		return 0
+	}
+
+	amount := p.DailyRate * int64(daysOverdue-p.GraceDays)
+	if p.MaxPerLoan > 0 && amount > p.MaxPerLoan {
+		return p.MaxPerLoan
+	}
+
+	return amount
+}
+
+// PatronAccount is the patron with the fines not paid in full. Balance
+// is their total outstanding amount.
+type PatronAccount struct {
+	Patron  *Patron
+	Fines   []*Fine
+	Balance int64
+}
+
+// OverdueNotice reminds the patron of an overdue loan and the fine
+// accrued so far.
+type OverdueNotice struct {
+	Loan        *Loan
+	DaysOverdue int
+	Fine        int64
+}
+
+var (
+	ErrFineNotFound   = status.Error(codes.NotFound, "fine not found")
+	ErrInvalidPayment = status.Error(codes.InvalidArgument, "payment exceeds the outstanding amount of the fine")
+)
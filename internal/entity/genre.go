@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Genre is a node of the genre and subject taxonomy. Root genres have
+// an empty ParentID.
+type Genre struct {
+	ID        string
+	Name      string
+	ParentID  string
+	CreatedAt time.Time
+}
+
+// GenreUpdate is a partial genre update. Nil fields are kept; an empty
+// ParentID moves the genre to the root.
+type GenreUpdate struct {
+	Name     *string
+	ParentID *string
+}
+
+var (
+	ErrGenreNotFound      = status.Error(codes.NotFound, "genre not found")
+	ErrGenreAlreadyExists = status.Error(codes.AlreadyExists, "genre with this name already exists under the parent")
+	ErrGenreInUse         = status.Error(codes.FailedPrecondition, "genre has subgenres or books")
+	ErrGenreCycle         = status.Error(codes.InvalidArgument, "genre can't be moved under itself or its subgenre")
+	ErrInvalidGenreName   = status.Error(codes.InvalidArgument, "invalid genre name")
+	ErrInvalidTag         = status.Error(codes.InvalidArgument, "invalid tag")
+)
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type HoldStatus string
+
+const (
+	HoldStatusWaiting   HoldStatus = "waiting"
+	HoldStatusReady     HoldStatus = "ready"
+	HoldStatusFulfilled HoldStatus = "fulfilled"
+	HoldStatusCancelled HoldStatus = "cancelled"
+	HoldStatusExpired   HoldStatus = "expired"
+)
+
+// Hold is a patron's place in the queue for a book. Once a copy is set
+// aside the hold is ready until the end of the ExpiresOn date.
+type Hold struct {
+	ID       string
+	BookID   string
+	PatronID string
+	// CopyID is the copy set aside for a ready hold.
+	CopyID string
+	Status HoldStatus
+	// QueuePosition starts at 1 for the oldest waiting hold of the book
+	// and is 0 for holds that don't wait.
+	QueuePosition int
+	PlacedAt      time.Time
+	ReadyAt       *time.Time
+	ExpiresOn     *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Active tells whether the hold is still in the queue or ready.
+func (h *Hold) Active() bool {
+	return h.Status == HoldStatusWaiting || h.Status == HoldStatusReady
+}
+
+// HoldsFilter selects active holds of a book, of a patron or both.
+type HoldsFilter struct {
+	BookID   string
+	PatronID string
+}
+
+var (
+	ErrHoldNotFound       = status.Error(codes.NotFound, "hold not found")
+	ErrHoldAlreadyExists  = status.Error(codes.AlreadyExists, "patron already has a hold on this book")
+	ErrHoldNotActive      = status.Error(codes.FailedPrecondition, "hold is no longer active")
+	ErrBookAvailable      = status.Error(codes.FailedPrecondition, "book has an available copy")
+	ErrInvalidHoldsFilter = status.Error(codes.InvalidArgument, "book or patron is required")
+)
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BookPurchased is sent by acquisitions when the library buys a title. The
+// book is added to the catalog unless one with the ISBN is already there.
+type BookPurchased struct {
+	Name            string   `json:"name"`
+	AuthorIDs       []string `json:"author_ids"`
+	ISBN            string   `json:"isbn,omitempty"`
+	Publisher       string   `json:"publisher,omitempty"`
+	PublicationYear int      `json:"publication_year,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	PageCount       int      `json:"page_count,omitempty"`
+	Description     string   `json:"description,omitempty"`
+}
+
+// AuthorMerged tells that SourceID is a duplicate of TargetID: the books of
+// the source move to the target and the source is deleted.
+type AuthorMerged struct {
+	SourceID string `json:"source_id"`
+	TargetID string `json:"target_id"`
+}
+
+var ErrAuthorMergedIntoItself = status.Error(codes.InvalidArgument, "author can not be merged into itself")
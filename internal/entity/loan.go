@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Loan is a copy checked out by a patron. The copy is due back by the end
+// of the DueOn date; ReturnedAt is nil while the loan is open.
+type Loan struct {
+	ID           string
+	CopyID       string
+	BookID       string
+	PatronID     string
+	CheckedOutAt time.Time
+	DueOn        time.Time
+	ReturnedAt   *time.Time
+	Renewals     int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+var (
+	ErrLoanNotFound        = status.Error(codes.NotFound, "loan not found")
+	ErrCopyNotAvailable    = status.Error(codes.FailedPrecondition, "copy is not available for loan")
+	ErrLoanLimitReached    = status.Error(codes.FailedPrecondition, "patron has reached the loan limit")
+	ErrRenewalLimitReached = status.Error(codes.FailedPrecondition, "loan has reached the renewal limit")
+	ErrLoanAlreadyReturned = status.Error(codes.FailedPrecondition, "loan is already returned")
+)
@@ -0,0 +1,12 @@
+package entity
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrOutboxMessageNotFound = status.Error(codes.NotFound, "outbox message not found")
+	ErrOutboxMessagePending  = status.Error(codes.FailedPrecondition, "outbox message is still pending delivery")
+	ErrInvalidOutboxKind     = status.Error(codes.InvalidArgument, "unknown outbox kind")
+)
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type SortOrder int
+
+const (
+	SortOrderAsc SortOrder = iota
+	SortOrderDesc
+)
+
+var (
+	ErrInvalidPageToken   = status.Error(codes.InvalidArgument, "invalid page token")
+	ErrInvalidResumeToken = status.Error(codes.InvalidArgument, "invalid resume token")
+)
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type PatronStatus string
+
+const (
+	PatronStatusActive  PatronStatus = "active"
+	PatronStatusBlocked PatronStatus = "blocked"
+)
+
+// Patron is a registered library member. The membership is valid through
+// the MembershipExpiresOn date.
+type Patron struct {
+	ID                  string
+	CardNumber          string
+	Name                string
+	Email               string
+	Phone               string
+	Address             string
+	MembershipExpiresOn time.Time
+	Status              PatronStatus
+	BlockReason         string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// CheckInGoodStanding tells whether the patron may use the library at now.
+func (p *Patron) CheckInGoodStanding(now time.Time) error {
+	if p.Status == PatronStatusBlocked {
+		return ErrPatronBlocked
+	}
+
+	if !now.Before(p.MembershipExpiresOn.AddDate(0, 0, 1)) {
+		return ErrMembershipExpired
+	}
+
+	return nil
+}
+
+// PatronUpdate is a partial patron update. Nil fields are kept.
+type PatronUpdate struct {
+	Name                *string
+	Email               *string
+	Phone               *string
+	Address             *string
+	MembershipExpiresOn *time.Time
+}
+
+var (
+	ErrPatronNotFound          = status.Error(codes.NotFound, "patron not found")
+	ErrInvalidPatronName       = status.Error(codes.InvalidArgument, "invalid patron name")
+	ErrInvalidCardNumber       = status.Error(codes.InvalidArgument, "invalid card number")
+	ErrInvalidMembershipExpiry = status.Error(codes.InvalidArgument, "membership expiry date is in the past")
+	ErrCardNumberAlreadyExists = status.Error(codes.AlreadyExists, "patron with this card number already exists")
+	ErrPatronBlocked           = status.Error(codes.FailedPrecondition, "patron is blocked")
+	ErrMembershipExpired       = status.Error(codes.FailedPrecondition, "patron membership has expired")
+)
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SearchFilter narrows a catalog search. With GenreID set only books of
+// that genre or its subgenres are returned.
+type SearchFilter struct {
+	Query   string
+	GenreID string
+}
+
+type SearchResultKind int
+
+const (
+	SearchResultBook SearchResultKind = iota
+	SearchResultAuthor
+)
+
+// SearchResult is a book or an author matched by a catalog search.
+// Snippet is the matched text, HTML-escaped, with hits wrapped in <b></b>.
+type SearchResult struct {
+	Kind    SearchResultKind
+	ID      string
+	Name    string
+	Snippet string
+	Score   float64
+}
+
+var (
+	ErrEmptySearchQuery = status.Error(codes.InvalidArgument, "search query is empty")
+)
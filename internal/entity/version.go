@@ -0,0 +1,13 @@
+package entity
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrVersionMismatch = status.Error(codes.Aborted, "version mismatch")
+	// ErrConcurrentUpdate is a change aborted by the database in favour of
+	// a concurrent one; it can be retried.
+	ErrConcurrentUpdate = status.Error(codes.Aborted, "concurrent update, retry")
+)
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+func init() {
+	prometheus.Register(InboxTasksCreated)
+	prometheus.Register(InboxTasksDuplicated)
+	prometheus.Register(InboxTasksProcessed)
+	prometheus.Register(InboxTasksFailed)
+	prometheus.Register(InboxTasksDeadLettered)
+	prometheus.Register(InboxTaskProcessingDuration)
+
+	InboxTasksFailed.WithLabelValues("book_purchased").Add(0)
+	InboxTasksFailed.WithLabelValues("author_merged").Add(0)
+	InboxTasksDeadLettered.WithLabelValues("book_purchased").Add(0)
+	InboxTasksDeadLettered.WithLabelValues("author_merged").Add(0)
+}
+
+var (
+	InboxTasksCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "inbox",
+		Name:      "tasks_created_total",
+		Help:      "Общее число событий, принятых в inbox, по each kind",
+	}, []string{"kind"})
+
+	InboxTasksDuplicated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "inbox",
+		Name:      "tasks_duplicated_total",
+		Help:      "Общее число повторно присланных событий, отброшенных inbox, по each kind",
+	}, []string{"kind"})
+
+	InboxTasksProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "inbox",
+		Name:      "tasks_processed_total",
+		Help:      "Общее число событий, успешно применённых воркером, по each kind",
+	}, []string{"kind"})
+
+	InboxTasksFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "inbox",
+		Name:      "tasks_failed_total",
+		Help:      "Общее число событий, применение которых завершилось ошибкой, по each kind",
+	}, []string{"kind"})
+
+	InboxTasksDeadLettered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "inbox",
+		Name:      "tasks_dead_lettered_total",
+		Help:      "Общее число событий, перенесённых в dead letter после исчерпания попыток, по each kind",
+	}, []string{"kind"})
+
+	InboxTaskProcessingDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "inbox",
+		Name:      "task_processing_duration_seconds",
+		Help:      "Время применения одного события из inbox (в секундах) по each kind",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"kind"})
+)
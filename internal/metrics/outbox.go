@@ -9,10 +9,14 @@ func init() {
 	prometheus.Register(OutboxTasksCreated)
 	prometheus.Register(OutboxTasksProcessed)
 	prometheus.Register(OutboxTasksFailed)
+	prometheus.Register(OutboxTasksDeadLettered)
 	prometheus.Register(OutboxTaskProcessingDuration)
+	prometheus.Register(OutboxTasksPurged)
 
 	OutboxTasksFailed.WithLabelValues("author").Add(0)
 	OutboxTasksFailed.WithLabelValues("book").Add(0)
+	OutboxTasksDeadLettered.WithLabelValues("author").Add(0)
+	OutboxTasksDeadLettered.WithLabelValues("book").Add(0)
 }
 
 var (
@@ -37,6 +41,20 @@ var (
 		Help:      "Общее число задач, завершившихся ошибкой, по each kind",
 	}, []string{"kind"})
 
+	OutboxTasksDeadLettered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "tasks_dead_lettered_total",
+		Help:      "Общее число задач, перенесённых в dead letter после исчерпания попыток, по each kind",
+	}, []string{"kind"})
+
+	OutboxTasksPurged = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "tasks_purged_total",
+		Help:      "Общее число обработанных задач, удалённых из outbox по сроку хранения",
+	})
+
 	OutboxTaskProcessingDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: namespace,
 		Subsystem: "outbox",
@@ -0,0 +1,134 @@
+package publisher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CloudEvents content modes.
+const (
+	// CloudEventsStructured sends the whole event, data included, as the
+	// JSON body.
+	CloudEventsStructured = "structured"
+	// CloudEventsBinary sends the data as the body and the attributes as
+	// headers.
+	CloudEventsBinary = "binary"
+)
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	// CloudEventsContentType is the content type of a structured event.
+	CloudEventsContentType = "application/cloudevents+json"
+	dataContentType        = "application/json"
+)
+
+// CloudEvents encodes messages as CloudEvents 1.0 events coming from
+// Source, in Mode.
+type CloudEvents struct {
+	Source string
+	Mode   string
+}
+
+// CloudEvent is a CloudEvents 1.0 event in the JSON format. Subject is the
+// message key, TraceParent is the distributed tracing extension, and
+// Sequence is the sequence extension: the message sequence zero-padded, so
+// that it orders as a string.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	TraceParent     string          `json:"traceparent,omitempty"`
+	Sequence        string          `json:"sequence,omitempty"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// Event returns the event of the message. The idempotency key is the
+// event ID, so that consumers deduplicate redeliveries by source and ID.
+func (c *CloudEvents) Event(message Message) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              message.IdempotencyKey,
+		Source:          c.Source,
+		Type:            message.Type,
+		Subject:         message.Key,
+		Time:            message.OccurredAt.UTC(),
+		DataContentType: dataContentType,
+		TraceParent:     TraceParent(message.TraceID, message.IdempotencyKey),
+		Sequence:        eventSequence(message.Sequence),
+		Data:            message.Body,
+	}
+}
+
+// Attribute is a context attribute of an event, as sent in a header in the
+// binary mode.
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+// Attributes lists the context attributes of the event, except for
+// datacontenttype, which every binding sends as its own content type.
+func (e CloudEvent) Attributes() []Attribute {
+	attributes := []Attribute{
+		{Name: "specversion", Value: e.SpecVersion},
+		{Name: "id", Value: e.ID},
+		{Name: "source", Value: e.Source},
+		{Name: "type", Value: e.Type},
+		{Name: "time", Value: e.Time.Format(time.RFC3339Nano)},
+	}
+	if e.Subject != "" {
+		attributes = append(attributes, Attribute{Name: "subject", Value: e.Subject})
+	}
+	if e.TraceParent != "" {
+		attributes = append(attributes, Attribute{Name: "traceparent", Value: e.TraceParent})
+	}
+	if e.Sequence != "" {
+		attributes = append(attributes, Attribute{Name: "sequence", Value: e.Sequence})
+	}
+
+	return attributes
+}
+
+// TraceParent is the W3C traceparent of a message of the trace traceID.
+// Only the trace is stored along with the message, so the parent span ID
+// is derived from the idempotency key: it's the same on every redelivery.
+// Messages without a valid trace have no traceparent.
+func TraceParent(traceID string, idempotencyKey string) string {
+	id, err := hex.DecodeString(traceID)
+	if err != nil || len(id) != 16 || isZero(id) {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(idempotencyKey))
+	spanID := sum[:8]
+	if isZero(spanID) {
+		spanID[7] = 1
+	}
+
+	return "00-" + hex.EncodeToString(id) + "-" + hex.EncodeToString(spanID) + "-01"
+}
+
+func eventSequence(sequence int64) string {
+	if sequence == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%019d", sequence)
+}
+
+func isZero(id []byte) bool {
+	for _, b := range id {
+		if b != 0 {
+			return false
+		}
+	}
+
+	return true
+}
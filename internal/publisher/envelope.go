@@ -0,0 +1,37 @@
+package publisher
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EnvelopeVersion is the version of the Envelope format. It changes only
+// when receivers of the previous version can no longer read the envelope.
+const EnvelopeVersion = 1
+
+// Envelope is the self-describing JSON body of a webhook: the event type,
+// the entity snapshot and the metadata needed to deduplicate and trace it.
+type Envelope struct {
+	Version        int             `json:"version"`
+	Type           string          `json:"type"`
+	Key            string          `json:"key"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	Sequence       int64           `json:"sequence,omitempty"`
+	OccurredAt     time.Time       `json:"occurred_at"`
+	TraceID        string          `json:"trace_id,omitempty"`
+	Data           json.RawMessage `json:"data"`
+}
+
+// NewEnvelope wraps the message with its metadata.
+func NewEnvelope(message Message) Envelope {
+	return Envelope{
+		Version:        EnvelopeVersion,
+		Type:           message.Kind,
+		Key:            message.Key,
+		IdempotencyKey: message.IdempotencyKey,
+		Sequence:       message.Sequence,
+		OccurredAt:     message.OccurredAt.UTC(),
+		TraceID:        message.TraceID,
+		Data:           message.Body,
+	}
+}
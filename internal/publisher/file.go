@@ -0,0 +1,92 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Stdout is the file path that makes the file publisher write to stdout.
+const Stdout = "-"
+
+var _ Publisher = (*filePublisher)(nil)
+
+type filePublisher struct {
+	mu     sync.Mutex
+	w      io.Writer
+	events *CloudEvents
+}
+
+type fileLine struct {
+	Key            string          `json:"key"`
+	Kind           string          `json:"kind"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	Sequence       int64           `json:"sequence,omitempty"`
+	TraceID        string          `json:"trace_id,omitempty"`
+	Data           json.RawMessage `json:"data"`
+}
+
+// NewFile appends messages to the file at path as JSON lines; Stdout
+// writes them to stdout. When events is not nil, every line is a
+// structured CloudEvent: a file has no headers for the binary mode.
+func NewFile(path string, events *CloudEvents) (*filePublisher, error) {
+	if path == Stdout {
+		return NewWriter(os.Stdout, events), nil
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("can not open outbox file: %w", err)
+	}
+
+	return NewWriter(file, events), nil
+}
+
+// NewWriter writes messages to w as JSON lines.
+func NewWriter(w io.Writer, events *CloudEvents) *filePublisher {
+	return &filePublisher{
+		w:      w,
+		events: events,
+	}
+}
+
+func (f *filePublisher) Publish(_ context.Context, message Message) error {
+	var line any = fileLine{
+		Key:            message.Key,
+		Kind:           message.Kind,
+		IdempotencyKey: message.IdempotencyKey,
+		Sequence:       message.Sequence,
+		TraceID:        message.TraceID,
+		Data:           message.Body,
+	}
+	if f.events != nil {
+		line = f.events.Event(message)
+	}
+
+	encoded, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Errorf("can not encode outbox message: %w", err)
+	}
+
+	// One write per line, so that lines of concurrent workers don't
+	// interleave.
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err = f.w.Write(append(encoded, '\n')); err != nil {
+		return fmt.Errorf("can not write outbox message: %w", err)
+	}
+
+	return nil
+}
+
+func (f *filePublisher) Close() error {
+	if file, ok := f.w.(*os.File); ok && file != os.Stdout {
+		return file.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Headers set by the HTTP publisher.
+const (
+	HeaderKind           = "X-Library-Kind"
+	HeaderKey            = "X-Library-Key"
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderTraceID        = "X-Library-Trace-Id"
+	HeaderSequence       = "X-Library-Sequence"
+)
+
+// What the HTTP publisher posts as the body.
+const (
+	// HTTPBodyKey is the message key alone, as the first webhooks expect.
+	HTTPBodyKey = "key"
+	// HTTPBodyPayload is the JSON payload of the message.
+	HTTPBodyPayload = "payload"
+	// HTTPBodyEnvelope is the payload wrapped in an Envelope.
+	HTTPBodyEnvelope = "envelope"
+)
+
+// maxErrorBodyLen bounds the part of an error response kept in the error.
+const maxErrorBodyLen = 512
+
+var _ Publisher = (*httpPublisher)(nil)
+
+type httpPublisher struct {
+	client *http.Client
+	url    string
+	body   string
+	signer *Signer
+	events *CloudEvents
+}
+
+// NewHTTP posts messages to a webhook at url, with body being HTTPBodyKey,
+// HTTPBodyPayload or HTTPBodyEnvelope, or as CloudEvents when events is
+// not nil. Requests are signed when signer is not nil. Any 2xx response
+// acknowledges the message.
+func NewHTTP(
+	client *http.Client,
+	url string,
+	body string,
+	signer *Signer,
+	events *CloudEvents,
+) *httpPublisher {
+	return &httpPublisher{
+		client: client,
+		url:    url,
+		body:   body,
+		signer: signer,
+		events: events,
+	}
+}
+
+func (h *httpPublisher) Publish(ctx context.Context, message Message) error {
+	body, header, err := h.encode(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if h.signer != nil {
+		req.Header.Set(HeaderSignature, h.signer.Sign(body))
+	}
+	req.Header.Set(HeaderKind, message.Kind)
+	req.Header.Set(HeaderKey, message.Key)
+	req.Header.Set(HeaderIdempotencyKey, message.IdempotencyKey)
+	if message.TraceID != "" {
+		req.Header.Set(HeaderTraceID, message.TraceID)
+	}
+	if message.Sequence != 0 {
+		req.Header.Set(HeaderSequence, strconv.FormatInt(message.Sequence, 10))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLen))
+		return fmt.Errorf("non success response: %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	// Drain the body, so that the connection is reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
+
+// encode returns the body of the message and its own headers.
+func (h *httpPublisher) encode(message Message) ([]byte, http.Header, error) {
+	header := make(http.Header)
+
+	if h.events != nil {
+		event := h.events.Event(message)
+		if h.events.Mode == CloudEventsBinary {
+			header.Set("Content-Type", event.DataContentType)
+			for _, attribute := range event.Attributes() {
+				header.Set("Ce-"+attribute.Name, attribute.Value)
+			}
+
+			return message.Body, header, nil
+		}
+
+		body, err := json.Marshal(event)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal cloud event: %w", err)
+		}
+		header.Set("Content-Type", CloudEventsContentType)
+
+		return body, header, nil
+	}
+
+	switch h.body {
+	case HTTPBodyKey:
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		return []byte(message.Key), header, nil
+	case HTTPBodyEnvelope:
+		body, err := json.Marshal(NewEnvelope(message))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal envelope: %w", err)
+		}
+		header.Set("Content-Type", "application/json")
+
+		return body, header, nil
+	default:
+		header.Set("Content-Type", "application/json")
+		return message.Body, header, nil
+	}
+}
+
+// Close does nothing: the client is shared.
+func (h *httpPublisher) Close() error {
+	return nil
+}
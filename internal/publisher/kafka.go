@@ -0,0 +1,105 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaWriter is the part of kafka.Writer the publisher uses.
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, messages ...kafka.Message) error
+	Close() error
+}
+
+var _ Publisher = (*kafkaPublisher)(nil)
+
+type kafkaPublisher struct {
+	writer KafkaWriter
+	events *CloudEvents
+}
+
+// NewKafkaWriter writes to topic on any Kafka-compatible broker. Messages
+// are partitioned by key and written one by one, acknowledged by all
+// in-sync replicas.
+func NewKafkaWriter(brokers []string, topic string) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		BatchSize:    1,
+		BatchTimeout: time.Millisecond,
+	}
+}
+
+// NewKafka writes messages with writer, as CloudEvents when events is not
+// nil. The message key is the Kafka key in any mode.
+func NewKafka(writer KafkaWriter, events *CloudEvents) *kafkaPublisher {
+	return &kafkaPublisher{
+		writer: writer,
+		events: events,
+	}
+}
+
+func (k *kafkaPublisher) Publish(ctx context.Context, message Message) error {
+	value, headers, err := k.encode(message)
+	if err != nil {
+		return err
+	}
+
+	err = k.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(message.Key),
+		Value:   value,
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write to kafka: %w", err)
+	}
+
+	return nil
+}
+
+func (k *kafkaPublisher) encode(message Message) ([]byte, []kafka.Header, error) {
+	if k.events == nil {
+		headers := []kafka.Header{
+			{Key: "kind", Value: []byte(message.Kind)},
+			{Key: "idempotency_key", Value: []byte(message.IdempotencyKey)},
+		}
+		if message.TraceID != "" {
+			headers = append(headers, kafka.Header{Key: "trace_id", Value: []byte(message.TraceID)})
+		}
+		if message.Sequence != 0 {
+			headers = append(headers, kafka.Header{
+				Key: "sequence", Value: []byte(strconv.FormatInt(message.Sequence, 10)),
+			})
+		}
+
+		return message.Body, headers, nil
+	}
+
+	event := k.events.Event(message)
+	if k.events.Mode == CloudEventsBinary {
+		headers := []kafka.Header{{Key: "content-type", Value: []byte(event.DataContentType)}}
+		for _, attribute := range event.Attributes() {
+			headers = append(headers, kafka.Header{Key: "ce_" + attribute.Name, Value: []byte(attribute.Value)})
+		}
+
+		return message.Body, headers, nil
+	}
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal cloud event: %w", err)
+	}
+
+	return value, []kafka.Header{{Key: "content-type", Value: []byte(CloudEventsContentType)}}, nil
+}
+
+func (k *kafkaPublisher) Close() error {
+	return k.writer.Close()
+}
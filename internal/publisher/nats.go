@@ -0,0 +1,83 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSConn is the part of nats.Conn the publisher uses.
+type NATSConn interface {
+	PublishMsg(msg *nats.Msg) error
+	FlushWithContext(ctx context.Context) error
+	Close()
+}
+
+var _ Publisher = (*natsPublisher)(nil)
+
+type natsPublisher struct {
+	conn    NATSConn
+	subject string
+	events  *CloudEvents
+}
+
+// NewNATS publishes every message to subject.<kind>, as CloudEvents when
+// events is not nil. The idempotency key is sent as Nats-Msg-Id, which
+// JetStream uses to drop duplicates.
+func NewNATS(conn NATSConn, subject string, events *CloudEvents) *natsPublisher {
+	return &natsPublisher{
+		conn:    conn,
+		subject: subject,
+		events:  events,
+	}
+}
+
+func (n *natsPublisher) Publish(ctx context.Context, message Message) error {
+	msg := nats.NewMsg(n.subject + "." + message.Kind)
+	msg.Data = message.Body
+	msg.Header.Set(nats.MsgIdHdr, message.IdempotencyKey)
+	msg.Header.Set("Library-Key", message.Key)
+	if message.TraceID != "" {
+		msg.Header.Set("Library-Trace-Id", message.TraceID)
+	}
+	if message.Sequence != 0 {
+		msg.Header.Set("Library-Sequence", strconv.FormatInt(message.Sequence, 10))
+	}
+
+	if n.events != nil {
+		event := n.events.Event(message)
+		if n.events.Mode == CloudEventsBinary {
+			msg.Header.Set("content-type", event.DataContentType)
+			for _, attribute := range event.Attributes() {
+				msg.Header.Set("ce-"+attribute.Name, attribute.Value)
+			}
+		} else {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("failed to marshal cloud event: %w", err)
+			}
+			msg.Data = data
+			msg.Header.Set("content-type", CloudEventsContentType)
+		}
+	}
+
+	if err := n.conn.PublishMsg(msg); err != nil {
+		return fmt.Errorf("failed to publish to nats: %w", err)
+	}
+
+	// Core NATS doesn't acknowledge messages; a flush at least makes sure
+	// the server got it.
+	if err := n.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush nats connection: %w", err)
+	}
+
+	return nil
+}
+
+func (n *natsPublisher) Close() error {
+	n.conn.Close()
+	return nil
+}
@@ -0,0 +1,40 @@
+package publisher
+
+import (
+	"context"
+	"time"
+)
+
+// Names of the publishers, as used in the configuration.
+const (
+	HTTP  = "http"
+	Kafka = "kafka"
+	NATS  = "nats"
+	File  = "file"
+)
+
+// Message is an outbox message on its way out. Key identifies the entity
+// the message is about: publishers that partition or order messages do so
+// by Key, so messages about one entity are delivered in order.
+type Message struct {
+	Key  string
+	Kind string
+	// Type is the event type of Kind, such as library.book.created.
+	Type           string
+	IdempotencyKey string
+	TraceID        string
+	// Sequence numbers the messages of one key from 1 with no gaps, so
+	// that consumers can tell a message was missed. It's 0 for messages
+	// that aren't numbered.
+	Sequence int64
+	// OccurredAt is when the message was stored in the outbox, along with
+	// the change it is about.
+	OccurredAt time.Time
+	// Body is the JSON payload of the message.
+	Body []byte
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+	Close() error
+}
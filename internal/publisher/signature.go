@@ -0,0 +1,126 @@
+package publisher
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeaderSignature carries the signatures of a webhook request, as
+// "t=<unix seconds>,v1=<hex>[,v1=<hex>]": one HMAC-SHA256 per active key
+// over "<unix seconds>.<body>".
+const HeaderSignature = "X-Library-Signature"
+
+// MaxSigningKeys is how many keys may be active at once: the current one
+// and the next one while receivers are being moved to it.
+const MaxSigningKeys = 2
+
+const signatureScheme = "v1"
+
+var (
+	ErrSignatureMissing  = errors.New("signature is missing")
+	ErrSignatureInvalid  = errors.New("signature is invalid")
+	ErrSignatureExpired  = errors.New("signature timestamp is out of tolerance")
+	ErrSignatureMismatch = errors.New("no signature matches")
+)
+
+// Signer signs request bodies with every active key, so that receivers
+// accept the request while they know either of the keys.
+type Signer struct {
+	keys [][]byte
+	now  func() time.Time
+}
+
+// NewSigner returns a signer for up to MaxSigningKeys keys.
+func NewSigner(keys ...string) (*Signer, error) {
+	if len(keys) == 0 || len(keys) > MaxSigningKeys {
+		return nil, fmt.Errorf("expected 1 to %d signing keys, got %d", MaxSigningKeys, len(keys))
+	}
+
+	signer := &Signer{
+		keys: make([][]byte, 0, len(keys)),
+		now:  time.Now,
+	}
+	for _, key := range keys {
+		if key == "" {
+			return nil, errors.New("signing key is empty")
+		}
+		signer.keys = append(signer.keys, []byte(key))
+	}
+
+	return signer, nil
+}
+
+// Sign returns the value of HeaderSignature for body.
+func (s *Signer) Sign(body []byte) string {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	var header strings.Builder
+	header.WriteString("t=" + timestamp)
+	for _, key := range s.keys {
+		header.WriteString("," + signatureScheme + "=" + sign(key, timestamp, body))
+	}
+
+	return header.String()
+}
+
+// VerifySignature checks the HeaderSignature value of a request against
+// the receiver's keys. The timestamp must be within tolerance of now, so
+// that a captured request can't be replayed later.
+func VerifySignature(header string, body []byte, keys []string, tolerance time.Duration, now time.Time) error {
+	if header == "" {
+		return ErrSignatureMissing
+	}
+
+	var (
+		timestamp  string
+		signatures []string
+	)
+	for _, part := range strings.Split(header, ",") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return ErrSignatureInvalid
+		}
+
+		switch name {
+		case "t":
+			timestamp = value
+		case signatureScheme:
+			signatures = append(signatures, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrSignatureInvalid
+	}
+
+	if skew := now.Sub(time.Unix(unix, 0)); skew > tolerance || skew < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	for _, key := range keys {
+		expected := sign([]byte(key), timestamp, body)
+		for _, signature := range signatures {
+			if hmac.Equal([]byte(expected), []byte(signature)) {
+				return nil
+			}
+		}
+	}
+
+	return ErrSignatureMismatch
+}
+
+func sign(key []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,193 @@
+package publisher
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/project/library/internal/publisher"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-"
+)
+
+func newTestEventMessage() publisher.Message {
+	return publisher.Message{
+		Key:            "book-1",
+		Kind:           "book",
+		Type:           "library.book.created",
+		IdempotencyKey: "book_book-1",
+		TraceID:        testTraceID,
+		Sequence:       3,
+		OccurredAt:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Body:           []byte(`{"id":"book-1"}`),
+	}
+}
+
+func assertTestEvent(t *testing.T, event publisher.CloudEvent) {
+	t.Helper()
+
+	assert.Equal(t, "1.0", event.SpecVersion)
+	assert.Equal(t, "book_book-1", event.ID)
+	assert.Equal(t, "/library", event.Source)
+	assert.Equal(t, "library.book.created", event.Type)
+	assert.Equal(t, "book-1", event.Subject)
+	assert.True(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).Equal(event.Time))
+	assert.Equal(t, "application/json", event.DataContentType)
+	assert.Contains(t, event.TraceParent, testTraceParent)
+	assert.Equal(t, "0000000000000000003", event.Sequence)
+	assert.JSONEq(t, `{"id":"book-1"}`, string(event.Data))
+}
+
+func TestHTTPPublishCloudEvents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		mode string
+	}{
+		{
+			name: "structured",
+			mode: publisher.CloudEventsStructured,
+		},
+		{
+			name: "binary",
+			mode: publisher.CloudEventsBinary,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				gotBody   []byte
+				gotHeader http.Header
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotBody, _ = io.ReadAll(r.Body)
+				gotHeader = r.Header.Clone()
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer server.Close()
+
+			events := &publisher.CloudEvents{Source: "/library", Mode: tt.mode}
+			p := publisher.NewHTTP(server.Client(), server.URL, publisher.HTTPBodyKey, nil, events)
+			require.NoError(t, p.Publish(t.Context(), newTestEventMessage()))
+
+			var event publisher.CloudEvent
+			if tt.mode == publisher.CloudEventsStructured {
+				assert.Equal(t, publisher.CloudEventsContentType, gotHeader.Get("Content-Type"))
+				require.NoError(t, json.Unmarshal(gotBody, &event))
+			} else {
+				eventTime, err := time.Parse(time.RFC3339Nano, gotHeader.Get("Ce-Time"))
+				require.NoError(t, err)
+
+				event = publisher.CloudEvent{
+					SpecVersion:     gotHeader.Get("Ce-Specversion"),
+					ID:              gotHeader.Get("Ce-Id"),
+					Source:          gotHeader.Get("Ce-Source"),
+					Type:            gotHeader.Get("Ce-Type"),
+					Subject:         gotHeader.Get("Ce-Subject"),
+					Time:            eventTime,
+					DataContentType: gotHeader.Get("Content-Type"),
+					TraceParent:     gotHeader.Get("Ce-Traceparent"),
+					Sequence:        gotHeader.Get("Ce-Sequence"),
+					Data:            gotBody,
+				}
+			}
+			assertTestEvent(t, event)
+		})
+	}
+}
+
+func TestKafkaPublishCloudEvents(t *testing.T) {
+	t.Parallel()
+
+	writer := &fakeKafkaWriter{}
+	p := publisher.NewKafka(writer, &publisher.CloudEvents{Source: "/library", Mode: publisher.CloudEventsBinary})
+	require.NoError(t, p.Publish(t.Context(), newTestEventMessage()))
+
+	require.Len(t, writer.messages, 1)
+	message := writer.messages[0]
+	assert.Equal(t, []byte("book-1"), message.Key)
+	assert.JSONEq(t, `{"id":"book-1"}`, string(message.Value))
+
+	headers := make(map[string]string, len(message.Headers))
+	for _, header := range message.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	assert.Equal(t, "application/json", headers["content-type"])
+	assert.Equal(t, "1.0", headers["ce_specversion"])
+	assert.Equal(t, "book_book-1", headers["ce_id"])
+	assert.Equal(t, "/library", headers["ce_source"])
+	assert.Equal(t, "library.book.created", headers["ce_type"])
+	assert.Equal(t, "2024-05-01T12:00:00Z", headers["ce_time"])
+	assert.Contains(t, headers["ce_traceparent"], testTraceParent)
+	assert.Equal(t, "0000000000000000003", headers["ce_sequence"])
+
+	writer.messages = nil
+	p = publisher.NewKafka(writer, &publisher.CloudEvents{Source: "/library", Mode: publisher.CloudEventsStructured})
+	require.NoError(t, p.Publish(t.Context(), newTestEventMessage()))
+
+	require.Len(t, writer.messages, 1)
+	assert.Equal(t, []kafka.Header{
+		{Key: "content-type", Value: []byte(publisher.CloudEventsContentType)},
+	}, writer.messages[0].Headers)
+
+	var event publisher.CloudEvent
+	require.NoError(t, json.Unmarshal(writer.messages[0].Value, &event))
+	assertTestEvent(t, event)
+}
+
+func TestNATSPublishCloudEvents(t *testing.T) {
+	t.Parallel()
+
+	conn := &fakeNATSConn{}
+	p := publisher.NewNATS(conn, "library", &publisher.CloudEvents{Source: "/library", Mode: publisher.CloudEventsBinary})
+	require.NoError(t, p.Publish(t.Context(), newTestEventMessage()))
+
+	require.Len(t, conn.messages, 1)
+	msg := conn.messages[0]
+	assert.JSONEq(t, `{"id":"book-1"}`, string(msg.Data))
+	assert.Equal(t, "book_book-1", msg.Header.Get(nats.MsgIdHdr))
+	assert.Equal(t, "book_book-1", msg.Header.Get("ce-id"))
+	assert.Equal(t, "library.book.created", msg.Header.Get("ce-type"))
+	assert.Equal(t, "application/json", msg.Header.Get("content-type"))
+}
+
+func TestFilePublishCloudEvents(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	p := publisher.NewWriter(&buf, &publisher.CloudEvents{Source: "/library", Mode: publisher.CloudEventsStructured})
+	require.NoError(t, p.Publish(t.Context(), newTestEventMessage()))
+
+	var event publisher.CloudEvent
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &event))
+	assertTestEvent(t, event)
+}
+
+func TestTraceParent(t *testing.T) {
+	t.Parallel()
+
+	traceParent := publisher.TraceParent(testTraceID, "book_book-1")
+	assert.Regexp(t, `^00-4bf92f3577b34da6a3ce929d0e0e4736-[0-9a-f]{16}-01$`, traceParent)
+	assert.Equal(t, traceParent, publisher.TraceParent(testTraceID, "book_book-1"))
+	assert.NotEqual(t, traceParent, publisher.TraceParent(testTraceID, "book_book-2"))
+
+	assert.Empty(t, publisher.TraceParent("", "book_book-1"))
+	assert.Empty(t, publisher.TraceParent("00000000000000000000000000000000", "book_book-1"))
+	assert.Empty(t, publisher.TraceParent("not a trace", "book_book-1"))
+}
@@ -0,0 +1,64 @@
+package publisher
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/project/library/internal/publisher"
+)
+
+func TestFilePublish(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	p, err := publisher.NewFile(path, nil)
+	require.NoError(t, err)
+
+	const messages = 50
+
+	var wg sync.WaitGroup
+	for range messages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, p.Publish(t.Context(), publisher.Message{
+				Key:            "author-1",
+				Kind:           "author",
+				IdempotencyKey: "author_author-1",
+				Body:           []byte(`{"id":"author-1","name":"Author"}`),
+			}))
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, p.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line struct {
+			Key            string          `json:"key"`
+			Kind           string          `json:"kind"`
+			IdempotencyKey string          `json:"idempotency_key"`
+			Data           json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		assert.Equal(t, "author-1", line.Key)
+		assert.Equal(t, "author", line.Kind)
+		assert.Equal(t, "author_author-1", line.IdempotencyKey)
+		assert.JSONEq(t, `{"id":"author-1","name":"Author"}`, string(line.Data))
+		lines++
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, messages, lines)
+}
@@ -0,0 +1,177 @@
+package publisher
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/project/library/internal/publisher"
+)
+
+func TestHTTPPublish(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		body            string
+		status          int
+		wantBody        string
+		wantContentType string
+		wantErr         bool
+	}{
+		{
+			name:            "publish payload",
+			body:            publisher.HTTPBodyPayload,
+			status:          http.StatusNoContent,
+			wantBody:        `{"after":{"id":"book-1"}}`,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "publish key",
+			body:            publisher.HTTPBodyKey,
+			status:          http.StatusOK,
+			wantBody:        "book-1",
+			wantContentType: "text/plain; charset=utf-8",
+		},
+		{
+			name:            "publish | non success response",
+			body:            publisher.HTTPBodyPayload,
+			status:          http.StatusServiceUnavailable,
+			wantBody:        `{"after":{"id":"book-1"}}`,
+			wantErr:         true,
+			wantContentType: "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				gotBody   []byte
+				gotHeader http.Header
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotBody, _ = io.ReadAll(r.Body)
+				gotHeader = r.Header.Clone()
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			p := publisher.NewHTTP(server.Client(), server.URL, tt.body, nil, nil)
+			err := p.Publish(t.Context(), publisher.Message{
+				Key:            "book-1",
+				Kind:           "book_updated",
+				IdempotencyKey: "book_updated_book-1_2",
+				TraceID:        "4bf92f3577b34da6a3ce929d0e0e4736",
+				Body:           []byte(`{"after":{"id":"book-1"}}`),
+			})
+			if tt.wantErr {
+				require.ErrorContains(t, err, "non success response: 503")
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantBody, string(gotBody))
+			assert.Equal(t, tt.wantContentType, gotHeader.Get("Content-Type"))
+			assert.Empty(t, gotHeader.Get(publisher.HeaderSignature))
+			assert.Equal(t, "book_updated", gotHeader.Get(publisher.HeaderKind))
+			assert.Equal(t, "book-1", gotHeader.Get(publisher.HeaderKey))
+			assert.Equal(t, "book_updated_book-1_2", gotHeader.Get(publisher.HeaderIdempotencyKey))
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", gotHeader.Get(publisher.HeaderTraceID))
+		})
+	}
+}
+
+func TestHTTPPublishSignedEnvelope(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		signingKeys  []string
+		receiverKeys []string
+		wantErr      bool
+	}{
+		{
+			name:         "signed envelope",
+			signingKeys:  []string{"current"},
+			receiverKeys: []string{"current"},
+		},
+		{
+			name:         "signed envelope | receiver knows the next key only",
+			signingKeys:  []string{"current", "next"},
+			receiverKeys: []string{"next"},
+		},
+		{
+			name:         "signed envelope | receiver knows the previous key only",
+			signingKeys:  []string{"current", "next"},
+			receiverKeys: []string{"current"},
+		},
+		{
+			name:         "signed envelope | unknown key",
+			signingKeys:  []string{"rotated-out"},
+			receiverKeys: []string{"current", "next"},
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var envelope publisher.Envelope
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				err := publisher.VerifySignature(r.Header.Get(publisher.HeaderSignature), body,
+					tt.receiverKeys, 5*time.Minute, time.Now())
+				if err != nil {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				if err := json.Unmarshal(body, &envelope); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			signer, err := publisher.NewSigner(tt.signingKeys...)
+			require.NoError(t, err)
+
+			occurredAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			p := publisher.NewHTTP(server.Client(), server.URL, publisher.HTTPBodyEnvelope, signer, nil)
+			err = p.Publish(t.Context(), publisher.Message{
+				Key:            "book-1",
+				Kind:           "book_updated",
+				IdempotencyKey: "book_updated_book-1_2",
+				TraceID:        "4bf92f3577b34da6a3ce929d0e0e4736",
+				Sequence:       2,
+				OccurredAt:     occurredAt,
+				Body:           []byte(`{"after":{"id":"book-1"}}`),
+			})
+			if tt.wantErr {
+				require.ErrorContains(t, err, "non success response: 401")
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, publisher.EnvelopeVersion, envelope.Version)
+			assert.Equal(t, "book_updated", envelope.Type)
+			assert.Equal(t, "book-1", envelope.Key)
+			assert.Equal(t, "book_updated_book-1_2", envelope.IdempotencyKey)
+			assert.Equal(t, int64(2), envelope.Sequence)
+			assert.True(t, occurredAt.Equal(envelope.OccurredAt))
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", envelope.TraceID)
+			assert.JSONEq(t, `{"after":{"id":"book-1"}}`, string(envelope.Data))
+		})
+	}
+}
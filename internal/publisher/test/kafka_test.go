@@ -0,0 +1,66 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/project/library/internal/publisher"
+)
+
+type fakeKafkaWriter struct {
+	messages []kafka.Message
+	err      error
+	closed   bool
+}
+
+func (f *fakeKafkaWriter) WriteMessages(_ context.Context, messages ...kafka.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	f.messages = append(f.messages, messages...)
+	return nil
+}
+
+func (f *fakeKafkaWriter) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestKafkaPublish(t *testing.T) {
+	t.Parallel()
+
+	writer := &fakeKafkaWriter{}
+	p := publisher.NewKafka(writer, nil)
+
+	err := p.Publish(t.Context(), publisher.Message{
+		Key:            "copy-1",
+		Kind:           "copy_updated",
+		IdempotencyKey: "copy_updated_copy-1_3",
+		Sequence:       7,
+		Body:           []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, writer.messages, 1)
+	message := writer.messages[0]
+	assert.Equal(t, []byte("copy-1"), message.Key)
+	assert.Equal(t, []byte(`{}`), message.Value)
+	assert.Equal(t, []kafka.Header{
+		{Key: "kind", Value: []byte("copy_updated")},
+		{Key: "idempotency_key", Value: []byte("copy_updated_copy-1_3")},
+		{Key: "sequence", Value: []byte("7")},
+	}, message.Headers)
+
+	writer.err = errors.New("leader not available")
+	err = p.Publish(t.Context(), publisher.Message{Key: "copy-1"})
+	require.ErrorContains(t, err, "leader not available")
+
+	require.NoError(t, p.Close())
+	assert.True(t, writer.closed)
+}
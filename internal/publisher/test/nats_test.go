@@ -0,0 +1,61 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/project/library/internal/publisher"
+)
+
+type fakeNATSConn struct {
+	messages []*nats.Msg
+	flushErr error
+	closed   bool
+}
+
+func (f *fakeNATSConn) PublishMsg(msg *nats.Msg) error {
+	f.messages = append(f.messages, msg)
+	return nil
+}
+
+func (f *fakeNATSConn) FlushWithContext(context.Context) error {
+	return f.flushErr
+}
+
+func (f *fakeNATSConn) Close() {
+	f.closed = true
+}
+
+func TestNATSPublish(t *testing.T) {
+	t.Parallel()
+
+	conn := &fakeNATSConn{}
+	p := publisher.NewNATS(conn, "library", nil)
+
+	err := p.Publish(t.Context(), publisher.Message{
+		Key:            "loan-1",
+		Kind:           "loan_overdue",
+		IdempotencyKey: "loan_overdue_loan-1_3",
+		Body:           []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, conn.messages, 1)
+	msg := conn.messages[0]
+	assert.Equal(t, "library.loan_overdue", msg.Subject)
+	assert.Equal(t, []byte(`{}`), msg.Data)
+	assert.Equal(t, "loan_overdue_loan-1_3", msg.Header.Get(nats.MsgIdHdr))
+	assert.Equal(t, "loan-1", msg.Header.Get("Library-Key"))
+
+	conn.flushErr = errors.New("nats: connection closed")
+	err = p.Publish(t.Context(), publisher.Message{Key: "loan-1", Kind: "loan_overdue"})
+	require.ErrorContains(t, err, "connection closed")
+
+	require.NoError(t, p.Close())
+	assert.True(t, conn.closed)
+}
@@ -0,0 +1,148 @@
+package publisher
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/project/library/internal/publisher"
+)
+
+func TestNewSigner(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		keys    []string
+		wantErr bool
+	}{
+		{
+			name: "one key",
+			keys: []string{"current"},
+		},
+		{
+			name: "two keys",
+			keys: []string{"current", "next"},
+		},
+		{
+			name:    "no keys",
+			wantErr: true,
+		},
+		{
+			name:    "too many keys",
+			keys:    []string{"previous", "current", "next"},
+			wantErr: true,
+		},
+		{
+			name:    "empty key",
+			keys:    []string{"current", ""},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := publisher.NewSigner(tt.keys...)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"version":1}`)
+	signer, err := publisher.NewSigner("current", "next")
+	require.NoError(t, err)
+	header := signer.Sign(body)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		header  string
+		body    []byte
+		keys    []string
+		now     time.Time
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			header: header,
+			body:   body,
+			keys:   []string{"current"},
+			now:    now,
+		},
+		{
+			name:   "valid | next key",
+			header: header,
+			body:   body,
+			keys:   []string{"next"},
+			now:    now,
+		},
+		{
+			name:    "missing",
+			body:    body,
+			keys:    []string{"current"},
+			now:     now,
+			wantErr: publisher.ErrSignatureMissing,
+		},
+		{
+			name:    "malformed",
+			header:  "garbage",
+			body:    body,
+			keys:    []string{"current"},
+			now:     now,
+			wantErr: publisher.ErrSignatureInvalid,
+		},
+		{
+			name:    "no timestamp",
+			header:  strings.SplitN(header, ",", 2)[1],
+			body:    body,
+			keys:    []string{"current"},
+			now:     now,
+			wantErr: publisher.ErrSignatureInvalid,
+		},
+		{
+			name:    "tampered body",
+			header:  header,
+			body:    []byte(`{"version":2}`),
+			keys:    []string{"current", "next"},
+			now:     now,
+			wantErr: publisher.ErrSignatureMismatch,
+		},
+		{
+			name:    "replayed timestamp",
+			header:  fmt.Sprintf("t=%d,v1=%s", now.Add(-time.Hour).Unix(), strings.Repeat("0", 64)),
+			body:    body,
+			keys:    []string{"current"},
+			now:     now,
+			wantErr: publisher.ErrSignatureExpired,
+		},
+		{
+			name:    "expired",
+			header:  header,
+			body:    body,
+			keys:    []string{"current"},
+			now:     now.Add(10 * time.Minute),
+			wantErr: publisher.ErrSignatureExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := publisher.VerifySignature(tt.header, tt.body, tt.keys, 5*time.Minute, tt.now)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package catalog
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+//go:generate mockgen_uber -source=catalog.go -destination=mocks/catalog_mock.go -package=mocks
+
+// batchSize bounds the changes read from the log at once.
+const batchSize = 100
+
+// SendFunc sends a change to the watcher together with the token that
+// resumes the watch after it.
+type SendFunc = func(change *repository.CatalogChange, resumeToken string) error
+
+type Catalog interface {
+	// Watch sends the changes following resumeToken, then the new ones as
+	// they are committed, until ctx is done or send fails. An empty
+	// resumeToken starts from the first change in the log.
+	Watch(ctx context.Context, resumeToken string, send SendFunc) error
+}
+
+var _ Catalog = (*catalogImpl)(nil)
+
+type catalogImpl struct {
+	logger           *zap.Logger
+	changeRepository repository.CatalogChangeRepository
+	notifier         repository.CatalogNotifier
+	// pollInterval bounds the delay of a change whose notification was
+	// lost, e.g. while the notifier was reconnecting.
+	pollInterval time.Duration
+}
+
+func New(
+	logger *zap.Logger,
+	changeRepository repository.CatalogChangeRepository,
+	notifier repository.CatalogNotifier,
+	pollInterval time.Duration,
+) *catalogImpl {
+	return &catalogImpl{
+		logger:           logger,
+		changeRepository: changeRepository,
+		notifier:         notifier,
+		pollInterval:     pollInterval,
+	}
+}
+
+type resumeToken struct {
+	Position int64 `json:"p"`
+}
+
+func (c *catalogImpl) Watch(
+	ctx context.Context,
+	token string,
+	send SendFunc,
+) error {
+	position, err := decodeResumeToken(token)
+	if err != nil {
+		return err
+	}
+
+	// Subscribing before the first read makes sure that a change committed
+	// right after a read still wakes the watch up.
+	wakeUp, unsubscribe := c.notifier.Subscribe()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		changes, err := c.changeRepository.ListChanges(ctx, position, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			next, err := encodeResumeToken(change.Position)
+			if err != nil {
+				return err
+			}
+
+			if err = send(change, next); err != nil {
+				return err
+			}
+
+			position = change.Position
+		}
+
+		// A full batch may have more changes behind it.
+		if len(changes) == batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wakeUp:
+		case <-ticker.C:
+		}
+	}
+}
+
+func encodeResumeToken(position int64) (string, error) {
+	raw, err := json.Marshal(resumeToken{Position: position})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeResumeToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, entity.ErrInvalidResumeToken
+	}
+
+	var decoded resumeToken
+	if err = json.Unmarshal(raw, &decoded); err != nil || decoded.Position <= 0 {
+		return 0, entity.ErrInvalidResumeToken
+	}
+
+	return decoded.Position, nil
+}
@@ -0,0 +1,221 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/catalog"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func changes(from, to int64) []*repository.CatalogChange {
+	result := make([]*repository.CatalogChange, 0)
+	for position := from; position <= to; position++ {
+		result = append(result, &repository.CatalogChange{
+			Position: position,
+			Kind:     repository.OutboxKindBookUpdated,
+		})
+	}
+
+	return result
+}
+
+// watcher collects what Watch sends.
+type watcher struct {
+	positions []int64
+	tokens    []string
+}
+
+func (w *watcher) send(change *repository.CatalogChange, resumeToken string) error {
+	w.positions = append(w.positions, change.Position)
+	w.tokens = append(w.tokens, resumeToken)
+	return nil
+}
+
+func newNotifier(ctrl *gomock.Controller) (*mocks.MockCatalogNotifier, chan struct{}) {
+	notifier := mocks.NewMockCatalogNotifier(ctrl)
+	wakeUp := make(chan struct{}, 1)
+	notifier.EXPECT().Subscribe().Return((<-chan struct{})(wakeUp), func() {}).AnyTimes()
+
+	return notifier, wakeUp
+}
+
+func TestWatchReplaysAndTails(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockChangeRepo := mocks.NewMockCatalogChangeRepository(ctrl)
+	notifier, wakeUp := newNotifier(ctrl)
+	logger, _ := zap.NewProduction()
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	gomock.InOrder(
+		// History, and a change committed meanwhile.
+		mockChangeRepo.EXPECT().ListChanges(ctx, int64(0), 100).
+			DoAndReturn(func(context.Context, int64, int) ([]*repository.CatalogChange, error) {
+				wakeUp <- struct{}{}
+				return changes(1, 2), nil
+			}),
+		// The next one, committed once the watch caught up.
+		mockChangeRepo.EXPECT().ListChanges(ctx, int64(2), 100).
+			DoAndReturn(func(context.Context, int64, int) ([]*repository.CatalogChange, error) {
+				wakeUp <- struct{}{}
+				return changes(3, 3), nil
+			}),
+		mockChangeRepo.EXPECT().ListChanges(ctx, int64(3), 100).
+			DoAndReturn(func(context.Context, int64, int) ([]*repository.CatalogChange, error) {
+				cancel()
+				return changes(4, 3), nil
+			}),
+	)
+
+	var got watcher
+	service := catalog.New(logger, mockChangeRepo, notifier, time.Hour)
+	err := service.Watch(ctx, "", got.send)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, []int64{1, 2, 3}, got.positions)
+
+	// A client reconnecting with the token of the last change it got
+	// carries on right after it.
+	resumeCtx, resumeCancel := context.WithCancel(t.Context())
+	defer resumeCancel()
+
+	mockChangeRepo.EXPECT().ListChanges(resumeCtx, int64(2), 100).
+		DoAndReturn(func(context.Context, int64, int) ([]*repository.CatalogChange, error) {
+			resumeCancel()
+			return changes(3, 3), nil
+		})
+
+	var resumed watcher
+	err = service.Watch(resumeCtx, got.tokens[1], resumed.send)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, []int64{3}, resumed.positions)
+	require.Equal(t, got.tokens[2], resumed.tokens[0])
+}
+
+func TestWatchReadsFullBatchesWithoutWaiting(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockChangeRepo := mocks.NewMockCatalogChangeRepository(ctrl)
+	notifier, _ := newNotifier(ctrl)
+	logger, _ := zap.NewProduction()
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	gomock.InOrder(
+		mockChangeRepo.EXPECT().ListChanges(ctx, int64(0), 100).Return(changes(1, 100), nil),
+		mockChangeRepo.EXPECT().ListChanges(ctx, int64(100), 100).
+			DoAndReturn(func(context.Context, int64, int) ([]*repository.CatalogChange, error) {
+				cancel()
+				return changes(101, 101), nil
+			}),
+	)
+
+	var got watcher
+	service := catalog.New(logger, mockChangeRepo, notifier, time.Hour)
+	err := service.Watch(ctx, "", got.send)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, got.positions, 101)
+}
+
+func TestWatchPollsWithoutNotifications(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockChangeRepo := mocks.NewMockCatalogChangeRepository(ctrl)
+	notifier, _ := newNotifier(ctrl)
+	logger, _ := zap.NewProduction()
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	gomock.InOrder(
+		mockChangeRepo.EXPECT().ListChanges(ctx, int64(0), 100).Return(changes(1, 0), nil),
+		mockChangeRepo.EXPECT().ListChanges(ctx, int64(0), 100).
+			DoAndReturn(func(context.Context, int64, int) ([]*repository.CatalogChange, error) {
+				cancel()
+				return changes(1, 1), nil
+			}),
+	)
+
+	var got watcher
+	service := catalog.New(logger, mockChangeRepo, notifier, time.Millisecond)
+	err := service.Watch(ctx, "", got.send)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, []int64{1}, got.positions)
+}
+
+func TestWatchErrors(t *testing.T) {
+	t.Parallel()
+
+	sendErr := errors.New("send error")
+
+	tests := []struct {
+		name    string
+		token   string
+		listErr error
+		sendErr error
+		wantErr error
+	}{
+		{
+			name:    "watch | not base64",
+			token:   "!!!",
+			wantErr: entity.ErrInvalidResumeToken,
+		},
+		{
+			name:    "watch | not a resume token",
+			token:   "bm90IGpzb24",
+			wantErr: entity.ErrInvalidResumeToken,
+		},
+		{
+			name:    "watch | negative position",
+			token:   "eyJwIjotMX0",
+			wantErr: entity.ErrInvalidResumeToken,
+		},
+		{
+			name:    "watch | list error",
+			listErr: errors.New("db error"),
+			wantErr: errors.New("db error"),
+		},
+		{
+			name:    "watch | send error",
+			sendErr: sendErr,
+			wantErr: sendErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockChangeRepo := mocks.NewMockCatalogChangeRepository(ctrl)
+			notifier, _ := newNotifier(ctrl)
+			logger, _ := zap.NewProduction()
+			ctx := t.Context()
+
+			if !errors.Is(tt.wantErr, entity.ErrInvalidResumeToken) {
+				mockChangeRepo.EXPECT().ListChanges(ctx, int64(0), 100).Return(changes(1, 1), tt.listErr)
+			}
+
+			service := catalog.New(logger, mockChangeRepo, notifier, time.Hour)
+			err := service.Watch(ctx, tt.token, func(*repository.CatalogChange, string) error {
+				return tt.sendErr
+			})
+			require.EqualError(t, err, tt.wantErr.Error())
+		})
+	}
+}
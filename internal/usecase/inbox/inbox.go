@@ -0,0 +1,313 @@
+package inbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/project/library/config"
+	"github.com/project/library/internal/metrics"
+	"github.com/project/library/internal/usecase/outbox"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+//go:generate mockgen_uber -source=inbox.go -destination=mocks/inbox_mock.go -package=mocks
+
+type GlobalHandler = func(kind repository.InboxKind) (KindHandler, error)
+
+// KindHandler applies the message in the transaction in ctx, which also
+// marks the message as processed.
+type KindHandler = func(ctx context.Context, message repository.InboxData) error
+
+var tracer = otel.Tracer("library-service")
+
+// maxLastErrorLen bounds the error kept with a failed message.
+const maxLastErrorLen = 1024
+
+type Inbox interface {
+	// Receive stores the event unless the message was received before, and
+	// tells whether it was stored.
+	Receive(ctx context.Context, messageID string, kind repository.InboxKind, event any) (bool, error)
+	Start(ctx context.Context, workers int, batchSize int,
+		waitTime time.Duration, inProgressTTL time.Duration)
+}
+
+var _ Inbox = (*inboxImpl)(nil)
+
+type inboxImpl struct {
+	logger          *zap.Logger
+	inboxRepository repository.InboxRepository
+	globalHandler   GlobalHandler
+	cfg             *config.Config
+	transactor      repository.Transactor
+}
+
+func New(
+	logger *zap.Logger,
+	inboxRepository repository.InboxRepository,
+	globalHandler GlobalHandler,
+	cfg *config.Config,
+	transactor repository.Transactor,
+) *inboxImpl {
+	return &inboxImpl{
+		logger:          logger,
+		inboxRepository: inboxRepository,
+		globalHandler:   globalHandler,
+		cfg:             cfg,
+		transactor:      transactor,
+	}
+}
+
+func (i *inboxImpl) Receive(
+	ctx context.Context,
+	messageID string,
+	kind repository.InboxKind,
+	event any,
+) (bool, error) {
+	span := trace.SpanFromContext(ctx)
+	traceID := span.SpanContext().TraceID().String()
+
+	serialized, err := json.Marshal(event)
+	if err != nil {
+		span.RecordError(fmt.Errorf("error serializing %s: %w", kind, err))
+		return false, err
+	}
+
+	return i.inboxRepository.ReceiveMessage(ctx, messageID, kind, serialized, traceID)
+}
+
+func (i *inboxImpl) Start(
+	ctx context.Context,
+	workers int, batchSize int,
+	waitTime time.Duration,
+	inProgressTTL time.Duration,
+) {
+	for workerID := 1; workerID <= workers; workerID++ {
+		go i.worker(ctx, batchSize, waitTime, inProgressTTL)
+	}
+}
+
+// worker claims a batch of messages in a short transaction and applies
+// every message in a transaction of its own, together with marking it as
+// processed, so that a message is applied exactly once.
+func (i *inboxImpl) worker(
+	ctx context.Context,
+	batchSize int,
+	waitTime time.Duration,
+	inProgressTTL time.Duration,
+) {
+	log := i.logger.With(
+		zap.String("layer", "inbox"))
+
+	timer := time.NewTimer(waitTime)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		i.processBatch(ctx, log, batchSize, inProgressTTL)
+
+		timer.Reset(waitTime)
+	}
+}
+
+func (i *inboxImpl) processBatch(
+	ctx context.Context,
+	log *zap.Logger,
+	batchSize int,
+	inProgressTTL time.Duration,
+) {
+	var messages []repository.InboxData
+
+	err := i.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		messages, err = i.inboxRepository.GetMessages(ctx, batchSize, inProgressTTL)
+		return err
+	})
+
+	if err != nil {
+		log.Error("can not fetch messages from inbox", zap.Error(err))
+		return
+	}
+
+	for _, message := range messages {
+		// The rest of the batch stays IN_PROGRESS and is claimed again
+		// after inProgressTTL.
+		if ctx.Err() != nil {
+			return
+		}
+
+		i.processMessage(ctx, log, message)
+	}
+}
+
+func (i *inboxImpl) processMessage(
+	ctx context.Context,
+	log *zap.Logger,
+	message repository.InboxData,
+) {
+	start := time.Now()
+	kind := message.Kind.String()
+
+	traceID, parseErr := trace.TraceIDFromHex(message.TraceID)
+	if parseErr != nil {
+		log.Warn("invalid trace_id",
+			zap.String("trace_id", message.TraceID),
+			zap.Error(parseErr))
+	}
+
+	eventCtx := ctx
+	if parseErr == nil {
+		parentSC := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     trace.SpanID{},
+			TraceFlags: trace.FlagsSampled,
+			Remote:     true,
+		})
+		eventCtx = trace.ContextWithRemoteSpanContext(ctx, parentSC)
+	}
+
+	eventCtx, span := tracer.Start(eventCtx, "ProcessInboxEvent")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("inbox.id", message.MessageID),
+		attribute.String("inbox.kind", kind),
+	)
+
+	log = log.With(
+		zap.String("trace_id", traceID.String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+		zap.String("kind", kind),
+		zap.String("message_id", message.MessageID))
+
+	// The message is applied and its outcome recorded even if the worker
+	// is being stopped meanwhile.
+	eventCtx = context.WithoutCancel(eventCtx)
+
+	kindHandler, err := i.globalHandler(message.Kind)
+	if err != nil {
+		log.Error("unexpected kind", zap.Error(err))
+		metrics.InboxTasksFailed.WithLabelValues(kind).Inc()
+		if err = i.markFailed(eventCtx, message, err, false); err != nil {
+			logOutcomeError(log, "can not dead-letter inbox message", err)
+		}
+		return
+	}
+
+	err = i.transactor.WithTx(eventCtx, func(ctx context.Context) error {
+		if err := apply(ctx, kindHandler, message); err != nil {
+			return err
+		}
+
+		return i.inboxRepository.MarkAsProcessed(ctx, message.MessageID, message.Attempts)
+	})
+
+	duration := time.Since(start).Seconds()
+	metrics.InboxTaskProcessingDuration.WithLabelValues(kind).Observe(duration)
+
+	if errors.Is(err, repository.ErrInboxMessageReclaimed) {
+		log.Warn("inbox message was taken over, its changes are rolled back")
+		return
+	}
+
+	if err != nil {
+		log.Error("kind error", zap.Error(err))
+		span.RecordError(err)
+		metrics.InboxTasksFailed.WithLabelValues(kind).Inc()
+		if err = i.markFailed(eventCtx, message, err, retryable(err)); err != nil {
+			logOutcomeError(log, "can not mark inbox message as failed", err)
+		}
+		return
+	}
+
+	log.Info("inbox worker executing")
+	metrics.InboxTasksProcessed.WithLabelValues(kind).Inc()
+}
+
+// logOutcomeError logs a failure to record the outcome of a message. A
+// message reclaimed after inProgressTTL is expected to happen now and then:
+// the worker that holds it now records its outcome.
+func logOutcomeError(log *zap.Logger, msg string, err error) {
+	if errors.Is(err, repository.ErrInboxMessageReclaimed) {
+		log.Warn(msg, zap.Error(err))
+		return
+	}
+
+	log.Error(msg, zap.Error(err))
+}
+
+// retryable tells whether applying the message again may succeed. A
+// malformed event, or one the catalog rejects, fails the same way on every
+// attempt, so it's dead-lettered right away.
+func retryable(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return true
+	}
+
+	switch st.Code() {
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.FailedPrecondition, codes.OutOfRange, codes.Unimplemented:
+		return false
+	default:
+		return true
+	}
+}
+
+// apply runs the handler, turning its panic into an error so that the
+// transaction is rolled back and the worker keeps running.
+func apply(ctx context.Context, handler KindHandler, message repository.InboxData) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("inbox handler panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, message)
+}
+
+// markFailed schedules another attempt of the message or, once it's out
+// of attempts or can't succeed at all, moves it to dead letter.
+func (i *inboxImpl) markFailed(
+	ctx context.Context,
+	message repository.InboxData,
+	cause error,
+	retryable bool,
+) error {
+	lastError := cause.Error()
+	if len(lastError) > maxLastErrorLen {
+		lastError = strings.ToValidUTF8(lastError[:maxLastErrorLen], "")
+	}
+
+	if !retryable || message.Attempts >= i.cfg.Inbox.MaxAttempts {
+		if err := i.inboxRepository.MarkAsDead(ctx, message.MessageID, message.Attempts, lastError); err != nil {
+			return err
+		}
+
+		i.logger.Warn("inbox message is dead-lettered",
+			zap.String("message_id", message.MessageID),
+			zap.String("kind", message.Kind.String()),
+			zap.Int("attempts", message.Attempts))
+		metrics.InboxTasksDeadLettered.WithLabelValues(message.Kind.String()).Inc()
+
+		return nil
+	}
+
+	retryIn := outbox.Backoff(message.Attempts, i.cfg.Inbox.BackoffBaseMS, i.cfg.Inbox.BackoffMaxMS)
+
+	return i.inboxRepository.MarkAsFailed(ctx, message.MessageID, message.Attempts, lastError, retryIn)
+}
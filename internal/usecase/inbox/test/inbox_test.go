@@ -0,0 +1,143 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/project/library/config"
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/inbox"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func newConfig() *config.Config {
+	return &config.Config{
+		Inbox: config.Inbox{
+			Enabled:       true,
+			MaxAttempts:   3,
+			BackoffBaseMS: time.Second,
+			BackoffMaxMS:  time.Minute,
+		},
+	}
+}
+
+type txKey struct{}
+
+// txTransactor marks the context of every transaction.
+type txTransactor struct{}
+
+func (txTransactor) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, txKey{}, true))
+}
+
+func inTx(ctx context.Context) bool {
+	inTx, _ := ctx.Value(txKey{}).(bool)
+	return inTx
+}
+
+func TestReceive(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockInboxRepo := mocks.NewMockInboxRepository(ctrl)
+	logger, _ := zap.NewProduction()
+	ctx := t.Context()
+
+	event := entity.AuthorMerged{SourceID: "source", TargetID: "target"}
+	mockInboxRepo.EXPECT().ReceiveMessage(ctx, "acq-1", repository.InboxKindAuthorMerged,
+		[]byte(`{"source_id":"source","target_id":"target"}`), gomock.Any()).Return(false, nil)
+
+	service := inbox.New(logger, mockInboxRepo, nil, newConfig(), nil)
+	received, err := service.Receive(ctx, "acq-1", repository.InboxKindAuthorMerged, event)
+	require.NoError(t, err)
+	assert.False(t, received)
+}
+
+func TestWorkerAppliesEachMessageInTransaction(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockInboxRepo := mocks.NewMockInboxRepository(ctrl)
+	logger, _ := zap.NewProduction()
+
+	messages := []repository.InboxData{
+		{MessageID: "ok", Kind: repository.InboxKindBookPurchased, Attempts: 1},
+		{MessageID: "fail", Kind: repository.InboxKindBookPurchased, Attempts: 1},
+		{MessageID: "rejected", Kind: repository.InboxKindAuthorMerged, Attempts: 1},
+		{MessageID: "panic", Kind: repository.InboxKindBookPurchased, Attempts: 3},
+		{MessageID: "reclaimed", Kind: repository.InboxKindAuthorMerged, Attempts: 1},
+		{MessageID: "unknown", Kind: "book_burned", Attempts: 1},
+	}
+
+	handler := func(ctx context.Context, message repository.InboxData) error {
+		assert.True(t, inTx(ctx), "message is applied outside of a transaction")
+
+		switch message.MessageID {
+		case "fail":
+			return entity.ErrConcurrentUpdate
+		case "rejected":
+			return entity.ErrAuthorNotFound
+		case "panic":
+			panic("boom")
+		default:
+			return nil
+		}
+	}
+	globalHandler := func(kind repository.InboxKind) (inbox.KindHandler, error) {
+		if kind == "book_burned" {
+			return nil, errors.New("unsupported inbox kind: book_burned")
+		}
+		return handler, nil
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+
+	gomock.InOrder(
+		mockInboxRepo.EXPECT().GetMessages(gomock.Any(), 10, time.Minute).Return(messages, nil),
+		mockInboxRepo.EXPECT().GetMessages(gomock.Any(), 10, time.Minute).
+			DoAndReturn(func(context.Context, int, time.Duration) ([]repository.InboxData, error) {
+				cancel()
+				close(done)
+				return nil, nil
+			}),
+	)
+
+	// Only the applied messages are marked as processed, in the
+	// transaction that applied them.
+	mockInboxRepo.EXPECT().MarkAsProcessed(gomock.Any(), "ok", 1).
+		DoAndReturn(func(ctx context.Context, _ string, _ int) error {
+			assert.True(t, inTx(ctx), "message is marked as processed outside of a transaction")
+			return nil
+		})
+	// Another worker took the message over: the change is rolled back and
+	// the message is left to that worker.
+	mockInboxRepo.EXPECT().MarkAsProcessed(gomock.Any(), "reclaimed", 1).
+		Return(repository.ErrInboxMessageReclaimed)
+	mockInboxRepo.EXPECT().MarkAsFailed(gomock.Any(), "fail", 1, entity.ErrConcurrentUpdate.Error(),
+		gomock.Any()).Return(repository.ErrInboxMessageReclaimed)
+	// An event the catalog rejects would be rejected on every attempt.
+	mockInboxRepo.EXPECT().MarkAsDead(gomock.Any(), "rejected", 1,
+		"rpc error: code = NotFound desc = author not found").Return(nil)
+	mockInboxRepo.EXPECT().MarkAsDead(gomock.Any(), "panic", 3, "inbox handler panicked: boom").Return(nil)
+	mockInboxRepo.EXPECT().MarkAsDead(gomock.Any(), "unknown", 1, "unsupported inbox kind: book_burned").Return(nil)
+
+	service := inbox.New(logger, mockInboxRepo, globalHandler, newConfig(), txTransactor{})
+	service.Start(ctx, 1, 10, time.Millisecond, time.Minute)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker didn't fetch the second batch")
+	}
+}
@@ -2,10 +2,6 @@ package library
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-
-	"go.opentelemetry.io/otel/trace"
 
 	"github.com/project/library/internal/entity"
 	"github.com/project/library/internal/usecase/repository"
@@ -13,59 +9,179 @@ import (
 
 func (l *libraryImpl) RegisterAuthor(
 	ctx context.Context,
-	authorName string,
+	newAuthor *entity.Author,
 ) (*entity.Author, error) {
-	span := trace.SpanFromContext(ctx)
-	traceID := span.SpanContext().TraceID().String()
+	if err := normalizeAuthor(newAuthor); err != nil {
+		return nil, err
+	}
+
 	var author *entity.Author
 
 	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
 		var txErr error
-		author, txErr = l.authorRepository.RegisterAuthor(ctx, &entity.Author{
-			Name: authorName,
-		})
+		author, txErr = l.authorRepository.RegisterAuthor(ctx, newAuthor)
 		if txErr != nil {
 			return txErr
 		}
 
-		serialized, txErr := json.Marshal(author)
+		return l.sendOutboxMessage(ctx, repository.OutboxKindAuthor, author.ID,
+			idempotencyKey(repository.OutboxKindAuthor, author.ID), author)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return author, nil
+}
+
+func (l *libraryImpl) GetAuthorInfo(ctx context.Context,
+	authorID string,
+) (*entity.Author, error) {
+	return l.authorRepository.GetAuthorInfo(ctx, authorID)
+}
+
+func (l *libraryImpl) ChangeAuthor(ctx context.Context,
+	authorID string,
+	update entity.AuthorUpdate,
+	expectedVersion int64,
+) (*entity.Author, error) {
+	if err := normalizeAuthorUpdate(&update); err != nil {
+		return nil, err
+	}
+
+	var after *entity.Author
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		before, txErr := l.authorRepository.GetAuthorForUpdate(ctx, authorID)
 		if txErr != nil {
-			span.RecordError(fmt.Errorf("error serializing author: %w", txErr))
 			return txErr
 		}
 
-		idempotencyKey := repository.OutboxKindAuthor.String() + "_" + author.ID
-		txErr = l.outboxRepository.SendMessage(
-			ctx, idempotencyKey, repository.OutboxKindAuthor, serialized, traceID)
+		birthDate, deathDate := before.BirthDate, before.DeathDate
+		if update.SetBirthDate {
+			birthDate = update.BirthDate
+		}
+		if update.SetDeathDate {
+			deathDate = update.DeathDate
+		}
+		if txErr = checkLifespan(birthDate, deathDate); txErr != nil {
+			return txErr
+		}
+
+		after, txErr = l.authorRepository.ChangeAuthor(ctx, authorID, update, expectedVersion)
 		if txErr != nil {
 			return txErr
 		}
 
-		return nil
+		return l.sendOutboxMessage(ctx, repository.OutboxKindAuthorUpdated, after.ID,
+			versionedIdempotencyKey(repository.OutboxKindAuthorUpdated, after.ID, after.Version),
+			entity.AuthorChange{Before: before, After: after})
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return author, nil
+	return after, nil
 }
 
-func (l *libraryImpl) GetAuthorInfo(ctx context.Context,
+func (l *libraryImpl) GetAuthorBooks(ctx context.Context,
 	authorID string,
-) (*entity.Author, error) {
-	return l.authorRepository.GetAuthorInfo(ctx, authorID)
+	showDeleted bool,
+) ([]*entity.Book, error) {
+	return l.authorRepository.GetAuthorBooks(ctx, authorID, showDeleted)
 }
 
-func (l *libraryImpl) ChangeAuthor(ctx context.Context,
+func (l *libraryImpl) DeleteAuthor(
+	ctx context.Context,
 	authorID string,
-	newAuthorName string,
+	purge bool,
 ) error {
-	return l.authorRepository.ChangeAuthor(ctx, authorID, newAuthorName)
+	return l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if purge {
+			author, err := l.authorRepository.PurgeAuthor(ctx, authorID)
+			if err != nil {
+				return err
+			}
+
+			return l.sendOutboxMessage(ctx, repository.OutboxKindAuthorPurged, author.ID,
+				idempotencyKey(repository.OutboxKindAuthorPurged, author.ID), author)
+		}
+
+		author, err := l.authorRepository.SoftDeleteAuthor(ctx, authorID)
+		if err != nil {
+			return err
+		}
+
+		return l.sendOutboxMessage(ctx, repository.OutboxKindAuthorDeleted, author.ID,
+			versionedIdempotencyKey(repository.OutboxKindAuthorDeleted, author.ID, author.Version), author)
+	})
 }
 
-func (l *libraryImpl) GetAuthorBooks(ctx context.Context,
+func (l *libraryImpl) UndeleteAuthor(
+	ctx context.Context,
 	authorID string,
-) ([]*entity.Book, error) {
-	return l.authorRepository.GetAuthorBooks(ctx, authorID)
+) (*entity.Author, error) {
+	var author *entity.Author
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var txErr error
+		author, txErr = l.authorRepository.RestoreAuthor(ctx, authorID)
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.sendOutboxMessage(ctx, repository.OutboxKindAuthorRestored, author.ID,
+			versionedIdempotencyKey(repository.OutboxKindAuthorRestored, author.ID, author.Version), author)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return author, nil
+}
+
+func (l *libraryImpl) ListAuthors(
+	ctx context.Context,
+	filter entity.AuthorsFilter,
+	pageSize int,
+	pageToken string,
+) ([]*entity.Author, string, error) {
+	var after *entity.AuthorCursor
+	if pageToken != "" {
+		var err error
+		after, err = decodeAuthorCursor(pageToken, filter)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	limit := normalizePageSize(pageSize)
+
+	// One extra row tells whether there is a next page.
+	authors, err := l.authorRepository.ListAuthors(ctx, filter, after, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(authors) <= limit {
+		return authors, "", nil
+	}
+
+	authors = authors[:limit]
+	last := authors[limit-1]
+
+	nextPageToken, err := encodePageToken(authorPageToken{
+		Name:      last.Name,
+		ID:        last.ID,
+		NameQuery: filter.NameQuery,
+		NameMatch: filter.NameMatch,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return authors, nextPageToken, nil
 }
@@ -2,10 +2,6 @@ package library
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-
-	"go.opentelemetry.io/otel/trace"
 
 	"github.com/project/library/internal/entity"
 	"github.com/project/library/internal/usecase/repository"
@@ -13,59 +9,193 @@ import (
 
 func (l *libraryImpl) AddBook(
 	ctx context.Context,
-	name string,
-	authorIDs []string,
+	newBook *entity.Book,
 ) (*entity.Book, error) {
-	span := trace.SpanFromContext(ctx)
-	traceID := span.SpanContext().TraceID().String()
+	if newBook.ISBN != "" {
+		isbn, err := normalizeISBN(newBook.ISBN)
+		if err != nil {
+			return nil, err
+		}
+		newBook.ISBN = isbn
+	}
+
+	tags, err := normalizeTags(newBook.Tags)
+	if err != nil {
+		return nil, err
+	}
+	newBook.Tags = tags
 
 	var book *entity.Book
 
-	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+	err = l.transactor.WithTx(ctx, func(ctx context.Context) error {
 		var txErr error
-		book, txErr = l.booksRepository.AddBook(ctx, &entity.Book{
-			Name:      name,
-			AuthorIDs: authorIDs,
-		})
+		book, txErr = l.booksRepository.AddBook(ctx, newBook)
 		if txErr != nil {
 			return txErr
 		}
 
-		serialized, txErr := json.Marshal(book)
+		return l.sendOutboxMessage(ctx, repository.OutboxKindBook, book.ID,
+			idempotencyKey(repository.OutboxKindBook, book.ID), book)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return book, nil
+}
+
+func (l *libraryImpl) GetBook(
+	ctx context.Context,
+	bookID string,
+	showDeleted bool,
+) (*entity.Book, error) {
+	return l.booksRepository.GetBook(ctx, bookID, showDeleted)
+}
+
+func (l *libraryImpl) GetBookByISBN(
+	ctx context.Context,
+	isbn string,
+) (*entity.Book, error) {
+	isbn, err := normalizeISBN(isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.booksRepository.GetBookByISBN(ctx, isbn)
+}
+
+func (l *libraryImpl) UpdateBook(
+	ctx context.Context,
+	bookID string,
+	update entity.BookUpdate,
+	expectedVersion int64,
+) (*entity.Book, error) {
+	if update.ISBN != nil && *update.ISBN != "" {
+		isbn, err := normalizeISBN(*update.ISBN)
+		if err != nil {
+			return nil, err
+		}
+		update.ISBN = &isbn
+	}
+
+	if update.ReplaceTags {
+		tags, err := normalizeTags(update.Tags)
+		if err != nil {
+			return nil, err
+		}
+		update.Tags = tags
+	}
+
+	var after *entity.Book
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		before, txErr := l.booksRepository.GetBookForUpdate(ctx, bookID)
 		if txErr != nil {
-			span.RecordError(fmt.Errorf("error serializing book: %w", txErr))
 			return txErr
 		}
 
-		idempotencyKey := repository.OutboxKindBook.String() + "_" + book.ID
-		txErr = l.outboxRepository.SendMessage(
-			ctx, idempotencyKey, repository.OutboxKindBook, serialized, traceID)
+		after, txErr = l.booksRepository.UpdateBook(ctx, bookID, update, expectedVersion)
 		if txErr != nil {
 			return txErr
 		}
 
-		return nil
+		return l.sendOutboxMessage(ctx, repository.OutboxKindBookUpdated, after.ID,
+			versionedIdempotencyKey(repository.OutboxKindBookUpdated, after.ID, after.Version),
+			entity.BookChange{Before: before, After: after})
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return book, nil
+	return after, nil
 }
 
-func (l *libraryImpl) GetBook(
+func (l *libraryImpl) ListBooks(
 	ctx context.Context,
-	bookID string,
-) (*entity.Book, error) {
-	return l.booksRepository.GetBook(ctx, bookID)
+	filter entity.BooksFilter,
+	pageSize int,
+	pageToken string,
+) ([]*entity.Book, string, error) {
+	var after *entity.BookCursor
+	if pageToken != "" {
+		var err error
+		after, err = decodeBookCursor(pageToken, filter)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	limit := normalizePageSize(pageSize)
+
+	// One extra row tells whether there is a next page.
+	books, err := l.booksRepository.ListBooks(ctx, filter, after, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(books) <= limit {
+		return books, "", nil
+	}
+
+	books = books[:limit]
+	last := books[limit-1]
+
+	nextPageToken, err := encodePageToken(newBookPageToken(last, filter))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return books, nextPageToken, nil
 }
 
-func (l *libraryImpl) UpdateBook(
+func (l *libraryImpl) DeleteBook(
 	ctx context.Context,
 	bookID string,
-	newBookName string,
-	authorIDs []string,
+	purge bool,
 ) error {
-	return l.booksRepository.UpdateBook(ctx, bookID, newBookName, authorIDs)
+	return l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if purge {
+			book, err := l.booksRepository.PurgeBook(ctx, bookID)
+			if err != nil {
+				return err
+			}
+
+			return l.sendOutboxMessage(ctx, repository.OutboxKindBookPurged, book.ID,
+				idempotencyKey(repository.OutboxKindBookPurged, book.ID), book)
+		}
+
+		book, err := l.booksRepository.SoftDeleteBook(ctx, bookID)
+		if err != nil {
+			return err
+		}
+
+		return l.sendOutboxMessage(ctx, repository.OutboxKindBookDeleted, book.ID,
+			versionedIdempotencyKey(repository.OutboxKindBookDeleted, book.ID, book.Version), book)
+	})
+}
+
+func (l *libraryImpl) UndeleteBook(
+	ctx context.Context,
+	bookID string,
+) (*entity.Book, error) {
+	var book *entity.Book
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var txErr error
+		book, txErr = l.booksRepository.RestoreBook(ctx, bookID)
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.sendOutboxMessage(ctx, repository.OutboxKindBookRestored, book.ID,
+			versionedIdempotencyKey(repository.OutboxKindBookRestored, book.ID, book.Version), book)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return book, nil
 }
@@ -0,0 +1,73 @@
+package library
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+
+	"github.com/project/library/internal/entity"
+)
+
+const (
+	// cardNumberPrefix marks library cards, as "2" does for most
+	// library barcodes.
+	cardNumberPrefix = "29"
+	cardNumberLength = 14
+)
+
+// newCardNumber returns a random card number: the prefix, random digits
+// and a Luhn check digit. Random numbers can't be guessed from one another,
+// unlike sequential ones.
+func newCardNumber() (string, error) {
+	randomDigits := cardNumberLength - len(cardNumberPrefix) - 1
+
+	var b strings.Builder
+	b.WriteString(cardNumberPrefix)
+	for range randomDigits {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(byte('0' + digit.Int64()))
+	}
+
+	number := b.String()
+	return number + string(luhnCheckDigit(number)), nil
+}
+
+// normalizeCardNumber drops spaces and hyphens and checks the length,
+// prefix and check digit.
+func normalizeCardNumber(cardNumber string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, cardNumber)
+
+	if len(digits) != cardNumberLength || !allDigits(digits) ||
+		!strings.HasPrefix(digits, cardNumberPrefix) ||
+		luhnCheckDigit(digits[:cardNumberLength-1]) != digits[cardNumberLength-1] {
+		return "", entity.ErrInvalidCardNumber
+	}
+
+	return digits, nil
+}
+
+func luhnCheckDigit(digits string) byte {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
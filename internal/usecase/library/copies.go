@@ -0,0 +1,211 @@
+package library
+
+import (
+	"context"
+	"strings"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func (l *libraryImpl) AddCopy(
+	ctx context.Context,
+	newCopy *entity.Copy,
+) (*entity.Copy, error) {
+	branch, err := normalizeBranch(newCopy.Branch)
+	if err != nil {
+		return nil, err
+	}
+	newCopy.Branch = branch
+	newCopy.ShelfLocation = strings.TrimSpace(newCopy.ShelfLocation)
+
+	if newCopy.Condition == "" {
+		newCopy.Condition = entity.CopyConditionGood
+	}
+	if newCopy.Status == "" {
+		newCopy.Status = entity.CopyStatusAvailable
+	}
+	if !manualCopyStatus(newCopy.Status) {
+		return nil, entity.ErrCopyStatusNotSettable
+	}
+
+	var bookCopy *entity.Copy
+
+	err = l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		available := newCopy.Status == entity.CopyStatusAvailable
+		if available {
+			if txErr := l.holdRepository.LockHoldQueue(ctx, newCopy.BookID); txErr != nil {
+				return txErr
+			}
+		}
+
+		var txErr error
+		bookCopy, txErr = l.copyRepository.AddCopy(ctx, newCopy)
+		if txErr != nil {
+			return txErr
+		}
+
+		// Patrons waiting for the book come before the walk-ins.
+		if available {
+			setAside, txErr := l.setAsideForNextHold(ctx, bookCopy.BookID, bookCopy.ID)
+			if txErr != nil {
+				return txErr
+			}
+			if setAside != nil {
+				bookCopy = setAside
+			}
+		}
+
+		return l.sendOutboxMessage(ctx, repository.OutboxKindCopy, bookCopy.BookID,
+			idempotencyKey(repository.OutboxKindCopy, bookCopy.ID), bookCopy)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bookCopy, nil
+}
+
+func (l *libraryImpl) GetCopy(
+	ctx context.Context,
+	copyID string,
+) (*entity.Copy, error) {
+	return l.copyRepository.GetCopy(ctx, copyID)
+}
+
+func (l *libraryImpl) UpdateCopy(
+	ctx context.Context,
+	copyID string,
+	update entity.CopyUpdate,
+) (*entity.Copy, error) {
+	if update.Branch != nil {
+		branch, err := normalizeBranch(*update.Branch)
+		if err != nil {
+			return nil, err
+		}
+		update.Branch = &branch
+	}
+
+	if update.ShelfLocation != nil {
+		shelfLocation := strings.TrimSpace(*update.ShelfLocation)
+		update.ShelfLocation = &shelfLocation
+	}
+
+	// Loans and holds move copies to and from on_loan and on_hold, so
+	// only the other statuses are set by hand.
+	if update.Status != nil && !manualCopyStatus(*update.Status) {
+		return nil, entity.ErrCopyStatusNotSettable
+	}
+
+	var after *entity.Copy
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		unlocked, txErr := l.copyRepository.GetCopy(ctx, copyID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = l.holdRepository.LockHoldQueue(ctx, unlocked.BookID); txErr != nil {
+			return txErr
+		}
+
+		before, txErr := l.copyRepository.GetCopyForUpdate(ctx, copyID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if update.Status != nil && !manualCopyStatus(before.Status) {
+			return entity.ErrCopyInUse
+		}
+
+		after, txErr = l.copyRepository.UpdateCopy(ctx, copyID, update)
+		if txErr != nil {
+			return txErr
+		}
+
+		// A copy back from repair goes to the patrons waiting for the book
+		// first.
+		if after.Status == entity.CopyStatusAvailable {
+			setAside, txErr := l.setAsideForNextHold(ctx, after.BookID, after.ID)
+			if txErr != nil {
+				return txErr
+			}
+			if setAside != nil {
+				after = setAside
+			}
+		}
+
+		// Copies have no version, the update time tells updates apart.
+		return l.sendOutboxMessage(ctx, repository.OutboxKindCopyUpdated, after.BookID,
+			versionedIdempotencyKey(repository.OutboxKindCopyUpdated, after.ID, after.UpdatedAt.UnixNano()),
+			entity.CopyChange{Before: before, After: after})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+func manualCopyStatus(status entity.CopyStatus) bool {
+	switch status {
+	case entity.CopyStatusAvailable, entity.CopyStatusLost, entity.CopyStatusInRepair:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *libraryImpl) DeleteCopy(
+	ctx context.Context,
+	copyID string,
+) error {
+	return l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		// The copy is locked, so a checkout or a hold can't take it
+		// meanwhile.
+		bookCopy, err := l.copyRepository.GetCopyForUpdate(ctx, copyID)
+		if err != nil {
+			return err
+		}
+
+		if !manualCopyStatus(bookCopy.Status) {
+			return entity.ErrCopyInUse
+		}
+
+		bookCopy, err = l.copyRepository.DeleteCopy(ctx, copyID)
+		if err != nil {
+			return err
+		}
+
+		return l.sendOutboxMessage(ctx, repository.OutboxKindCopyDeleted, bookCopy.BookID,
+			idempotencyKey(repository.OutboxKindCopyDeleted, bookCopy.ID), bookCopy)
+	})
+}
+
+func (l *libraryImpl) ListCopies(
+	ctx context.Context,
+	bookID string,
+	filter entity.CopiesFilter,
+) ([]*entity.Copy, error) {
+	filter.Branch = strings.Join(strings.Fields(filter.Branch), " ")
+
+	return l.copyRepository.ListCopies(ctx, bookID, filter)
+}
+
+func (l *libraryImpl) GetBookAvailability(
+	ctx context.Context,
+	bookID string,
+) (*entity.BookAvailability, error) {
+	return l.copyRepository.GetBookAvailability(ctx, bookID)
+}
+
+func normalizeBranch(branch string) (string, error) {
+	branch = strings.Join(strings.Fields(branch), " ")
+	if branch == "" {
+		return "", entity.ErrInvalidCopyBranch
+	}
+
+	return branch, nil
+}
@@ -0,0 +1,127 @@
+package library
+
+import (
+	"context"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+// AccrueFines brings the fines of the overdue loans up to date and sends
+// a daily reminder for each loan still out. A loan returned late gets its
+// fine once more, for the days up to the return. Each loan is handled in
+// its own transaction; the fines are upserted and the reminders are keyed
+// by the day, so running it again the same day changes nothing.
+func (l *libraryImpl) AccrueFines(
+	ctx context.Context,
+	policy entity.FinePolicy,
+) (int, error) {
+	asOf := today()
+
+	loans, err := l.loanRepository.ListOverdueLoans(ctx, asOf, policy.GraceDays)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, loan := range loans {
+		if err = l.accrueFine(ctx, policy, loan, asOf); err != nil {
+			return i, err
+		}
+	}
+
+	return len(loans), nil
+}
+
+func (l *libraryImpl) accrueFine(
+	ctx context.Context,
+	policy entity.FinePolicy,
+	loan *entity.Loan,
+	asOf time.Time,
+) error {
+	until := asOf
+	if loan.ReturnedAt != nil {
+		until = loan.ReturnedAt.UTC().Truncate(24 * time.Hour)
+	}
+	daysOverdue := int(until.Sub(loan.DueOn).Hours() / 24)
+
+	return l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		amount := policy.Amount(daysOverdue)
+
+		if daysOverdue > policy.GraceDays {
+			fine, txErr := l.fineRepository.UpsertFine(ctx, &entity.Fine{
+				LoanID:    loan.ID,
+				PatronID:  loan.PatronID,
+				Amount:    amount,
+				AccruedOn: until,
+			})
+			if txErr != nil {
+				return txErr
+			}
+			amount = fine.Amount
+		}
+
+		if loan.ReturnedAt != nil {
+			return nil
+		}
+
+		return l.sendOutboxMessage(ctx, repository.OutboxKindLoanOverdue, loan.BookID,
+			versionedIdempotencyKey(repository.OutboxKindLoanOverdue, loan.ID, int64(daysOverdue)),
+			entity.OverdueNotice{Loan: loan, DaysOverdue: daysOverdue, Fine: amount})
+	})
+}
+
+func (l *libraryImpl) GetPatronAccount(
+	ctx context.Context,
+	patronID string,
+) (*entity.PatronAccount, error) {
+	patron, err := l.patronRepository.GetPatron(ctx, patronID)
+	if err != nil {
+		return nil, err
+	}
+
+	fines, err := l.fineRepository.ListOutstandingFines(ctx, patronID)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &entity.PatronAccount{Patron: patron, Fines: fines}
+	for _, fine := range fines {
+		account.Balance += fine.Outstanding()
+	}
+
+	return account, nil
+}
+
+// PayFine pays off the fine in full or in part.
+func (l *libraryImpl) PayFine(
+	ctx context.Context,
+	fineID string,
+	amount int64,
+) (*entity.Fine, error) {
+	if amount <= 0 {
+		return nil, entity.ErrInvalidPayment
+	}
+
+	var fine *entity.Fine
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		before, txErr := l.fineRepository.GetFineForUpdate(ctx, fineID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if amount > before.Outstanding() {
+			return entity.ErrInvalidPayment
+		}
+
+		fine, txErr = l.fineRepository.PayFine(ctx, fineID, amount)
+		return txErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return fine, nil
+}
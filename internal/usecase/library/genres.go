@@ -0,0 +1,92 @@
+package library
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/project/library/internal/entity"
+)
+
+func (l *libraryImpl) CreateGenre(
+	ctx context.Context,
+	genre *entity.Genre,
+) (*entity.Genre, error) {
+	name, err := normalizeGenreName(genre.Name)
+	if err != nil {
+		return nil, err
+	}
+	genre.Name = name
+
+	return l.genreRepository.CreateGenre(ctx, genre)
+}
+
+func (l *libraryImpl) GetGenre(
+	ctx context.Context,
+	genreID string,
+) (*entity.Genre, error) {
+	return l.genreRepository.GetGenre(ctx, genreID)
+}
+
+func (l *libraryImpl) UpdateGenre(
+	ctx context.Context,
+	genreID string,
+	update entity.GenreUpdate,
+) (*entity.Genre, error) {
+	if update.Name != nil {
+		name, err := normalizeGenreName(*update.Name)
+		if err != nil {
+			return nil, err
+		}
+		update.Name = &name
+	}
+
+	if update.ParentID != nil && *update.ParentID == genreID {
+		return nil, entity.ErrGenreCycle
+	}
+
+	return l.genreRepository.UpdateGenre(ctx, genreID, update)
+}
+
+func (l *libraryImpl) DeleteGenre(
+	ctx context.Context,
+	genreID string,
+) error {
+	return l.genreRepository.DeleteGenre(ctx, genreID)
+}
+
+func (l *libraryImpl) ListGenres(
+	ctx context.Context,
+	rootID string,
+) ([]*entity.Genre, error) {
+	return l.genreRepository.ListGenres(ctx, rootID)
+}
+
+func normalizeGenreName(name string) (string, error) {
+	name = strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+	if name == "" {
+		return "", entity.ErrInvalidGenreName
+	}
+
+	return name, nil
+}
+
+// normalizeTags lowercases tags, trims them and drops repeats, so that
+// "Sci-Fi " and "sci-fi" are the same tag.
+func normalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(norm.NFC.String(tag)), " "))
+		if tag == "" {
+			return nil, entity.ErrInvalidTag
+		}
+
+		if !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,231 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+// holdPickupDays is how long a copy stays set aside for a ready hold.
+const holdPickupDays = 7
+
+// Transactions lock the rows of a book's circulation in one order: the
+// book (LockHoldQueue), then its copies, then its holds. A hold or a copy
+// is read unlocked first when its book isn't known yet.
+
+// PlaceHold queues the patron for the book. Holds are only for books with
+// no copy to check out right away.
+func (l *libraryImpl) PlaceHold(
+	ctx context.Context,
+	bookID string,
+	patronID string,
+) (*entity.Hold, error) {
+	var hold *entity.Hold
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		patron, txErr := l.patronRepository.GetPatron(ctx, patronID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = patron.CheckInGoodStanding(time.Now()); txErr != nil {
+			return txErr
+		}
+
+		if txErr = l.holdRepository.LockHoldQueue(ctx, bookID); txErr != nil {
+			return txErr
+		}
+
+		availability, txErr := l.copyRepository.GetBookAvailability(ctx, bookID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if availability.Available > 0 {
+			return entity.ErrBookAvailable
+		}
+
+		hold, txErr = l.holdRepository.PlaceHold(ctx, &entity.Hold{
+			BookID:   bookID,
+			PatronID: patronID,
+		})
+		return txErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// CancelHold takes the hold out of the queue. The copy set aside for a
+// ready hold goes to the next hold.
+func (l *libraryImpl) CancelHold(
+	ctx context.Context,
+	holdID string,
+) (*entity.Hold, error) {
+	var hold *entity.Hold
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		unlocked, txErr := l.holdRepository.GetHold(ctx, holdID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = l.holdRepository.LockHoldQueue(ctx, unlocked.BookID); txErr != nil {
+			return txErr
+		}
+
+		before, txErr := l.holdRepository.GetHoldForUpdate(ctx, holdID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if !before.Active() {
+			return entity.ErrHoldNotActive
+		}
+
+		hold, txErr = l.holdRepository.SetHoldStatus(ctx, holdID, entity.HoldStatusCancelled)
+		if txErr != nil {
+			return txErr
+		}
+
+		if before.Status != entity.HoldStatusReady || before.CopyID == "" {
+			return nil
+		}
+
+		return l.advanceHolds(ctx, before.BookID, before.CopyID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func (l *libraryImpl) ListHolds(
+	ctx context.Context,
+	filter entity.HoldsFilter,
+) ([]*entity.Hold, error) {
+	if filter.BookID == "" && filter.PatronID == "" {
+		return nil, entity.ErrInvalidHoldsFilter
+	}
+
+	return l.holdRepository.ListHolds(ctx, filter)
+}
+
+// ExpireHolds expires the ready holds which weren't picked up in time and
+// passes their copies on. Each hold is expired in its own transaction; a
+// hold that another replica expired or a patron picked up meanwhile is
+// skipped.
+func (l *libraryImpl) ExpireHolds(ctx context.Context) (int, error) {
+	expired := 0
+
+	for {
+		done := false
+
+		err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+			unlocked, txErr := l.holdRepository.GetExpiredHold(ctx, today())
+			if txErr != nil {
+				return txErr
+			}
+
+			if txErr = l.holdRepository.LockHoldQueue(ctx, unlocked.BookID); txErr != nil {
+				return txErr
+			}
+
+			hold, txErr := l.holdRepository.GetHoldForUpdate(ctx, unlocked.ID)
+			if txErr != nil {
+				return txErr
+			}
+
+			if hold.Status != entity.HoldStatusReady {
+				return nil
+			}
+
+			if _, txErr = l.holdRepository.SetHoldStatus(ctx, hold.ID, entity.HoldStatusExpired); txErr != nil {
+				return txErr
+			}
+
+			done = true
+
+			if hold.CopyID == "" {
+				return nil
+			}
+
+			return l.advanceHolds(ctx, hold.BookID, hold.CopyID)
+		})
+
+		if errors.Is(err, entity.ErrHoldNotFound) {
+			return expired, nil
+		}
+		if err != nil {
+			return expired, err
+		}
+
+		if done {
+			expired++
+		}
+	}
+}
+
+// advanceHolds sets the copy aside for the next waiting hold of the book
+// and notifies the patron, or makes the copy available when nobody waits.
+func (l *libraryImpl) advanceHolds(
+	ctx context.Context,
+	bookID string,
+	copyID string,
+) error {
+	if err := l.holdRepository.LockHoldQueue(ctx, bookID); err != nil {
+		return err
+	}
+
+	setAside, err := l.setAsideForNextHold(ctx, bookID, copyID)
+	if err != nil || setAside != nil {
+		return err
+	}
+
+	return l.setCopyStatus(ctx, copyID, entity.CopyStatusAvailable)
+}
+
+// setAsideForNextHold sets the copy aside for the next waiting hold of the
+// book and notifies the patron. It returns the copy set aside, or nil when
+// nobody waits. The hold queue of the book must be locked.
+func (l *libraryImpl) setAsideForNextHold(
+	ctx context.Context,
+	bookID string,
+	copyID string,
+) (*entity.Copy, error) {
+	next, err := l.holdRepository.GetNextWaitingHold(ctx, bookID)
+	if errors.Is(err, entity.ErrHoldNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err := l.holdRepository.MarkHoldReady(ctx, next.ID, copyID,
+		today().AddDate(0, 0, holdPickupDays))
+	if err != nil {
+		return nil, err
+	}
+
+	onHold := entity.CopyStatusOnHold
+	bookCopy, err := l.copyRepository.UpdateCopy(ctx, copyID, entity.CopyUpdate{Status: &onHold})
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.sendOutboxMessage(ctx, repository.OutboxKindHoldReady, hold.BookID,
+		idempotencyKey(repository.OutboxKindHoldReady, hold.ID), hold)
+	if err != nil {
+		return nil, err
+	}
+
+	return bookCopy, nil
+}
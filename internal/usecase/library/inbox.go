@@ -0,0 +1,110 @@
+package library
+
+import (
+	"context"
+	"errors"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+// The inbox events are applied in the transaction that marks the inbox
+// message as processed, so they call the repositories directly instead of
+// opening a transaction of their own.
+
+// ApplyBookPurchased must be called inside Transactor.WithTx.
+func (l *libraryImpl) ApplyBookPurchased(
+	ctx context.Context,
+	event entity.BookPurchased,
+) error {
+	newBook := &entity.Book{
+		Name:            event.Name,
+		AuthorIDs:       event.AuthorIDs,
+		Publisher:       event.Publisher,
+		PublicationYear: event.PublicationYear,
+		Language:        event.Language,
+		PageCount:       event.PageCount,
+		Description:     event.Description,
+	}
+
+	if event.ISBN != "" {
+		isbn, err := normalizeISBN(event.ISBN)
+		if err != nil {
+			return err
+		}
+		newBook.ISBN = isbn
+
+		_, err = l.booksRepository.GetBookByISBN(ctx, isbn)
+		switch {
+		case err == nil:
+			// Another copy of a title the catalog already has.
+			return nil
+		case !errors.Is(err, entity.ErrBookNotFound):
+			return err
+		}
+	}
+
+	book, err := l.booksRepository.AddBook(ctx, newBook)
+	if err != nil {
+		return err
+	}
+
+	return l.sendOutboxMessage(ctx, repository.OutboxKindBook, book.ID,
+		idempotencyKey(repository.OutboxKindBook, book.ID), book)
+}
+
+// ApplyAuthorMerged must be called inside Transactor.WithTx. Deleted books
+// of the source keep it as their author.
+func (l *libraryImpl) ApplyAuthorMerged(
+	ctx context.Context,
+	event entity.AuthorMerged,
+) error {
+	if event.SourceID == event.TargetID {
+		return entity.ErrAuthorMergedIntoItself
+	}
+
+	if _, err := l.authorRepository.GetAuthorForUpdate(ctx, event.TargetID); err != nil {
+		return err
+	}
+
+	if _, err := l.authorRepository.GetAuthorForUpdate(ctx, event.SourceID); err != nil {
+		return err
+	}
+
+	books, err := l.authorRepository.GetAuthorBooks(ctx, event.SourceID, false)
+	if err != nil {
+		return err
+	}
+
+	update := entity.BookUpdate{
+		AddAuthorIDs:    []string{event.TargetID},
+		RemoveAuthorIDs: []string{event.SourceID},
+	}
+
+	for _, book := range books {
+		before, err := l.booksRepository.GetBookForUpdate(ctx, book.ID)
+		if err != nil {
+			return err
+		}
+
+		after, err := l.booksRepository.UpdateBook(ctx, book.ID, update, 0)
+		if err != nil {
+			return err
+		}
+
+		err = l.sendOutboxMessage(ctx, repository.OutboxKindBookUpdated, after.ID,
+			versionedIdempotencyKey(repository.OutboxKindBookUpdated, after.ID, after.Version),
+			entity.BookChange{Before: before, After: after})
+		if err != nil {
+			return err
+		}
+	}
+
+	author, err := l.authorRepository.SoftDeleteAuthor(ctx, event.SourceID)
+	if err != nil {
+		return err
+	}
+
+	return l.sendOutboxMessage(ctx, repository.OutboxKindAuthorDeleted, author.ID,
+		versionedIdempotencyKey(repository.OutboxKindAuthorDeleted, author.ID, author.Version), author)
+}
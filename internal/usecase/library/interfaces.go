@@ -2,6 +2,7 @@ package library
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -13,19 +14,94 @@ import (
 
 var _ AuthorUseCase = (*libraryImpl)(nil)
 var _ BooksUseCase = (*libraryImpl)(nil)
+var _ GenreUseCase = (*libraryImpl)(nil)
+var _ CopyUseCase = (*libraryImpl)(nil)
+var _ PatronUseCase = (*libraryImpl)(nil)
+var _ LoanUseCase = (*libraryImpl)(nil)
+var _ HoldUseCase = (*libraryImpl)(nil)
+var _ FineUseCase = (*libraryImpl)(nil)
+var _ OutboxAdminUseCase = (*libraryImpl)(nil)
+var _ InboxUseCase = (*libraryImpl)(nil)
 
 type (
 	AuthorUseCase interface {
-		RegisterAuthor(ctx context.Context, authorName string) (*entity.Author, error)
+		RegisterAuthor(ctx context.Context, author *entity.Author) (*entity.Author, error)
 		GetAuthorInfo(ctx context.Context, authorID string) (*entity.Author, error)
-		ChangeAuthor(ctx context.Context, authorID string, newAuthorName string) error
-		GetAuthorBooks(ctx context.Context, authorID string) ([]*entity.Book, error)
+		ChangeAuthor(ctx context.Context, authorID string, update entity.AuthorUpdate, expectedVersion int64) (*entity.Author, error)
+		GetAuthorBooks(ctx context.Context, authorID string, showDeleted bool) ([]*entity.Book, error)
+		DeleteAuthor(ctx context.Context, authorID string, purge bool) error
+		UndeleteAuthor(ctx context.Context, authorID string) (*entity.Author, error)
+		ListAuthors(ctx context.Context, filter entity.AuthorsFilter, pageSize int, pageToken string) ([]*entity.Author, string, error)
 	}
 
 	BooksUseCase interface {
-		AddBook(ctx context.Context, name string, authorIDs []string) (*entity.Book, error)
-		GetBook(ctx context.Context, bookID string) (*entity.Book, error)
-		UpdateBook(ctx context.Context, bookID string, newBookName string, authorIDs []string) error
+		AddBook(ctx context.Context, book *entity.Book) (*entity.Book, error)
+		GetBook(ctx context.Context, bookID string, showDeleted bool) (*entity.Book, error)
+		GetBookByISBN(ctx context.Context, isbn string) (*entity.Book, error)
+		UpdateBook(ctx context.Context, bookID string, update entity.BookUpdate, expectedVersion int64) (*entity.Book, error)
+		ListBooks(ctx context.Context, filter entity.BooksFilter, pageSize int, pageToken string) ([]*entity.Book, string, error)
+		DeleteBook(ctx context.Context, bookID string, purge bool) error
+		UndeleteBook(ctx context.Context, bookID string) (*entity.Book, error)
+		SearchCatalog(ctx context.Context, filter entity.SearchFilter, pageSize int, pageToken string) ([]*entity.SearchResult, string, error)
+	}
+
+	GenreUseCase interface {
+		CreateGenre(ctx context.Context, genre *entity.Genre) (*entity.Genre, error)
+		GetGenre(ctx context.Context, genreID string) (*entity.Genre, error)
+		UpdateGenre(ctx context.Context, genreID string, update entity.GenreUpdate) (*entity.Genre, error)
+		DeleteGenre(ctx context.Context, genreID string) error
+		ListGenres(ctx context.Context, rootID string) ([]*entity.Genre, error)
+	}
+
+	CopyUseCase interface {
+		AddCopy(ctx context.Context, copy *entity.Copy) (*entity.Copy, error)
+		GetCopy(ctx context.Context, copyID string) (*entity.Copy, error)
+		UpdateCopy(ctx context.Context, copyID string, update entity.CopyUpdate) (*entity.Copy, error)
+		DeleteCopy(ctx context.Context, copyID string) error
+		ListCopies(ctx context.Context, bookID string, filter entity.CopiesFilter) ([]*entity.Copy, error)
+		GetBookAvailability(ctx context.Context, bookID string) (*entity.BookAvailability, error)
+	}
+
+	PatronUseCase interface {
+		RegisterPatron(ctx context.Context, patron *entity.Patron) (*entity.Patron, error)
+		GetPatron(ctx context.Context, patronID string) (*entity.Patron, error)
+		GetPatronByCardNumber(ctx context.Context, cardNumber string) (*entity.Patron, error)
+		UpdatePatron(ctx context.Context, patronID string, update entity.PatronUpdate) (*entity.Patron, error)
+		BlockPatron(ctx context.Context, patronID string, reason string) (*entity.Patron, error)
+		UnblockPatron(ctx context.Context, patronID string) (*entity.Patron, error)
+	}
+
+	LoanUseCase interface {
+		CheckoutBook(ctx context.Context, copyID string, patronID string) (*entity.Loan, error)
+		ReturnBook(ctx context.Context, loanID string) (*entity.Loan, error)
+		RenewLoan(ctx context.Context, loanID string) (*entity.Loan, error)
+	}
+
+	HoldUseCase interface {
+		PlaceHold(ctx context.Context, bookID string, patronID string) (*entity.Hold, error)
+		CancelHold(ctx context.Context, holdID string) (*entity.Hold, error)
+		ListHolds(ctx context.Context, filter entity.HoldsFilter) ([]*entity.Hold, error)
+		ExpireHolds(ctx context.Context) (int, error)
+	}
+
+	FineUseCase interface {
+		AccrueFines(ctx context.Context, policy entity.FinePolicy) (int, error)
+		GetPatronAccount(ctx context.Context, patronID string) (*entity.PatronAccount, error)
+		PayFine(ctx context.Context, fineID string, amount int64) (*entity.Fine, error)
+	}
+
+	OutboxAdminUseCase interface {
+		ListOutboxMessages(ctx context.Context, filter repository.OutboxFilter, pageSize int, pageToken string) ([]*repository.OutboxMessage, string, error)
+		GetOutboxMessage(ctx context.Context, idempotencyKey string) (*repository.OutboxMessage, error)
+		RetryOutboxMessage(ctx context.Context, idempotencyKey string) (*repository.OutboxMessage, error)
+		RetryAllDead(ctx context.Context) (int64, error)
+		PurgeOutbox(ctx context.Context, olderThan time.Duration) (int64, error)
+	}
+
+	// InboxUseCase applies the events received from other services.
+	InboxUseCase interface {
+		ApplyBookPurchased(ctx context.Context, event entity.BookPurchased) error
+		ApplyAuthorMerged(ctx context.Context, event entity.AuthorMerged) error
 	}
 )
 
@@ -33,22 +109,47 @@ type libraryImpl struct {
 	logger           *zap.Logger
 	authorRepository repository.AuthorRepository
 	booksRepository  repository.BooksRepository
+	genreRepository  repository.GenreRepository
+	copyRepository   repository.CopyRepository
+	patronRepository repository.PatronRepository
+	loanRepository   repository.LoanRepository
+	holdRepository   repository.HoldRepository
+	fineRepository   repository.FineRepository
 	outboxRepository repository.OutboxRepository
 	transactor       repository.Transactor
 }
 
+// Repositories are the repositories the use cases work with, and the
+// transactor that runs them in one transaction. The ones a caller has no
+// use for may be left nil.
+type Repositories struct {
+	Author     repository.AuthorRepository
+	Books      repository.BooksRepository
+	Genre      repository.GenreRepository
+	Copy       repository.CopyRepository
+	Patron     repository.PatronRepository
+	Loan       repository.LoanRepository
+	Hold       repository.HoldRepository
+	Fine       repository.FineRepository
+	Outbox     repository.OutboxRepository
+	Transactor repository.Transactor
+}
+
 func New(
 	logger *zap.Logger,
-	authorRepository repository.AuthorRepository,
-	booksRepository repository.BooksRepository,
-	outboxRepository repository.OutboxRepository,
-	transactor repository.Transactor,
+	repositories Repositories,
 ) *libraryImpl {
 	return &libraryImpl{
 		logger:           logger,
-		authorRepository: authorRepository,
-		booksRepository:  booksRepository,
-		outboxRepository: outboxRepository,
-		transactor:       transactor,
+		authorRepository: repositories.Author,
+		booksRepository:  repositories.Books,
+		genreRepository:  repositories.Genre,
+		copyRepository:   repositories.Copy,
+		patronRepository: repositories.Patron,
+		loanRepository:   repositories.Loan,
+		holdRepository:   repositories.Hold,
+		fineRepository:   repositories.Fine,
+		outboxRepository: repositories.Outbox,
+		transactor:       repositories.Transactor,
 	}
 }
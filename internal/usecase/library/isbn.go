@@ -0,0 +1,88 @@
+package library
+
+import (
+	"strings"
+
+	"github.com/project/library/internal/entity"
+)
+
+// normalizeISBN validates an ISBN-10 or ISBN-13 and returns it as ISBN-13
+// digits, so that both forms of the same number compare equal.
+func normalizeISBN(isbn string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ':
+			return -1
+		case 'x':
+			return 'X'
+		default:
+			return r
+		}
+	}, isbn)
+
+	switch len(digits) {
+	case 10:
+		if !validISBN10(digits) {
+			return "", entity.ErrInvalidISBN
+		}
+
+		isbn13 := "978" + digits[:9]
+		return isbn13 + string(isbn13CheckDigit(isbn13)), nil
+	case 13:
+		if !allDigits(digits) ||
+			!(strings.HasPrefix(digits, "978") || strings.HasPrefix(digits, "979")) ||
+			isbn13CheckDigit(digits[:12]) != digits[12] {
+			return "", entity.ErrInvalidISBN
+		}
+
+		return digits, nil
+	default:
+		return "", entity.ErrInvalidISBN
+	}
+}
+
+func validISBN10(digits string) bool {
+	if !allDigits(digits[:9]) {
+		return false
+	}
+
+	sum := 0
+	for i := range 9 {
+		sum += (10 - i) * int(digits[i]-'0')
+	}
+
+	switch last := digits[9]; {
+	case last == 'X':
+		sum += 10
+	case last >= '0' && last <= '9':
+		sum += int(last - '0')
+	default:
+		return false
+	}
+
+	return sum%11 == 0
+}
+
+// isbn13CheckDigit computes the check digit for the first 12 digits.
+func isbn13CheckDigit(digits string) byte {
+	sum := 0
+	for i := range 12 {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(digits[i]-'0')
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func allDigits(s string) bool {
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,206 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+const (
+	loanPeriodDays = 21
+	maxOpenLoans   = 5
+	maxRenewals    = 2
+)
+
+// CheckoutBook lends the copy to the patron. The patron and copy rows are
+// locked, so concurrent checkouts of the copy or by the patron are
+// serialized and can't exceed the limits. A copy on hold is lent only to
+// the patron it's set aside for, which fulfills the hold; the hold queue
+// of the book is locked before the copy, as everywhere else.
+func (l *libraryImpl) CheckoutBook(
+	ctx context.Context,
+	copyID string,
+	patronID string,
+) (*entity.Loan, error) {
+	var loan *entity.Loan
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		patron, txErr := l.patronRepository.GetPatronForUpdate(ctx, patronID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = patron.CheckInGoodStanding(time.Now()); txErr != nil {
+			return txErr
+		}
+
+		unlocked, txErr := l.copyRepository.GetCopy(ctx, copyID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = l.holdRepository.LockHoldQueue(ctx, unlocked.BookID); txErr != nil {
+			return txErr
+		}
+
+		bookCopy, txErr := l.copyRepository.GetCopyForUpdate(ctx, copyID)
+		if txErr != nil {
+			return txErr
+		}
+
+		var hold *entity.Hold
+		switch bookCopy.Status {
+		case entity.CopyStatusAvailable:
+		case entity.CopyStatusOnHold:
+			hold, txErr = l.holdRepository.GetReadyHoldForCopy(ctx, copyID)
+			if errors.Is(txErr, entity.ErrHoldNotFound) || (txErr == nil && hold.PatronID != patronID) {
+				return entity.ErrCopyNotAvailable
+			}
+			if txErr != nil {
+				return txErr
+			}
+		default:
+			return entity.ErrCopyNotAvailable
+		}
+
+		openLoans, txErr := l.loanRepository.CountOpenLoans(ctx, patronID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if openLoans >= maxOpenLoans {
+			return entity.ErrLoanLimitReached
+		}
+
+		loan, txErr = l.loanRepository.CreateLoan(ctx, &entity.Loan{
+			CopyID:   copyID,
+			PatronID: patronID,
+			DueOn:    today().AddDate(0, 0, loanPeriodDays),
+		})
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = l.setCopyStatus(ctx, copyID, entity.CopyStatusOnLoan); txErr != nil {
+			return txErr
+		}
+
+		if hold != nil {
+			if _, txErr = l.holdRepository.SetHoldStatus(ctx, hold.ID, entity.HoldStatusFulfilled); txErr != nil {
+				return txErr
+			}
+		}
+
+		return l.sendOutboxMessage(ctx, repository.OutboxKindLoanCheckedOut, loan.BookID,
+			idempotencyKey(repository.OutboxKindLoanCheckedOut, loan.ID), loan)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+// ReturnBook closes the loan and passes the copy on to the next hold of
+// the book, if any.
+func (l *libraryImpl) ReturnBook(
+	ctx context.Context,
+	loanID string,
+) (*entity.Loan, error) {
+	var loan *entity.Loan
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		before, txErr := l.loanRepository.GetLoanForUpdate(ctx, loanID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if before.ReturnedAt != nil {
+			return entity.ErrLoanAlreadyReturned
+		}
+
+		loan, txErr = l.loanRepository.ReturnLoan(ctx, loanID)
+		if txErr != nil {
+			return txErr
+		}
+
+		txErr = l.sendOutboxMessage(ctx, repository.OutboxKindLoanReturned, loan.BookID,
+			idempotencyKey(repository.OutboxKindLoanReturned, loan.ID), loan)
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.advanceHolds(ctx, loan.BookID, loan.CopyID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+// RenewLoan extends the loan by another loan period, counted from the
+// current due date or from today for an overdue loan.
+func (l *libraryImpl) RenewLoan(
+	ctx context.Context,
+	loanID string,
+) (*entity.Loan, error) {
+	var loan *entity.Loan
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		before, txErr := l.loanRepository.GetLoanForUpdate(ctx, loanID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if before.ReturnedAt != nil {
+			return entity.ErrLoanAlreadyReturned
+		}
+
+		if before.Renewals >= maxRenewals {
+			return entity.ErrRenewalLimitReached
+		}
+
+		patron, txErr := l.patronRepository.GetPatron(ctx, before.PatronID)
+		if txErr != nil {
+			return txErr
+		}
+
+		if txErr = patron.CheckInGoodStanding(time.Now()); txErr != nil {
+			return txErr
+		}
+
+		from := before.DueOn
+		if now := today(); from.Before(now) {
+			from = now
+		}
+
+		loan, txErr = l.loanRepository.RenewLoan(ctx, loanID, from.AddDate(0, 0, loanPeriodDays))
+		if txErr != nil {
+			return txErr
+		}
+
+		return l.sendOutboxMessage(ctx, repository.OutboxKindLoanRenewed, loan.BookID,
+			versionedIdempotencyKey(repository.OutboxKindLoanRenewed, loan.ID, int64(loan.Renewals)), loan)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return loan, nil
+}
+
+func (l *libraryImpl) setCopyStatus(
+	ctx context.Context,
+	copyID string,
+	copyStatus entity.CopyStatus,
+) error {
+	_, err := l.copyRepository.UpdateCopy(ctx, copyID, entity.CopyUpdate{Status: &copyStatus})
+	return err
+}
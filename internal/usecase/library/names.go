@@ -0,0 +1,113 @@
+package library
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/project/library/internal/entity"
+)
+
+// normalizeAuthorName brings a name to NFC, trims it and collapses inner
+// whitespace. A name is made of words of letters, combining marks and
+// digits; words may also contain apostrophes, hyphens and periods, as in
+// "O'Brien", "Jean-Paul" or "J. R. R. Tolkien".
+func normalizeAuthorName(name string) (string, error) {
+	words := strings.Fields(norm.NFC.String(name))
+	if len(words) == 0 {
+		return "", entity.ErrInvalidAuthorName
+	}
+
+	for _, word := range words {
+		if !validNameWord(word) {
+			return "", entity.ErrInvalidAuthorName
+		}
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+func validNameWord(word string) bool {
+	hasLetterOrDigit := false
+	for _, r := range word {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			hasLetterOrDigit = true
+		case unicode.IsMark(r), strings.ContainsRune("'’-.", r):
+		default:
+			return false
+		}
+	}
+
+	return hasLetterOrDigit
+}
+
+// normalizeAlternateNames normalizes every name and drops repeats.
+func normalizeAlternateNames(names []string) ([]string, error) {
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		normalized, err := normalizeAuthorName(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(result, normalized) {
+			result = append(result, normalized)
+		}
+	}
+
+	return result, nil
+}
+
+// normalizeAuthor normalizes the names of a new author and checks its dates.
+func normalizeAuthor(author *entity.Author) error {
+	var err error
+	if author.Name, err = normalizeAuthorName(author.Name); err != nil {
+		return err
+	}
+
+	if author.AlternateNames, err = normalizeAlternateNames(author.AlternateNames); err != nil {
+		return err
+	}
+
+	author.Nationality = strings.TrimSpace(author.Nationality)
+
+	return checkLifespan(author.BirthDate, author.DeathDate)
+}
+
+// normalizeAuthorUpdate normalizes the names an update sets.
+func normalizeAuthorUpdate(update *entity.AuthorUpdate) error {
+	if update.Name != nil {
+		name, err := normalizeAuthorName(*update.Name)
+		if err != nil {
+			return err
+		}
+		update.Name = &name
+	}
+
+	if update.SetAlternateNames {
+		names, err := normalizeAlternateNames(update.AlternateNames)
+		if err != nil {
+			return err
+		}
+		update.AlternateNames = names
+	}
+
+	if update.Nationality != nil {
+		nationality := strings.TrimSpace(*update.Nationality)
+		update.Nationality = &nationality
+	}
+
+	return nil
+}
+
+func checkLifespan(birthDate, deathDate *time.Time) error {
+	if birthDate != nil && deathDate != nil && deathDate.Before(*birthDate) {
+		return entity.ErrInvalidAuthorDates
+	}
+
+	return nil
+}
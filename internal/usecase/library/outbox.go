@@ -0,0 +1,47 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/project/library/internal/usecase/repository"
+)
+
+// sendOutboxMessage must be called inside Transactor.WithTx so that the
+// message is committed together with the change it describes. Messages of
+// one aggregate are numbered in the order they are committed: copy, loan
+// and hold events change the availability of a book, so the book is their
+// aggregate.
+func (l *libraryImpl) sendOutboxMessage(
+	ctx context.Context,
+	kind repository.OutboxKind,
+	aggregateKey string,
+	idempotencyKey string,
+	payload any,
+) error {
+	span := trace.SpanFromContext(ctx)
+	traceID := span.SpanContext().TraceID().String()
+
+	serialized, err := json.Marshal(payload)
+	if err != nil {
+		span.RecordError(fmt.Errorf("error serializing %s: %w", kind, err))
+		return err
+	}
+
+	return l.outboxRepository.SendMessage(
+		ctx, idempotencyKey, kind, aggregateKey, serialized, traceID)
+}
+
+func idempotencyKey(kind repository.OutboxKind, id string) string {
+	return kind.String() + "_" + id
+}
+
+// versionedIdempotencyKey is used for events that can happen to the same
+// entity more than once, e.g. updates or delete and restore cycles.
+func versionedIdempotencyKey(kind repository.OutboxKind, id string, version int64) string {
+	return idempotencyKey(kind, id) + "_" + strconv.FormatInt(version, 10)
+}
@@ -0,0 +1,113 @@
+package library
+
+import (
+	"context"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+const outboxPurgeBatchSize = 1000
+
+func (l *libraryImpl) ListOutboxMessages(
+	ctx context.Context,
+	filter repository.OutboxFilter,
+	pageSize int,
+	pageToken string,
+) ([]*repository.OutboxMessage, string, error) {
+	var after *repository.OutboxCursor
+	if pageToken != "" {
+		var err error
+		after, err = decodeOutboxCursor(pageToken, filter)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	limit := normalizePageSize(pageSize)
+
+	// One extra row tells whether there is a next page.
+	messages, err := l.outboxRepository.ListMessages(ctx, filter, after, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(messages) <= limit {
+		return messages, "", nil
+	}
+
+	messages = messages[:limit]
+	last := messages[limit-1]
+
+	nextPageToken, err := encodePageToken(newOutboxPageToken(last, filter))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return messages, nextPageToken, nil
+}
+
+func (l *libraryImpl) GetOutboxMessage(
+	ctx context.Context,
+	idempotencyKey string,
+) (*repository.OutboxMessage, error) {
+	return l.outboxRepository.GetMessage(ctx, idempotencyKey)
+}
+
+// RetryOutboxMessage sends a failed, dead or already delivered message
+// again. A message that is still pending would be sent twice, so it is
+// rejected.
+func (l *libraryImpl) RetryOutboxMessage(
+	ctx context.Context,
+	idempotencyKey string,
+) (*repository.OutboxMessage, error) {
+	var message *repository.OutboxMessage
+
+	err := l.transactor.WithTx(ctx, func(ctx context.Context) error {
+		before, txErr := l.outboxRepository.GetMessageForUpdate(ctx, idempotencyKey)
+		if txErr != nil {
+			return txErr
+		}
+
+		if before.Status.Pending() {
+			return entity.ErrOutboxMessagePending
+		}
+
+		message, txErr = l.outboxRepository.RetryMessage(ctx, idempotencyKey)
+		return txErr
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func (l *libraryImpl) RetryAllDead(ctx context.Context) (int64, error) {
+	return l.outboxRepository.RetryDeadMessages(ctx)
+}
+
+// PurgeOutbox deletes the delivered messages older than olderThan. They are
+// deleted in batches, so that no single statement holds many row locks or
+// takes long.
+func (l *libraryImpl) PurgeOutbox(ctx context.Context, olderThan time.Duration) (int64, error) {
+	var total int64
+
+	for {
+		deleted, err := l.outboxRepository.PurgeProcessed(ctx, olderThan, outboxPurgeBatchSize)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+
+		if deleted < outboxPurgeBatchSize {
+			return total, nil
+		}
+
+		if err = ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package library
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// bookPageToken keeps the filter and the sort order, so that a token
+// can't be reused with other ones.
+type bookPageToken struct {
+	CreatedAt     time.Time        `json:"c"`
+	ID            string           `json:"i"`
+	SortOrder     entity.SortOrder `json:"o"`
+	NamePrefix    string           `json:"n,omitempty"`
+	AuthorID      string           `json:"a,omitempty"`
+	GenreID       string           `json:"g,omitempty"`
+	Tag           string           `json:"t,omitempty"`
+	CreatedAfter  *time.Time       `json:"ca,omitempty"`
+	CreatedBefore *time.Time       `json:"cb,omitempty"`
+	UpdatedAfter  *time.Time       `json:"ua,omitempty"`
+	UpdatedBefore *time.Time       `json:"ub,omitempty"`
+}
+
+func newBookPageToken(last *entity.Book, filter entity.BooksFilter) bookPageToken {
+	return bookPageToken{
+		CreatedAt:     last.CreatedAt,
+		ID:            last.ID,
+		SortOrder:     filter.SortOrder,
+		NamePrefix:    filter.NamePrefix,
+		AuthorID:      filter.AuthorID,
+		GenreID:       filter.GenreID,
+		Tag:           filter.Tag,
+		CreatedAfter:  filter.CreatedAfter,
+		CreatedBefore: filter.CreatedBefore,
+		UpdatedAfter:  filter.UpdatedAfter,
+		UpdatedBefore: filter.UpdatedBefore,
+	}
+}
+
+func (t bookPageToken) matches(filter entity.BooksFilter) bool {
+	return t.SortOrder == filter.SortOrder &&
+		t.NamePrefix == filter.NamePrefix &&
+		t.AuthorID == filter.AuthorID &&
+		t.GenreID == filter.GenreID &&
+		t.Tag == filter.Tag &&
+		sameTime(t.CreatedAfter, filter.CreatedAfter) &&
+		sameTime(t.CreatedBefore, filter.CreatedBefore) &&
+		sameTime(t.UpdatedAfter, filter.UpdatedAfter) &&
+		sameTime(t.UpdatedBefore, filter.UpdatedBefore)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+// authorPageToken keeps the name filter, so that a token can't be
+// reused with another one.
+type authorPageToken struct {
+	Name      string           `json:"n"`
+	ID        string           `json:"i"`
+	NameQuery string           `json:"q,omitempty"`
+	NameMatch entity.NameMatch `json:"m,omitempty"`
+}
+
+// outboxPageToken keeps the filter, so that a token can't be reused with
+// another one.
+type outboxPageToken struct {
+	CreatedAt     time.Time               `json:"c"`
+	Key           string                  `json:"k"`
+	Status        repository.OutboxStatus `json:"s,omitempty"`
+	Kind          repository.OutboxKind   `json:"t,omitempty"`
+	CreatedAfter  time.Time               `json:"ca,omitzero"`
+	CreatedBefore time.Time               `json:"cb,omitzero"`
+}
+
+func newOutboxPageToken(last *repository.OutboxMessage, filter repository.OutboxFilter) outboxPageToken {
+	return outboxPageToken{
+		CreatedAt:     last.CreatedAt,
+		Key:           last.IdempotencyKey,
+		Status:        filter.Status,
+		Kind:          filter.Kind,
+		CreatedAfter:  filter.CreatedAfter,
+		CreatedBefore: filter.CreatedBefore,
+	}
+}
+
+func (t outboxPageToken) matches(filter repository.OutboxFilter) bool {
+	return t.Status == filter.Status &&
+		t.Kind == filter.Kind &&
+		t.CreatedAfter.Equal(filter.CreatedAfter) &&
+		t.CreatedBefore.Equal(filter.CreatedBefore)
+}
+
+// searchPageToken pages by offset: relevance scores have no stable
+// order to resume from. The filter is kept so that a token can't be
+// reused with another search.
+type searchPageToken struct {
+	Query   string `json:"q"`
+	GenreID string `json:"g,omitempty"`
+	Offset  int    `json:"o"`
+}
+
+func normalizePageSize(pageSize int) int {
+	switch {
+	case pageSize <= 0:
+		return defaultPageSize
+	case pageSize > maxPageSize:
+		return maxPageSize
+	default:
+		return pageSize
+	}
+}
+
+func encodePageToken(token any) (string, error) {
+	raw, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodePageToken(pageToken string, token any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return entity.ErrInvalidPageToken
+	}
+
+	if err = json.Unmarshal(raw, token); err != nil {
+		return entity.ErrInvalidPageToken
+	}
+
+	return nil
+}
+
+func decodeBookCursor(
+	pageToken string,
+	filter entity.BooksFilter,
+) (*entity.BookCursor, error) {
+	var token bookPageToken
+	if err := decodePageToken(pageToken, &token); err != nil {
+		return nil, err
+	}
+
+	if token.ID == "" || !token.matches(filter) {
+		return nil, entity.ErrInvalidPageToken
+	}
+
+	return &entity.BookCursor{
+		CreatedAt: token.CreatedAt,
+		ID:        token.ID,
+	}, nil
+}
+
+func decodeAuthorCursor(
+	pageToken string,
+	filter entity.AuthorsFilter,
+) (*entity.AuthorCursor, error) {
+	var token authorPageToken
+	if err := decodePageToken(pageToken, &token); err != nil {
+		return nil, err
+	}
+
+	if token.ID == "" || token.NameQuery != filter.NameQuery || token.NameMatch != filter.NameMatch {
+		return nil, entity.ErrInvalidPageToken
+	}
+
+	return &entity.AuthorCursor{
+		Name: token.Name,
+		ID:   token.ID,
+	}, nil
+}
+
+func decodeOutboxCursor(
+	pageToken string,
+	filter repository.OutboxFilter,
+) (*repository.OutboxCursor, error) {
+	var token outboxPageToken
+	if err := decodePageToken(pageToken, &token); err != nil {
+		return nil, err
+	}
+
+	if token.Key == "" || !token.matches(filter) {
+		return nil, entity.ErrInvalidPageToken
+	}
+
+	return &repository.OutboxCursor{
+		CreatedAt:      token.CreatedAt,
+		IdempotencyKey: token.Key,
+	}, nil
+}
+
+func decodeSearchOffset(pageToken string, filter entity.SearchFilter) (int, error) {
+	var token searchPageToken
+	if err := decodePageToken(pageToken, &token); err != nil {
+		return 0, err
+	}
+
+	if token.Query != filter.Query || token.GenreID != filter.GenreID || token.Offset <= 0 {
+		return 0, entity.ErrInvalidPageToken
+	}
+
+	return token.Offset, nil
+}
@@ -0,0 +1,139 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/project/library/internal/entity"
+)
+
+const (
+	defaultMembershipYears = 1
+
+	// cardNumberAttempts bounds the retries on a card number collision,
+	// which is unlikely with 11 random digits.
+	cardNumberAttempts = 3
+)
+
+func (l *libraryImpl) RegisterPatron(
+	ctx context.Context,
+	newPatron *entity.Patron,
+) (*entity.Patron, error) {
+	name, err := normalizePatronName(newPatron.Name)
+	if err != nil {
+		return nil, err
+	}
+	newPatron.Name = name
+	newPatron.Email = normalizeEmail(newPatron.Email)
+	newPatron.Phone = strings.TrimSpace(newPatron.Phone)
+	newPatron.Address = strings.TrimSpace(newPatron.Address)
+
+	if newPatron.MembershipExpiresOn.IsZero() {
+		newPatron.MembershipExpiresOn = today().AddDate(defaultMembershipYears, 0, 0)
+	} else if newPatron.MembershipExpiresOn.Before(today()) {
+		return nil, entity.ErrInvalidMembershipExpiry
+	}
+
+	for range cardNumberAttempts {
+		newPatron.CardNumber, err = newCardNumber()
+		if err != nil {
+			return nil, err
+		}
+
+		var patron *entity.Patron
+		patron, err = l.patronRepository.RegisterPatron(ctx, newPatron)
+		if !errors.Is(err, entity.ErrCardNumberAlreadyExists) {
+			return patron, err
+		}
+	}
+
+	return nil, err
+}
+
+func (l *libraryImpl) GetPatron(
+	ctx context.Context,
+	patronID string,
+) (*entity.Patron, error) {
+	return l.patronRepository.GetPatron(ctx, patronID)
+}
+
+func (l *libraryImpl) GetPatronByCardNumber(
+	ctx context.Context,
+	cardNumber string,
+) (*entity.Patron, error) {
+	cardNumber, err := normalizeCardNumber(cardNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.patronRepository.GetPatronByCardNumber(ctx, cardNumber)
+}
+
+func (l *libraryImpl) UpdatePatron(
+	ctx context.Context,
+	patronID string,
+	update entity.PatronUpdate,
+) (*entity.Patron, error) {
+	if update.Name != nil {
+		name, err := normalizePatronName(*update.Name)
+		if err != nil {
+			return nil, err
+		}
+		update.Name = &name
+	}
+
+	if update.Email != nil {
+		email := normalizeEmail(*update.Email)
+		update.Email = &email
+	}
+
+	if update.Phone != nil {
+		phone := strings.TrimSpace(*update.Phone)
+		update.Phone = &phone
+	}
+
+	if update.Address != nil {
+		address := strings.TrimSpace(*update.Address)
+		update.Address = &address
+	}
+
+	return l.patronRepository.UpdatePatron(ctx, patronID, update)
+}
+
+func (l *libraryImpl) BlockPatron(
+	ctx context.Context,
+	patronID string,
+	reason string,
+) (*entity.Patron, error) {
+	return l.patronRepository.SetPatronStatus(ctx, patronID,
+		entity.PatronStatusBlocked, strings.TrimSpace(reason))
+}
+
+func (l *libraryImpl) UnblockPatron(
+	ctx context.Context,
+	patronID string,
+) (*entity.Patron, error) {
+	return l.patronRepository.SetPatronStatus(ctx, patronID, entity.PatronStatusActive, "")
+}
+
+// today is the current UTC date, comparable with DATE columns.
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+func normalizePatronName(name string) (string, error) {
+	name = strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+	if name == "" {
+		return "", entity.ErrInvalidPatronName
+	}
+
+	return name, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,56 @@
+package library
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/project/library/internal/entity"
+)
+
+func (l *libraryImpl) SearchCatalog(
+	ctx context.Context,
+	filter entity.SearchFilter,
+	pageSize int,
+	pageToken string,
+) ([]*entity.SearchResult, string, error) {
+	filter.Query = strings.Join(strings.Fields(norm.NFC.String(filter.Query)), " ")
+	if filter.Query == "" {
+		return nil, "", entity.ErrEmptySearchQuery
+	}
+
+	offset := 0
+	if pageToken != "" {
+		var err error
+		offset, err = decodeSearchOffset(pageToken, filter)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	limit := normalizePageSize(pageSize)
+
+	// One extra row tells whether there is a next page.
+	results, err := l.booksRepository.SearchCatalog(ctx, filter, offset, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+
+	results = results[:limit]
+
+	nextPageToken, err := encodePageToken(searchPageToken{
+		Query:   filter.Query,
+		GenreID: filter.GenreID,
+		Offset:  offset + limit,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return results, nextPageToken, nil
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -12,6 +14,7 @@ import (
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/project/library/internal/entity"
 	"github.com/project/library/internal/usecase/library"
@@ -70,8 +73,11 @@ func TestRegisterAuthor(t *testing.T) {
 			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
 			mockTransactor := mocks.NewMockTransactor(ctrl)
 			logger, _ := zap.NewProduction()
-			useCase := library.New(logger, mockAuthorRepo,
-				nil, mockOutboxRepo, mockTransactor)
+			useCase := library.New(logger, library.Repositories{
+				Author:     mockAuthorRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
 			ctx := t.Context()
 
 			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
@@ -87,6 +93,7 @@ func TestRegisterAuthor(t *testing.T) {
 					ctx,
 					idempotencyKey,
 					repository.OutboxKindAuthor,
+					tt.repositoryRerunAuthor.ID,
 					serialized,
 					gomock.Any(),
 				).Return(tt.outboxErr)
@@ -96,11 +103,9 @@ func TestRegisterAuthor(t *testing.T) {
 				err          error
 			)
 
-			if tt.repositoryRerunAuthor == nil {
-				resultAuthor, err = useCase.RegisterAuthor(ctx, defaultAuthor.Name)
-			} else {
-				resultAuthor, err = useCase.RegisterAuthor(ctx, tt.repositoryRerunAuthor.Name)
-			}
+			resultAuthor, err = useCase.RegisterAuthor(ctx, &entity.Author{
+				Name: defaultAuthor.Name,
+			})
 
 			switch {
 			case tt.outboxErr == nil && tt.repositoryErr == nil:
@@ -116,6 +121,143 @@ func TestRegisterAuthor(t *testing.T) {
 	}
 }
 
+func TestRegisterAuthorNormalization(t *testing.T) {
+	t.Parallel()
+
+	birthDate := time.Date(1927, time.March, 6, 0, 0, 0, 0, time.UTC)
+	deathDate := time.Date(2014, time.April, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		author             *entity.Author
+		wantName           string
+		wantAlternateNames []string
+		wantErr            error
+	}{
+		{
+			name:     "accented latin name",
+			author:   &entity.Author{Name: "Gabriel García Márquez"},
+			wantName: "Gabriel García Márquez",
+		},
+		{
+			name:     "decomposed accents are composed",
+			author:   &entity.Author{Name: "Gabriel Garci\u0301a Ma\u0301rquez"},
+			wantName: "Gabriel García Márquez",
+		},
+		{
+			name:     "whitespace is trimmed and collapsed",
+			author:   &entity.Author{Name: "  Jean-Paul \u00a0 Sartre\t"},
+			wantName: "Jean-Paul Sartre",
+		},
+		{
+			name:     "apostrophe",
+			author:   &entity.Author{Name: "Flann O'Brien"},
+			wantName: "Flann O'Brien",
+		},
+		{
+			name:     "initials",
+			author:   &entity.Author{Name: "J. R. R. Tolkien"},
+			wantName: "J. R. R. Tolkien",
+		},
+		{
+			name: "cyrillic name with alternate names",
+			author: &entity.Author{
+				Name:           "Фёдор Достоевский",
+				AlternateNames: []string{" Fyodor Dostoevsky", "Fyodor  Dostoevsky", "Ф. М. Достоевский"},
+			},
+			wantName:           "Фёдор Достоевский",
+			wantAlternateNames: []string{"Fyodor Dostoevsky", "Ф. М. Достоевский"},
+		},
+		{
+			name: "lifespan",
+			author: &entity.Author{
+				Name:      "Gabriel García Márquez",
+				BirthDate: &birthDate,
+				DeathDate: &deathDate,
+			},
+			wantName: "Gabriel García Márquez",
+		},
+		{
+			name:    "blank name",
+			author:  &entity.Author{Name: " \t "},
+			wantErr: entity.ErrInvalidAuthorName,
+		},
+		{
+			name:    "markup in name",
+			author:  &entity.Author{Name: "<b>Name</b>"},
+			wantErr: entity.ErrInvalidAuthorName,
+		},
+		{
+			name:    "punctuation only word",
+			author:  &entity.Author{Name: "Name -"},
+			wantErr: entity.ErrInvalidAuthorName,
+		},
+		{
+			name: "invalid alternate name",
+			author: &entity.Author{
+				Name:           "Name",
+				AlternateNames: []string{"Name!"},
+			},
+			wantErr: entity.ErrInvalidAuthorName,
+		},
+		{
+			name: "death before birth",
+			author: &entity.Author{
+				Name:      "Name",
+				BirthDate: &deathDate,
+				DeathDate: &birthDate,
+			},
+			wantErr: entity.ErrInvalidAuthorDates,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Author:     mockAuthorRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			if tt.wantErr == nil {
+				mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				mockAuthorRepo.EXPECT().RegisterAuthor(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, author *entity.Author) (*entity.Author, error) {
+						author.ID = uuid.NewString()
+						return author, nil
+					})
+				mockOutboxRepo.EXPECT().SendMessage(ctx, gomock.Any(),
+					repository.OutboxKindAuthor, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			}
+
+			author, err := useCase.RegisterAuthor(ctx, tt.author)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, author.Name)
+			if tt.wantAlternateNames != nil {
+				assert.Equal(t, tt.wantAlternateNames, author.AlternateNames)
+			}
+		})
+	}
+}
+
 func TestGetAuthorInfo(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
@@ -145,8 +287,7 @@ func TestGetAuthorInfo(t *testing.T) {
 
 			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
 			logger, _ := zap.NewProduction()
-			useCase := library.New(logger, mockAuthorRepo,
-				nil, nil, nil)
+			useCase := library.New(logger, library.Repositories{Author: mockAuthorRepo})
 			ctx := t.Context()
 
 			mockAuthorRepo.EXPECT().GetAuthorInfo(ctx, tt.repositoryRerunAuthor.ID).Return(tt.repositoryRerunAuthor, tt.wantErr)
@@ -160,41 +301,147 @@ func TestGetAuthorInfo(t *testing.T) {
 
 func TestChangeAuthor(t *testing.T) {
 	t.Parallel()
-	ctrl := gomock.NewController(t)
-	t.Cleanup(ctrl.Finish)
+
+	birthDate := time.Date(1927, time.March, 6, 0, 0, 0, 0, time.UTC)
+	earlierDate := birthDate.AddDate(-1, 0, 0)
+
+	before := &entity.Author{
+		ID:        uuid.NewString(),
+		Name:      "old name",
+		BirthDate: &birthDate,
+		Version:   1,
+	}
+	after := &entity.Author{
+		ID:      before.ID,
+		Name:    "new name",
+		Version: 2,
+	}
+	serialized, _ := json.Marshal(entity.AuthorChange{Before: before, After: after})
+	idempotencyKey := repository.OutboxKindAuthorUpdated.String() + "_" + after.ID + "_2"
 
 	tests := []struct {
-		name                  string
-		repositoryRerunAuthor *entity.Author
-		wantErr               error
-		wantErrCode           codes.Code
+		name            string
+		update          *entity.AuthorUpdate
+		expectedVersion int64
+		getErr          error
+		updateErr       error
+		outboxErr       error
+		want            *entity.Author
+		wantErr         error
 	}{
 		{
-			name:                  "change author",
-			repositoryRerunAuthor: defaultAuthor,
+			name: "change author",
+			want: after,
 		},
 		{
-			name:                  "change author | with error",
-			repositoryRerunAuthor: defaultAuthor,
-			wantErr:               entity.ErrAuthorNotFound,
-			wantErrCode:           codes.NotFound,
+			name: "change author | name is normalized",
+			update: &entity.AuthorUpdate{
+				Name: proto.String("  new   name "),
+			},
+			want: after,
+		},
+		{
+			name: "change author | invalid name",
+			update: &entity.AuthorUpdate{
+				Name: proto.String("new name?"),
+			},
+			wantErr: entity.ErrInvalidAuthorName,
+		},
+		{
+			name: "change author | death before birth",
+			update: &entity.AuthorUpdate{
+				DeathDate:    &earlierDate,
+				SetDeathDate: true,
+			},
+			wantErr: entity.ErrInvalidAuthorDates,
+		},
+		{
+			name:            "change author | expected version",
+			expectedVersion: 1,
+			want:            after,
+		},
+		{
+			name:    "change author | not found",
+			getErr:  entity.ErrAuthorNotFound,
+			wantErr: entity.ErrAuthorNotFound,
+		},
+		{
+			name:            "change author | version mismatch",
+			expectedVersion: 5,
+			updateErr:       entity.ErrVersionMismatch,
+			wantErr:         entity.ErrVersionMismatch,
+		},
+		{
+			name:      "change author | outbox error",
+			outboxErr: errors.New("outbox error"),
+			wantErr:   errors.New("outbox error"),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
 
 			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
 			logger, _ := zap.NewProduction()
-			useCase := library.New(logger, mockAuthorRepo,
-				nil, nil, nil)
+			useCase := library.New(logger, library.Repositories{
+				Author:     mockAuthorRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
 			ctx := t.Context()
 
-			mockAuthorRepo.EXPECT().ChangeAuthor(ctx, tt.repositoryRerunAuthor.ID, tt.repositoryRerunAuthor.Name).Return(tt.wantErr)
+			update := entity.AuthorUpdate{Name: proto.String(after.Name)}
+			if tt.update != nil {
+				update = *tt.update
+			}
 
-			wantErr := useCase.ChangeAuthor(ctx, tt.repositoryRerunAuthor.ID, tt.repositoryRerunAuthor.Name)
-			CheckError(t, wantErr, tt.wantErrCode)
+			if errors.Is(tt.wantErr, entity.ErrInvalidAuthorName) {
+				_, err := useCase.ChangeAuthor(ctx, before.ID, update, tt.expectedVersion)
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+
+			switch {
+			case errors.Is(tt.wantErr, entity.ErrInvalidAuthorDates):
+				mockAuthorRepo.EXPECT().GetAuthorForUpdate(ctx, before.ID).
+					Return(before, nil)
+			case tt.getErr != nil:
+				mockAuthorRepo.EXPECT().GetAuthorForUpdate(ctx, before.ID).
+					Return(nil, tt.getErr)
+			case tt.updateErr != nil:
+				mockAuthorRepo.EXPECT().GetAuthorForUpdate(ctx, before.ID).
+					Return(before, nil)
+				mockAuthorRepo.EXPECT().ChangeAuthor(ctx, before.ID,
+					entity.AuthorUpdate{Name: proto.String(after.Name)}, tt.expectedVersion).
+					Return(nil, tt.updateErr)
+			default:
+				mockAuthorRepo.EXPECT().GetAuthorForUpdate(ctx, before.ID).
+					Return(before, nil)
+				mockAuthorRepo.EXPECT().ChangeAuthor(ctx, before.ID,
+					entity.AuthorUpdate{Name: proto.String(after.Name)}, tt.expectedVersion).
+					Return(after, nil)
+				mockOutboxRepo.EXPECT().SendMessage(ctx, idempotencyKey,
+					repository.OutboxKindAuthorUpdated, gomock.Any(), serialized, gomock.Any()).
+					Return(tt.outboxErr)
+			}
+
+			got, err := useCase.ChangeAuthor(ctx, before.ID, update, tt.expectedVersion)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
@@ -233,15 +480,287 @@ func TestGetAuthorBooks(t *testing.T) {
 
 			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
 			logger, _ := zap.NewProduction()
-			useCase := library.New(logger, mockAuthorRepo,
-				nil, nil, nil)
+			useCase := library.New(logger, library.Repositories{Author: mockAuthorRepo})
 			ctx := t.Context()
 
-			mockAuthorRepo.EXPECT().GetAuthorBooks(ctx, tt.repositoryRerunAuthor.ID).Return(tt.returnBooks, tt.wantErr)
+			mockAuthorRepo.EXPECT().GetAuthorBooks(ctx, tt.repositoryRerunAuthor.ID, false).Return(tt.returnBooks, tt.wantErr)
 
-			books, wantErr := useCase.GetAuthorBooks(ctx, tt.repositoryRerunAuthor.ID)
+			books, wantErr := useCase.GetAuthorBooks(ctx, tt.repositoryRerunAuthor.ID, false)
 			CheckError(t, wantErr, tt.wantErrCode)
 			assert.Equal(t, tt.returnBooks, books)
 		})
 	}
 }
+
+func TestListAuthors(t *testing.T) {
+	t.Parallel()
+
+	authors := []*entity.Author{
+		{ID: uuid.NewString(), Name: "Anna Akhmatova", BookCount: 4},
+		{ID: uuid.NewString(), Name: "anton Chekhov", BookCount: 0},
+		{ID: uuid.NewString(), Name: "Ayn Rand", BookCount: 2},
+	}
+	filter := entity.AuthorsFilter{NameQuery: "a", NameMatch: entity.NameMatchPrefix}
+
+	t.Run("paginates with opaque cursor", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Author: mockAuthorRepo})
+		ctx := t.Context()
+
+		mockAuthorRepo.EXPECT().ListAuthors(ctx, filter, nil, 3).
+			Return(authors, nil)
+
+		page, nextPageToken, err := useCase.ListAuthors(ctx, filter, 2, "")
+		require.NoError(t, err)
+		assert.Equal(t, authors[:2], page)
+		require.NotEmpty(t, nextPageToken)
+
+		mockAuthorRepo.EXPECT().ListAuthors(ctx, filter, &entity.AuthorCursor{
+			Name: authors[1].Name,
+			ID:   authors[1].ID,
+		}, 3).Return(authors[2:], nil)
+
+		page, nextPageToken, err = useCase.ListAuthors(ctx, filter, 2, nextPageToken)
+		require.NoError(t, err)
+		assert.Equal(t, authors[2:], page)
+		assert.Empty(t, nextPageToken)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Author: mockAuthorRepo})
+		ctx := t.Context()
+
+		mockAuthorRepo.EXPECT().ListAuthors(ctx, filter, nil, 51).
+			Return(nil, errors.New("error list authors"))
+
+		_, _, err := useCase.ListAuthors(ctx, filter, 0, "")
+		require.Error(t, err)
+	})
+
+	t.Run("invalid page token", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Author: mockAuthorRepo})
+		ctx := t.Context()
+
+		_, _, err := useCase.ListAuthors(ctx, filter, 10, "e30")
+		require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+	})
+
+	t.Run("page token of another name filter", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Author: mockAuthorRepo})
+		ctx := t.Context()
+
+		mockAuthorRepo.EXPECT().ListAuthors(ctx, filter, nil, 3).
+			Return(authors, nil)
+
+		_, nextPageToken, err := useCase.ListAuthors(ctx, filter, 2, "")
+		require.NoError(t, err)
+
+		_, _, err = useCase.ListAuthors(ctx, entity.AuthorsFilter{NameQuery: "b"}, 2, nextPageToken)
+		require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+
+		substring := entity.AuthorsFilter{NameQuery: "a", NameMatch: entity.NameMatchSubstring}
+		_, _, err = useCase.ListAuthors(ctx, substring, 2, nextPageToken)
+		require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+	})
+}
+
+func TestDeleteAuthor(t *testing.T) {
+	t.Parallel()
+
+	deletedAt := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+	author := &entity.Author{
+		ID:        uuid.NewString(),
+		Name:      "name",
+		DeletedAt: &deletedAt,
+		Version:   2,
+	}
+	serialized, _ := json.Marshal(author)
+	softDeleteKey := repository.OutboxKindAuthorDeleted.String() + "_" + author.ID +
+		"_" + strconv.FormatInt(author.Version, 10)
+	purgeKey := repository.OutboxKindAuthorPurged.String() + "_" + author.ID
+
+	tests := []struct {
+		name           string
+		purge          bool
+		wantKind       repository.OutboxKind
+		idempotencyKey string
+		repositoryErr  error
+		outboxErr      error
+	}{
+		{
+			name:           "soft delete author",
+			wantKind:       repository.OutboxKindAuthorDeleted,
+			idempotencyKey: softDeleteKey,
+		},
+		{
+			name:           "purge author",
+			purge:          true,
+			wantKind:       repository.OutboxKindAuthorPurged,
+			idempotencyKey: purgeKey,
+		},
+		{
+			name:          "purge author | not found",
+			purge:         true,
+			repositoryErr: entity.ErrAuthorNotFound,
+		},
+		{
+			name:           "purge author | outbox error",
+			purge:          true,
+			wantKind:       repository.OutboxKindAuthorPurged,
+			idempotencyKey: purgeKey,
+			outboxErr:      errors.New("outbox error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Author:     mockAuthorRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+
+			returnAuthor := author
+			if tt.repositoryErr != nil {
+				returnAuthor = nil
+			}
+			if tt.purge {
+				mockAuthorRepo.EXPECT().PurgeAuthor(ctx, author.ID).
+					Return(returnAuthor, tt.repositoryErr)
+			} else {
+				mockAuthorRepo.EXPECT().SoftDeleteAuthor(ctx, author.ID).
+					Return(returnAuthor, tt.repositoryErr)
+			}
+
+			if tt.repositoryErr == nil {
+				mockOutboxRepo.EXPECT().SendMessage(ctx, tt.idempotencyKey,
+					tt.wantKind, gomock.Any(), serialized, gomock.Any()).
+					Return(tt.outboxErr)
+			}
+
+			err := useCase.DeleteAuthor(ctx, author.ID, tt.purge)
+			switch {
+			case tt.repositoryErr != nil:
+				require.ErrorIs(t, err, tt.repositoryErr)
+			case tt.outboxErr != nil:
+				require.ErrorIs(t, err, tt.outboxErr)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUndeleteAuthor(t *testing.T) {
+	t.Parallel()
+
+	author := &entity.Author{
+		ID:      uuid.NewString(),
+		Name:    "name",
+		Version: 5,
+	}
+
+	tests := []struct {
+		name          string
+		repositoryErr error
+		outboxErr     error
+		want          *entity.Author
+	}{
+		{
+			name: "undelete author",
+			want: author,
+		},
+		{
+			name:          "undelete author | not found",
+			repositoryErr: entity.ErrAuthorNotFound,
+		},
+		{
+			name:      "undelete author | outbox error",
+			outboxErr: errors.New("outbox error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Author:     mockAuthorRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+
+			if tt.repositoryErr != nil {
+				mockAuthorRepo.EXPECT().RestoreAuthor(ctx, author.ID).
+					Return(nil, tt.repositoryErr)
+			} else {
+				mockAuthorRepo.EXPECT().RestoreAuthor(ctx, author.ID).
+					Return(author, nil)
+				mockOutboxRepo.EXPECT().SendMessage(ctx,
+					repository.OutboxKindAuthorRestored.String()+"_"+author.ID+"_5",
+					repository.OutboxKindAuthorRestored, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(tt.outboxErr)
+			}
+
+			got, err := useCase.UndeleteAuthor(ctx, author.ID)
+			switch {
+			case tt.repositoryErr != nil:
+				require.ErrorIs(t, err, tt.repositoryErr)
+			case tt.outboxErr != nil:
+				require.ErrorIs(t, err, tt.outboxErr)
+			default:
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
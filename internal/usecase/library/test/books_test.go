@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -71,8 +73,11 @@ func TestAddBook(t *testing.T) {
 			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
 			mockTransactor := mocks.NewMockTransactor(ctrl)
 			logger, _ := zap.NewProduction()
-			useCase := library.New(logger, nil,
-				mockBooksRepo, mockOutboxRepo, mockTransactor)
+			useCase := library.New(logger, library.Repositories{
+				Books:      mockBooksRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
 			ctx := t.Context()
 
 			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
@@ -88,12 +93,16 @@ func TestAddBook(t *testing.T) {
 					ctx,
 					idempotencyKey,
 					repository.OutboxKindBook,
+					tt.repositoryRerunBook.ID,
 					serialized,
 					gomock.Any(),
 				).Return(tt.outboxErr)
 			}
 
-			resultBook, err := useCase.AddBook(ctx, book.Name, book.AuthorIDs)
+			resultBook, err := useCase.AddBook(ctx, &entity.Book{
+				Name:      book.Name,
+				AuthorIDs: book.AuthorIDs,
+			})
 			switch {
 			case tt.outboxErr == nil && tt.repositoryErr == nil:
 				require.NoError(t, err)
@@ -108,6 +117,163 @@ func TestAddBook(t *testing.T) {
 	}
 }
 
+func TestAddBookISBN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		isbn     string
+		wantISBN string
+		wantErr  error
+	}{
+		{
+			name:     "isbn-10 is normalized to isbn-13",
+			isbn:     "0-306-40615-2",
+			wantISBN: "9780306406157",
+		},
+		{
+			name:     "isbn-10 with X check digit",
+			isbn:     "0-8044-2957-x",
+			wantISBN: "9780804429573",
+		},
+		{
+			name:     "isbn-13 with separators",
+			isbn:     "978 0 306 40615 7",
+			wantISBN: "9780306406157",
+		},
+		{
+			name:    "isbn-10 with bad checksum",
+			isbn:    "0-306-40615-3",
+			wantErr: entity.ErrInvalidISBN,
+		},
+		{
+			name:    "isbn-13 with bad checksum",
+			isbn:    "9780306406158",
+			wantErr: entity.ErrInvalidISBN,
+		},
+		{
+			name:    "isbn-13 with unknown prefix",
+			isbn:    "9770306406152",
+			wantErr: entity.ErrInvalidISBN,
+		},
+		{
+			name:    "wrong length",
+			isbn:    "978030640615",
+			wantErr: entity.ErrInvalidISBN,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Books:      mockBooksRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			if tt.wantErr == nil {
+				mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					},
+				)
+				mockBooksRepo.EXPECT().AddBook(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, book *entity.Book) (*entity.Book, error) {
+						book.ID = uuid.NewString()
+						return book, nil
+					},
+				)
+				mockOutboxRepo.EXPECT().SendMessage(ctx, gomock.Any(),
+					repository.OutboxKindBook, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			}
+
+			book, err := useCase.AddBook(ctx, &entity.Book{
+				Name: "book",
+				ISBN: tt.isbn,
+			})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantISBN, book.ISBN)
+		})
+	}
+}
+
+func TestGetBookByISBN(t *testing.T) {
+	t.Parallel()
+
+	book := &entity.Book{
+		ID:   uuid.NewString(),
+		Name: "book",
+		ISBN: "9780306406157",
+	}
+
+	tests := []struct {
+		name          string
+		isbn          string
+		repositoryErr error
+		want          *entity.Book
+		wantErrCode   codes.Code
+	}{
+		{
+			name: "get book by isbn-10",
+			isbn: "0306406152",
+			want: book,
+		},
+		{
+			name: "get book by isbn-13",
+			isbn: "978-0-306-40615-7",
+			want: book,
+		},
+		{
+			name:          "get book by isbn | not found",
+			isbn:          "9780306406157",
+			repositoryErr: entity.ErrBookNotFound,
+			wantErrCode:   codes.NotFound,
+		},
+		{
+			name:        "get book by isbn | invalid isbn",
+			isbn:        "0306406153",
+			wantErrCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{Books: mockBooksRepo})
+			ctx := t.Context()
+
+			if tt.wantErrCode != codes.InvalidArgument {
+				mockBooksRepo.EXPECT().GetBookByISBN(ctx, book.ISBN).
+					Return(tt.want, tt.repositoryErr)
+			}
+
+			got, err := useCase.GetBookByISBN(ctx, tt.isbn)
+			CheckError(t, err, tt.wantErrCode)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestGetBook(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
@@ -145,14 +311,13 @@ func TestGetBook(t *testing.T) {
 
 			mockBookRepo := mocks.NewMockBooksRepository(ctrl)
 			logger, _ := zap.NewProduction()
-			useCase := library.New(logger, nil,
-				mockBookRepo, nil, nil)
+			useCase := library.New(logger, library.Repositories{Books: mockBookRepo})
 			ctx := t.Context()
 
-			mockBookRepo.EXPECT().GetBook(ctx, tt.returnBook.ID).
+			mockBookRepo.EXPECT().GetBook(ctx, tt.returnBook.ID, false).
 				Return(tt.returnBook, tt.wantErr)
 
-			got, err := useCase.GetBook(ctx, tt.returnBook.ID)
+			got, err := useCase.GetBook(ctx, tt.returnBook.ID, false)
 			CheckError(t, err, tt.wantErrCode)
 			assert.Equal(t, tt.returnBook, got)
 		})
@@ -161,52 +326,442 @@ func TestGetBook(t *testing.T) {
 
 func TestUpdateBook(t *testing.T) {
 	t.Parallel()
-	ctrl := gomock.NewController(t)
-	t.Cleanup(ctrl.Finish)
+
+	before := &entity.Book{
+		ID:        uuid.NewString(),
+		Name:      "old name",
+		AuthorIDs: []string{uuid.NewString()},
+		Version:   3,
+	}
+	after := &entity.Book{
+		ID:        before.ID,
+		Name:      "new name",
+		AuthorIDs: []string{uuid.NewString()},
+		Version:   4,
+	}
+	update := entity.BookUpdate{
+		Name:           &after.Name,
+		AuthorIDs:      after.AuthorIDs,
+		ReplaceAuthors: true,
+	}
+	serialized, _ := json.Marshal(entity.BookChange{Before: before, After: after})
+	idempotencyKey := repository.OutboxKindBookUpdated.String() + "_" + after.ID + "_4"
 
 	tests := []struct {
-		name        string
-		returnBook  *entity.Book
-		wantErr     error
-		wantErrCode codes.Code
+		name            string
+		expectedVersion int64
+		getErr          error
+		updateErr       error
+		outboxErr       error
+		want            *entity.Book
+		wantErr         error
 	}{
 		{
 			name: "update book",
-			returnBook: &entity.Book{
-				ID:        uuid.NewString(),
-				Name:      "name",
-				AuthorIDs: make([]string, 0),
-			},
+			want: after,
 		},
 		{
-			name: "update book | with error",
-			returnBook: &entity.Book{
-				ID:        uuid.NewString(),
-				Name:      "name",
-				AuthorIDs: make([]string, 0),
-			},
-			wantErrCode: codes.NotFound,
-			wantErr:     entity.ErrBookNotFound,
+			name:            "update book | expected version",
+			expectedVersion: 3,
+			want:            after,
+		},
+		{
+			name:    "update book | not found",
+			getErr:  entity.ErrBookNotFound,
+			wantErr: entity.ErrBookNotFound,
+		},
+		{
+			name:            "update book | version mismatch",
+			expectedVersion: 2,
+			updateErr:       entity.ErrVersionMismatch,
+			wantErr:         entity.ErrVersionMismatch,
+		},
+		{
+			name:      "update book | outbox error",
+			outboxErr: errors.New("cannot send message"),
+			wantErr:   errors.New("cannot send message"),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
 
 			mockBookRepo := mocks.NewMockBooksRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
 			logger, _ := zap.NewProduction()
-			useCase := library.New(logger, nil,
-				mockBookRepo, nil, nil)
+			useCase := library.New(logger, library.Repositories{
+				Books:      mockBookRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
 			ctx := t.Context()
 
-			mockBookRepo.EXPECT().UpdateBook(ctx,
-				tt.returnBook.ID, tt.returnBook.Name, tt.returnBook.AuthorIDs).
-				Return(tt.wantErr)
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
 
-			err := useCase.UpdateBook(ctx,
-				tt.returnBook.ID, tt.returnBook.Name, tt.returnBook.AuthorIDs)
-			CheckError(t, err, tt.wantErrCode)
+			switch {
+			case tt.getErr != nil:
+				mockBookRepo.EXPECT().GetBookForUpdate(ctx, before.ID).
+					Return(nil, tt.getErr)
+			case tt.updateErr != nil:
+				mockBookRepo.EXPECT().GetBookForUpdate(ctx, before.ID).
+					Return(before, nil)
+				mockBookRepo.EXPECT().UpdateBook(ctx, before.ID, update, tt.expectedVersion).
+					Return(nil, tt.updateErr)
+			default:
+				mockBookRepo.EXPECT().GetBookForUpdate(ctx, before.ID).
+					Return(before, nil)
+				mockBookRepo.EXPECT().UpdateBook(ctx, before.ID, update, tt.expectedVersion).
+					Return(after, nil)
+				mockOutboxRepo.EXPECT().SendMessage(ctx, idempotencyKey,
+					repository.OutboxKindBookUpdated, gomock.Any(), serialized, gomock.Any()).
+					Return(tt.outboxErr)
+			}
+
+			got, err := useCase.UpdateBook(ctx, before.ID, update, tt.expectedVersion)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListBooks(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	books := []*entity.Book{
+		{ID: uuid.NewString(), Name: "first", CreatedAt: createdAt},
+		{ID: uuid.NewString(), Name: "second", CreatedAt: createdAt.Add(time.Second)},
+		{ID: uuid.NewString(), Name: "third", CreatedAt: createdAt.Add(2 * time.Second)},
+	}
+
+	t.Run("paginates with opaque cursor", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockBookRepo := mocks.NewMockBooksRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Books: mockBookRepo})
+		ctx := t.Context()
+		filter := entity.BooksFilter{NamePrefix: "t", SortOrder: entity.SortOrderDesc}
+
+		mockBookRepo.EXPECT().ListBooks(ctx, filter, nil, 3).
+			Return(books, nil)
+
+		page, nextPageToken, err := useCase.ListBooks(ctx, filter, 2, "")
+		require.NoError(t, err)
+		assert.Equal(t, books[:2], page)
+		require.NotEmpty(t, nextPageToken)
+
+		mockBookRepo.EXPECT().ListBooks(ctx, filter, &entity.BookCursor{
+			CreatedAt: books[1].CreatedAt,
+			ID:        books[1].ID,
+		}, 3).Return(books[2:], nil)
+
+		page, nextPageToken, err = useCase.ListBooks(ctx, filter, 2, nextPageToken)
+		require.NoError(t, err)
+		assert.Equal(t, books[2:], page)
+		assert.Empty(t, nextPageToken)
+	})
+
+	t.Run("default page size", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockBookRepo := mocks.NewMockBooksRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Books: mockBookRepo})
+		ctx := t.Context()
+
+		mockBookRepo.EXPECT().ListBooks(ctx, entity.BooksFilter{}, nil, 51).
+			Return(books, nil)
+
+		page, nextPageToken, err := useCase.ListBooks(ctx, entity.BooksFilter{}, 0, "")
+		require.NoError(t, err)
+		assert.Equal(t, books, page)
+		assert.Empty(t, nextPageToken)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockBookRepo := mocks.NewMockBooksRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Books: mockBookRepo})
+		ctx := t.Context()
+
+		mockBookRepo.EXPECT().ListBooks(ctx, entity.BooksFilter{}, nil, 11).
+			Return(nil, status.Error(codes.Internal, "error"))
+
+		_, _, err := useCase.ListBooks(ctx, entity.BooksFilter{}, 10, "")
+		CheckError(t, err, codes.Internal)
+	})
+
+	t.Run("invalid page token", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockBookRepo := mocks.NewMockBooksRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Books: mockBookRepo})
+		ctx := t.Context()
+
+		_, _, err := useCase.ListBooks(ctx, entity.BooksFilter{}, 10, "not a token")
+		require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+	})
+
+	t.Run("page token from another sort order", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockBookRepo := mocks.NewMockBooksRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Books: mockBookRepo})
+		ctx := t.Context()
+		ascFilter := entity.BooksFilter{SortOrder: entity.SortOrderAsc}
+
+		mockBookRepo.EXPECT().ListBooks(ctx, ascFilter, nil, 2).
+			Return(books[:2], nil)
+
+		_, nextPageToken, err := useCase.ListBooks(ctx, ascFilter, 1, "")
+		require.NoError(t, err)
+
+		descFilter := entity.BooksFilter{SortOrder: entity.SortOrderDesc}
+		_, _, err = useCase.ListBooks(ctx, descFilter, 1, nextPageToken)
+		require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+	})
+
+	t.Run("page token of another filter", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockBookRepo := mocks.NewMockBooksRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Books: mockBookRepo})
+		ctx := t.Context()
+		createdAfter := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+		filter := entity.BooksFilter{NamePrefix: "war", CreatedAfter: &createdAfter}
+
+		mockBookRepo.EXPECT().ListBooks(ctx, filter, nil, 2).
+			Return(books[:2], nil)
+
+		_, nextPageToken, err := useCase.ListBooks(ctx, filter, 1, "")
+		require.NoError(t, err)
+
+		// The same instant in another time zone is the same filter.
+		sameAfter := createdAfter.In(time.FixedZone("MSK", 3*60*60))
+		sameFilter := entity.BooksFilter{NamePrefix: "war", CreatedAfter: &sameAfter}
+		mockBookRepo.EXPECT().ListBooks(ctx, sameFilter, gomock.Any(), 2).
+			Return(books[1:2], nil)
+
+		_, _, err = useCase.ListBooks(ctx, sameFilter, 1, nextPageToken)
+		require.NoError(t, err)
+
+		_, _, err = useCase.ListBooks(ctx, entity.BooksFilter{NamePrefix: "peace", CreatedAfter: &createdAfter},
+			1, nextPageToken)
+		require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+
+		_, _, err = useCase.ListBooks(ctx, entity.BooksFilter{NamePrefix: "war"}, 1, nextPageToken)
+		require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+	})
+}
+
+func TestDeleteBook(t *testing.T) {
+	t.Parallel()
+
+	deletedAt := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+	book := &entity.Book{
+		ID:        uuid.NewString(),
+		Name:      "Test Book",
+		AuthorIDs: []string{uuid.NewString()},
+		DeletedAt: &deletedAt,
+		Version:   2,
+	}
+	serialized, _ := json.Marshal(book)
+	softDeleteKey := repository.OutboxKindBookDeleted.String() + "_" + book.ID +
+		"_" + strconv.FormatInt(book.Version, 10)
+	purgeKey := repository.OutboxKindBookPurged.String() + "_" + book.ID
+
+	tests := []struct {
+		name           string
+		purge          bool
+		wantKind       repository.OutboxKind
+		idempotencyKey string
+		repositoryErr  error
+		outboxErr      error
+	}{
+		{
+			name:           "soft delete book",
+			wantKind:       repository.OutboxKindBookDeleted,
+			idempotencyKey: softDeleteKey,
+		},
+		{
+			name:           "purge book",
+			purge:          true,
+			wantKind:       repository.OutboxKindBookPurged,
+			idempotencyKey: purgeKey,
+		},
+		{
+			name:          "delete book | not found",
+			repositoryErr: entity.ErrBookNotFound,
+		},
+		{
+			name:           "delete book | outbox error",
+			wantKind:       repository.OutboxKindBookDeleted,
+			idempotencyKey: softDeleteKey,
+			outboxErr:      errors.New("cannot send message"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Books:      mockBooksRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+
+			returnBook := book
+			if tt.repositoryErr != nil {
+				returnBook = nil
+			}
+			if tt.purge {
+				mockBooksRepo.EXPECT().PurgeBook(ctx, book.ID).
+					Return(returnBook, tt.repositoryErr)
+			} else {
+				mockBooksRepo.EXPECT().SoftDeleteBook(ctx, book.ID).
+					Return(returnBook, tt.repositoryErr)
+			}
+
+			if tt.repositoryErr == nil {
+				mockOutboxRepo.EXPECT().SendMessage(ctx, tt.idempotencyKey,
+					tt.wantKind, gomock.Any(), serialized, gomock.Any()).
+					Return(tt.outboxErr)
+			}
+
+			err := useCase.DeleteBook(ctx, book.ID, tt.purge)
+			switch {
+			case tt.repositoryErr != nil:
+				require.ErrorIs(t, err, tt.repositoryErr)
+			case tt.outboxErr != nil:
+				require.ErrorIs(t, err, tt.outboxErr)
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUndeleteBook(t *testing.T) {
+	t.Parallel()
+
+	book := &entity.Book{
+		ID:        uuid.NewString(),
+		Name:      "Test Book",
+		AuthorIDs: []string{uuid.NewString()},
+		Version:   3,
+	}
+	serialized, _ := json.Marshal(book)
+	idempotencyKey := repository.OutboxKindBookRestored.String() + "_" + book.ID +
+		"_" + strconv.FormatInt(book.Version, 10)
+
+	tests := []struct {
+		name          string
+		repositoryErr error
+		outboxErr     error
+		want          *entity.Book
+	}{
+		{
+			name: "undelete book",
+			want: book,
+		},
+		{
+			name:          "undelete book | not found",
+			repositoryErr: entity.ErrBookNotFound,
+		},
+		{
+			name:      "undelete book | outbox error",
+			outboxErr: errors.New("cannot send message"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Books:      mockBooksRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+
+			if tt.repositoryErr != nil {
+				mockBooksRepo.EXPECT().RestoreBook(ctx, book.ID).
+					Return(nil, tt.repositoryErr)
+			} else {
+				mockBooksRepo.EXPECT().RestoreBook(ctx, book.ID).
+					Return(book, nil)
+				mockOutboxRepo.EXPECT().SendMessage(ctx, idempotencyKey,
+					repository.OutboxKindBookRestored, gomock.Any(), serialized, gomock.Any()).
+					Return(tt.outboxErr)
+			}
+
+			got, err := useCase.UndeleteBook(ctx, book.ID)
+			switch {
+			case tt.repositoryErr != nil:
+				require.ErrorIs(t, err, tt.repositoryErr)
+			case tt.outboxErr != nil:
+				require.ErrorIs(t, err, tt.outboxErr)
+			default:
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
@@ -0,0 +1,545 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func TestAddCopy(t *testing.T) {
+	t.Parallel()
+
+	bookID := uuid.NewString()
+
+	tests := []struct {
+		name      string
+		copy      *entity.Copy
+		want      *entity.Copy
+		repoErr   error
+		outboxErr error
+		wantErr   error
+	}{
+		{
+			name: "add copy with defaults",
+			copy: &entity.Copy{
+				BookID:  bookID,
+				Barcode: "LIB-1",
+				Branch:  "  Central   Library ",
+			},
+			want: &entity.Copy{
+				BookID:    bookID,
+				Barcode:   "LIB-1",
+				Branch:    "Central Library",
+				Condition: entity.CopyConditionGood,
+				Status:    entity.CopyStatusAvailable,
+			},
+		},
+		{
+			name: "add copy in repair",
+			copy: &entity.Copy{
+				BookID:        bookID,
+				Barcode:       "LIB-2",
+				Branch:        "North",
+				ShelfLocation: " B-3 ",
+				Condition:     entity.CopyConditionPoor,
+				Status:        entity.CopyStatusInRepair,
+			},
+			want: &entity.Copy{
+				BookID:        bookID,
+				Barcode:       "LIB-2",
+				Branch:        "North",
+				ShelfLocation: "B-3",
+				Condition:     entity.CopyConditionPoor,
+				Status:        entity.CopyStatusInRepair,
+			},
+		},
+		{
+			name: "add copy | blank branch",
+			copy: &entity.Copy{
+				BookID:  bookID,
+				Barcode: "LIB-1",
+				Branch:  " ",
+			},
+			wantErr: entity.ErrInvalidCopyBranch,
+		},
+		{
+			name: "add copy | on loan",
+			copy: &entity.Copy{
+				BookID:  bookID,
+				Barcode: "LIB-1",
+				Branch:  "Central",
+				Status:  entity.CopyStatusOnLoan,
+			},
+			wantErr: entity.ErrCopyStatusNotSettable,
+		},
+		{
+			name: "add copy | barcode already exists",
+			copy: &entity.Copy{
+				BookID:  bookID,
+				Barcode: "LIB-1",
+				Branch:  "Central",
+			},
+			repoErr: entity.ErrBarcodeAlreadyExists,
+			wantErr: entity.ErrBarcodeAlreadyExists,
+		},
+		{
+			name: "add copy | outbox error",
+			copy: &entity.Copy{
+				BookID:  bookID,
+				Barcode: "LIB-1",
+				Branch:  "Central",
+			},
+			outboxErr: errors.New("outbox error"),
+			wantErr:   errors.New("outbox error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockCopyRepo := mocks.NewMockCopyRepository(ctrl)
+			mockHoldRepo := mocks.NewMockHoldRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Copy:       mockCopyRepo,
+				Hold:       mockHoldRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			if !errors.Is(tt.wantErr, entity.ErrInvalidCopyBranch) &&
+				!errors.Is(tt.wantErr, entity.ErrCopyStatusNotSettable) {
+				mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					},
+				)
+
+				available := tt.copy.Status == "" || tt.copy.Status == entity.CopyStatusAvailable
+				if available {
+					mockHoldRepo.EXPECT().LockHoldQueue(ctx, bookID).Return(nil)
+					if tt.repoErr == nil {
+						mockHoldRepo.EXPECT().GetNextWaitingHold(ctx, bookID).Return(nil, entity.ErrHoldNotFound)
+					}
+				}
+
+				mockCopyRepo.EXPECT().AddCopy(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, bookCopy *entity.Copy) (*entity.Copy, error) {
+						if tt.repoErr != nil {
+							return nil, tt.repoErr
+						}
+						bookCopy.ID = uuid.NewString()
+						return bookCopy, nil
+					},
+				)
+
+				if tt.repoErr == nil {
+					mockOutboxRepo.EXPECT().SendMessage(ctx, gomock.Any(),
+						repository.OutboxKindCopy, gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(tt.outboxErr)
+				}
+			}
+
+			got, err := useCase.AddCopy(ctx, tt.copy)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			tt.want.ID = got.ID
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdateCopy(t *testing.T) {
+	t.Parallel()
+
+	lost := entity.CopyStatusLost
+	before := &entity.Copy{
+		ID:        uuid.NewString(),
+		BookID:    uuid.NewString(),
+		Barcode:   "LIB-1",
+		Branch:    "Central",
+		Status:    entity.CopyStatusAvailable,
+		UpdatedAt: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+	}
+	after := &entity.Copy{
+		ID:        before.ID,
+		BookID:    before.BookID,
+		Barcode:   "LIB-1",
+		Branch:    "North",
+		Status:    entity.CopyStatusLost,
+		UpdatedAt: time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC),
+	}
+	branch := " North "
+	update := entity.CopyUpdate{Branch: &branch, Status: &lost}
+	wantUpdate := entity.CopyUpdate{Branch: &after.Branch, Status: &lost}
+	serialized, _ := json.Marshal(entity.CopyChange{Before: before, After: after})
+	idempotencyKey := repository.OutboxKindCopyUpdated.String() + "_" + after.ID + "_" +
+		strconv.FormatInt(after.UpdatedAt.UnixNano(), 10)
+
+	tests := []struct {
+		name      string
+		getErr    error
+		outboxErr error
+		want      *entity.Copy
+		wantErr   error
+	}{
+		{
+			name: "update copy",
+			want: after,
+		},
+		{
+			name:    "update copy | not found",
+			getErr:  entity.ErrCopyNotFound,
+			wantErr: entity.ErrCopyNotFound,
+		},
+		{
+			name:      "update copy | outbox error",
+			outboxErr: errors.New("cannot send message"),
+			wantErr:   errors.New("cannot send message"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockCopyRepo := mocks.NewMockCopyRepository(ctrl)
+			mockHoldRepo := mocks.NewMockHoldRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Copy:       mockCopyRepo,
+				Hold:       mockHoldRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+
+			if tt.getErr != nil {
+				mockCopyRepo.EXPECT().GetCopy(ctx, before.ID).
+					Return(nil, tt.getErr)
+			} else {
+				gomock.InOrder(
+					mockCopyRepo.EXPECT().GetCopy(ctx, before.ID).Return(before, nil),
+					mockHoldRepo.EXPECT().LockHoldQueue(ctx, before.BookID).Return(nil),
+					mockCopyRepo.EXPECT().GetCopyForUpdate(ctx, before.ID).Return(before, nil),
+				)
+				mockCopyRepo.EXPECT().UpdateCopy(ctx, before.ID, wantUpdate).
+					Return(after, nil)
+				mockOutboxRepo.EXPECT().SendMessage(ctx, idempotencyKey,
+					repository.OutboxKindCopyUpdated, after.BookID, serialized, gomock.Any()).
+					Return(tt.outboxErr)
+			}
+
+			got, err := useCase.UpdateCopy(ctx, before.ID, update)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdateCopyStatus(t *testing.T) {
+	t.Parallel()
+
+	copyID := uuid.NewString()
+	bookID := uuid.NewString()
+
+	tests := []struct {
+		name    string
+		current entity.CopyStatus
+		status  entity.CopyStatus
+		wantErr error
+	}{
+		{
+			name:    "update copy status | on loan",
+			current: entity.CopyStatusOnLoan,
+			status:  entity.CopyStatusLost,
+			wantErr: entity.ErrCopyInUse,
+		},
+		{
+			name:    "update copy status | on hold",
+			current: entity.CopyStatusOnHold,
+			status:  entity.CopyStatusAvailable,
+			wantErr: entity.ErrCopyInUse,
+		},
+		{
+			name:    "update copy status | to on loan",
+			status:  entity.CopyStatusOnLoan,
+			wantErr: entity.ErrCopyStatusNotSettable,
+		},
+		{
+			name:    "update copy status | to on hold",
+			status:  entity.CopyStatusOnHold,
+			wantErr: entity.ErrCopyStatusNotSettable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockCopyRepo := mocks.NewMockCopyRepository(ctrl)
+			mockHoldRepo := mocks.NewMockHoldRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Copy:       mockCopyRepo,
+				Hold:       mockHoldRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			if tt.current != "" {
+				current := &entity.Copy{ID: copyID, BookID: bookID, Status: tt.current}
+				mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					},
+				)
+				mockCopyRepo.EXPECT().GetCopy(ctx, copyID).Return(current, nil)
+				mockHoldRepo.EXPECT().LockHoldQueue(ctx, bookID).Return(nil)
+				mockCopyRepo.EXPECT().GetCopyForUpdate(ctx, copyID).Return(current, nil)
+			}
+
+			got, err := useCase.UpdateCopy(ctx, copyID, entity.CopyUpdate{Status: &tt.status})
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestCopyInCirculationGoesToWaitingHold(t *testing.T) {
+	t.Parallel()
+
+	bookID := uuid.NewString()
+	copyID := uuid.NewString()
+	waiting := &entity.Hold{ID: uuid.NewString(), BookID: bookID, Status: entity.HoldStatusWaiting}
+	onHold := entity.CopyStatusOnHold
+	setAside := &entity.Copy{ID: copyID, BookID: bookID, Barcode: "LIB-1", Status: entity.CopyStatusOnHold}
+
+	tests := []struct {
+		name  string
+		call  func(ctx context.Context, useCase library.CopyUseCase) (*entity.Copy, error)
+		setUp func(ctx context.Context, copyRepo *mocks.MockCopyRepository, outboxRepo *mocks.MockOutboxRepository)
+	}{
+		{
+			name: "add copy",
+			call: func(ctx context.Context, useCase library.CopyUseCase) (*entity.Copy, error) {
+				return useCase.AddCopy(ctx, &entity.Copy{BookID: bookID, Barcode: "LIB-1", Branch: "Central"})
+			},
+			setUp: func(ctx context.Context, copyRepo *mocks.MockCopyRepository, outboxRepo *mocks.MockOutboxRepository) {
+				copyRepo.EXPECT().AddCopy(ctx, gomock.Any()).
+					Return(&entity.Copy{ID: copyID, BookID: bookID, Barcode: "LIB-1", Status: entity.CopyStatusAvailable}, nil)
+				outboxRepo.EXPECT().SendMessage(ctx, gomock.Any(),
+					repository.OutboxKindCopy, bookID, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "update copy back from repair",
+			call: func(ctx context.Context, useCase library.CopyUseCase) (*entity.Copy, error) {
+				available := entity.CopyStatusAvailable
+				return useCase.UpdateCopy(ctx, copyID, entity.CopyUpdate{Status: &available})
+			},
+			setUp: func(ctx context.Context, copyRepo *mocks.MockCopyRepository, outboxRepo *mocks.MockOutboxRepository) {
+				inRepair := &entity.Copy{ID: copyID, BookID: bookID, Barcode: "LIB-1", Status: entity.CopyStatusInRepair}
+				available := entity.CopyStatusAvailable
+				copyRepo.EXPECT().GetCopy(ctx, copyID).Return(inRepair, nil)
+				copyRepo.EXPECT().GetCopyForUpdate(ctx, copyID).Return(inRepair, nil)
+				copyRepo.EXPECT().UpdateCopy(ctx, copyID, entity.CopyUpdate{Status: &available}).
+					Return(&entity.Copy{ID: copyID, BookID: bookID, Barcode: "LIB-1", Status: available}, nil)
+				outboxRepo.EXPECT().SendMessage(ctx, gomock.Any(),
+					repository.OutboxKindCopyUpdated, bookID, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockCopyRepo := mocks.NewMockCopyRepository(ctrl)
+			mockHoldRepo := mocks.NewMockHoldRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Copy:       mockCopyRepo,
+				Hold:       mockHoldRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+			tt.setUp(ctx, mockCopyRepo, mockOutboxRepo)
+
+			// The waiting patron gets the copy, with the hold queue locked.
+			lock := mockHoldRepo.EXPECT().LockHoldQueue(ctx, bookID).Return(nil)
+			gomock.InOrder(
+				lock,
+				mockHoldRepo.EXPECT().GetNextWaitingHold(ctx, bookID).Return(waiting, nil),
+				mockHoldRepo.EXPECT().MarkHoldReady(ctx, waiting.ID, copyID, gomock.Any()).
+					Return(&entity.Hold{ID: waiting.ID, BookID: bookID, CopyID: copyID,
+						Status: entity.HoldStatusReady}, nil),
+				mockCopyRepo.EXPECT().UpdateCopy(ctx, copyID, entity.CopyUpdate{Status: &onHold}).
+					Return(setAside, nil),
+				mockOutboxRepo.EXPECT().SendMessage(ctx, "hold_ready_"+waiting.ID,
+					repository.OutboxKindHoldReady, bookID, gomock.Any(), gomock.Any()).Return(nil),
+			)
+
+			got, err := tt.call(ctx, useCase)
+			require.NoError(t, err)
+			assert.Equal(t, setAside, got)
+		})
+	}
+}
+
+func TestDeleteCopy(t *testing.T) {
+	t.Parallel()
+
+	deleted := &entity.Copy{
+		ID:      uuid.NewString(),
+		BookID:  uuid.NewString(),
+		Barcode: "LIB-1",
+		Status:  entity.CopyStatusAvailable,
+	}
+	serialized, _ := json.Marshal(deleted)
+
+	tests := []struct {
+		name      string
+		status    entity.CopyStatus
+		getErr    error
+		deleteErr error
+		outboxErr error
+		wantErr   error
+	}{
+		{
+			name:   "delete copy",
+			status: entity.CopyStatusAvailable,
+		},
+		{
+			name:    "delete copy | not found",
+			getErr:  entity.ErrCopyNotFound,
+			wantErr: entity.ErrCopyNotFound,
+		},
+		{
+			name:    "delete copy | on loan",
+			status:  entity.CopyStatusOnLoan,
+			wantErr: entity.ErrCopyInUse,
+		},
+		{
+			name:    "delete copy | on hold",
+			status:  entity.CopyStatusOnHold,
+			wantErr: entity.ErrCopyInUse,
+		},
+		{
+			name:      "delete copy | was lent",
+			status:    entity.CopyStatusLost,
+			deleteErr: entity.ErrCopyHasLoans,
+			wantErr:   entity.ErrCopyHasLoans,
+		},
+		{
+			name:      "delete copy | outbox error",
+			status:    entity.CopyStatusInRepair,
+			outboxErr: errors.New("cannot send message"),
+			wantErr:   errors.New("cannot send message"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockCopyRepo := mocks.NewMockCopyRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Copy:       mockCopyRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+
+			if tt.getErr != nil {
+				mockCopyRepo.EXPECT().GetCopyForUpdate(ctx, deleted.ID).Return(nil, tt.getErr)
+			} else {
+				locked := *deleted
+				locked.Status = tt.status
+				mockCopyRepo.EXPECT().GetCopyForUpdate(ctx, deleted.ID).Return(&locked, nil)
+			}
+
+			switch {
+			case tt.getErr != nil || errors.Is(tt.wantErr, entity.ErrCopyInUse):
+			case tt.deleteErr != nil:
+				mockCopyRepo.EXPECT().DeleteCopy(ctx, deleted.ID).Return(nil, tt.deleteErr)
+			default:
+				mockCopyRepo.EXPECT().DeleteCopy(ctx, deleted.ID).Return(deleted, nil)
+				mockOutboxRepo.EXPECT().SendMessage(ctx,
+					repository.OutboxKindCopyDeleted.String()+"_"+deleted.ID,
+					repository.OutboxKindCopyDeleted, deleted.BookID, serialized, gomock.Any()).
+					Return(tt.outboxErr)
+			}
+
+			err := useCase.DeleteCopy(ctx, deleted.ID)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
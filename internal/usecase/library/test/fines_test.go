@@ -0,0 +1,327 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func TestFinePolicyAmount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		policy      entity.FinePolicy
+		daysOverdue int
+		want        int64
+	}{
+		{
+			name:        "within grace period",
+			policy:      entity.FinePolicy{DailyRate: 25, MaxPerLoan: 500, GraceDays: 2},
+			daysOverdue: 2,
+			want:        0,
+		},
+		{
+			name:        "past grace period",
+			policy:      entity.FinePolicy{DailyRate: 25, MaxPerLoan: 500, GraceDays: 2},
+			daysOverdue: 5,
+			want:        75,
+		},
+		{
+			name:        "capped",
+			policy:      entity.FinePolicy{DailyRate: 25, MaxPerLoan: 500, GraceDays: 2},
+			daysOverdue: 100,
+			want:        500,
+		},
+		{
+			name:        "no cap",
+			policy:      entity.FinePolicy{DailyRate: 25},
+			daysOverdue: 100,
+			want:        2500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.policy.Amount(tt.daysOverdue))
+		})
+	}
+}
+
+func TestAccrueFines(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockLoanRepo := mocks.NewMockLoanRepository(ctrl)
+	mockFineRepo := mocks.NewMockFineRepository(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockTransactor := mocks.NewMockTransactor(ctrl)
+	logger, _ := zap.NewProduction()
+	useCase := library.New(logger, library.Repositories{
+		Loan:       mockLoanRepo,
+		Fine:       mockFineRepo,
+		Outbox:     mockOutboxRepo,
+		Transactor: mockTransactor,
+	})
+	ctx := t.Context()
+
+	policy := entity.FinePolicy{DailyRate: 25, MaxPerLoan: 500, GraceDays: 2}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	returnedAt := time.Date(2024, time.May, 6, 10, 0, 0, 0, time.UTC)
+
+	// The amounts of the open loans don't depend on the exact day, so the
+	// test is stable around midnight.
+	longOverdue := &entity.Loan{ID: uuid.NewString(), PatronID: uuid.NewString(),
+		DueOn: today.AddDate(0, 0, -30)}
+	inGrace := &entity.Loan{ID: uuid.NewString(), PatronID: uuid.NewString(),
+		DueOn: today.AddDate(0, 0, -1)}
+	returnedLate := &entity.Loan{ID: uuid.NewString(), PatronID: uuid.NewString(),
+		DueOn: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC), ReturnedAt: &returnedAt}
+	loans := []*entity.Loan{longOverdue, inGrace, returnedLate}
+
+	mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(len(loans))
+
+	mockLoanRepo.EXPECT().ListOverdueLoans(ctx, gomock.Any(), policy.GraceDays).Return(loans, nil)
+
+	upsert := func(_ context.Context, fine *entity.Fine) (*entity.Fine, error) {
+		fine.ID = uuid.NewString()
+		return fine, nil
+	}
+	mockFineRepo.EXPECT().UpsertFine(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fine *entity.Fine) (*entity.Fine, error) {
+			assert.Equal(t, longOverdue.ID, fine.LoanID)
+			assert.Equal(t, longOverdue.PatronID, fine.PatronID)
+			assert.Equal(t, int64(500), fine.Amount)
+			return upsert(ctx, fine)
+		})
+	mockFineRepo.EXPECT().UpsertFine(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fine *entity.Fine) (*entity.Fine, error) {
+			assert.Equal(t, returnedLate.ID, fine.LoanID)
+			assert.Equal(t, int64(75), fine.Amount)
+			assert.Equal(t, time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC), fine.AccruedOn)
+			return upsert(ctx, fine)
+		})
+
+	for _, loan := range []*entity.Loan{longOverdue, inGrace} {
+		mockOutboxRepo.EXPECT().SendMessage(ctx, gomock.Any(), repository.OutboxKindLoanOverdue, gomock.Any(),
+			gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, key string, _ repository.OutboxKind, _ string, message []byte, _ string) error {
+				notice := entity.OverdueNotice{}
+				require.NoError(t, json.Unmarshal(message, &notice))
+				assert.Equal(t, loan.ID, notice.Loan.ID)
+				assert.Contains(t, key, repository.OutboxKindLoanOverdue.String()+"_"+loan.ID+"_")
+				if loan == longOverdue {
+					assert.Equal(t, int64(500), notice.Fine)
+				} else {
+					assert.Zero(t, notice.Fine)
+				}
+				return nil
+			})
+	}
+
+	count, err := useCase.AccrueFines(ctx, policy)
+	require.NoError(t, err)
+	assert.Equal(t, len(loans), count)
+}
+
+func TestAccrueFinesError(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockLoanRepo := mocks.NewMockLoanRepository(ctrl)
+	mockFineRepo := mocks.NewMockFineRepository(ctrl)
+	mockTransactor := mocks.NewMockTransactor(ctrl)
+	logger, _ := zap.NewProduction()
+	useCase := library.New(logger, library.Repositories{
+		Loan:       mockLoanRepo,
+		Fine:       mockFineRepo,
+		Transactor: mockTransactor,
+	})
+	ctx := t.Context()
+
+	dueOn := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+	returnedAt := time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC)
+	loans := []*entity.Loan{
+		{ID: uuid.NewString(), DueOn: dueOn, ReturnedAt: &returnedAt},
+		{ID: uuid.NewString(), DueOn: dueOn, ReturnedAt: &returnedAt},
+	}
+
+	mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(2)
+	mockLoanRepo.EXPECT().ListOverdueLoans(ctx, gomock.Any(), 0).Return(loans, nil)
+	mockFineRepo.EXPECT().UpsertFine(ctx, gomock.Any()).Return(&entity.Fine{}, nil)
+	mockFineRepo.EXPECT().UpsertFine(ctx, gomock.Any()).Return(nil, errors.New("db error"))
+
+	count, err := useCase.AccrueFines(ctx, entity.FinePolicy{DailyRate: 10})
+	require.EqualError(t, err, "db error")
+	assert.Equal(t, 1, count)
+}
+
+func TestGetPatronAccount(t *testing.T) {
+	t.Parallel()
+
+	patron := &entity.Patron{ID: uuid.NewString(), Name: "Patron"}
+
+	tests := []struct {
+		name        string
+		getErr      error
+		fines       []*entity.Fine
+		wantBalance int64
+		wantErr     error
+	}{
+		{
+			name: "patron with fines",
+			fines: []*entity.Fine{
+				{ID: uuid.NewString(), Amount: 500, Paid: 200},
+				{ID: uuid.NewString(), Amount: 75},
+			},
+			wantBalance: 375,
+		},
+		{
+			name:  "patron without fines",
+			fines: []*entity.Fine{},
+		},
+		{
+			name:    "patron not found",
+			getErr:  entity.ErrPatronNotFound,
+			wantErr: entity.ErrPatronNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockPatronRepo := mocks.NewMockPatronRepository(ctrl)
+			mockFineRepo := mocks.NewMockFineRepository(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{Patron: mockPatronRepo, Fine: mockFineRepo})
+			ctx := t.Context()
+
+			if tt.getErr != nil {
+				mockPatronRepo.EXPECT().GetPatron(ctx, patron.ID).Return(nil, tt.getErr)
+			} else {
+				mockPatronRepo.EXPECT().GetPatron(ctx, patron.ID).Return(patron, nil)
+				mockFineRepo.EXPECT().ListOutstandingFines(ctx, patron.ID).Return(tt.fines, nil)
+			}
+
+			account, err := useCase.GetPatronAccount(ctx, patron.ID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, patron, account.Patron)
+			assert.Equal(t, tt.fines, account.Fines)
+			assert.Equal(t, tt.wantBalance, account.Balance)
+		})
+	}
+}
+
+func TestPayFine(t *testing.T) {
+	t.Parallel()
+
+	before := &entity.Fine{ID: uuid.NewString(), Amount: 500, Paid: 200}
+
+	tests := []struct {
+		name      string
+		amount    int64
+		getErr    error
+		mocksUsed bool
+		wantErr   error
+	}{
+		{
+			name:      "pay in part",
+			amount:    100,
+			mocksUsed: true,
+		},
+		{
+			name:      "pay in full",
+			amount:    300,
+			mocksUsed: true,
+		},
+		{
+			name:      "pay more than outstanding",
+			amount:    301,
+			mocksUsed: true,
+			wantErr:   entity.ErrInvalidPayment,
+		},
+		{
+			name:    "pay nothing",
+			amount:  0,
+			wantErr: entity.ErrInvalidPayment,
+		},
+		{
+			name:      "fine not found",
+			amount:    100,
+			getErr:    entity.ErrFineNotFound,
+			mocksUsed: true,
+			wantErr:   entity.ErrFineNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockFineRepo := mocks.NewMockFineRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{Fine: mockFineRepo, Transactor: mockTransactor})
+			ctx := t.Context()
+
+			if tt.mocksUsed {
+				mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					},
+				)
+				mockFineRepo.EXPECT().GetFineForUpdate(ctx, before.ID).Return(before, tt.getErr)
+			}
+			if tt.wantErr == nil {
+				mockFineRepo.EXPECT().PayFine(ctx, before.ID, tt.amount).Return(&entity.Fine{
+					ID:     before.ID,
+					Amount: before.Amount,
+					Paid:   before.Paid + tt.amount,
+				}, nil)
+			}
+
+			fine, err := useCase.PayFine(ctx, before.ID, tt.amount)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, before.Outstanding()-tt.amount, fine.Outstanding())
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package library
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func TestCreateGenre(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		genre      *entity.Genre
+		wantName   string
+		wantErr    error
+		repoCalled bool
+	}{
+		{
+			name:       "create genre",
+			genre:      &entity.Genre{Name: "Fiction"},
+			wantName:   "Fiction",
+			repoCalled: true,
+		},
+		{
+			name:       "whitespace is trimmed and collapsed",
+			genre:      &entity.Genre{Name: "  Science \t Fiction "},
+			wantName:   "Science Fiction",
+			repoCalled: true,
+		},
+		{
+			name:       "decomposed accents are composed",
+			genre:      &entity.Genre{Name: "Me\u0301moires", ParentID: uuid.NewString()},
+			wantName:   "Mémoires",
+			repoCalled: true,
+		},
+		{
+			name:    "blank name",
+			genre:   &entity.Genre{Name: " \t "},
+			wantErr: entity.ErrInvalidGenreName,
+		},
+		{
+			name:       "repository error",
+			genre:      &entity.Genre{Name: "Fiction"},
+			wantErr:    entity.ErrGenreAlreadyExists,
+			repoCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockGenreRepo := mocks.NewMockGenreRepository(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{Genre: mockGenreRepo})
+			ctx := t.Context()
+
+			if tt.repoCalled {
+				mockGenreRepo.EXPECT().CreateGenre(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, genre *entity.Genre) (*entity.Genre, error) {
+						if tt.wantErr != nil {
+							return nil, tt.wantErr
+						}
+						genre.ID = uuid.NewString()
+						return genre, nil
+					},
+				)
+			}
+
+			genre, err := useCase.CreateGenre(ctx, tt.genre)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, genre.Name)
+			assert.Equal(t, tt.genre.ParentID, genre.ParentID)
+		})
+	}
+}
+
+func TestUpdateGenre(t *testing.T) {
+	t.Parallel()
+
+	genreID := uuid.NewString()
+	parentID := uuid.NewString()
+
+	tests := []struct {
+		name       string
+		update     entity.GenreUpdate
+		wantUpdate entity.GenreUpdate
+		repoErr    error
+		wantErr    error
+		repoCalled bool
+	}{
+		{
+			name:       "rename genre",
+			update:     entity.GenreUpdate{Name: proto.String(" Science  Fiction ")},
+			wantUpdate: entity.GenreUpdate{Name: proto.String("Science Fiction")},
+			repoCalled: true,
+		},
+		{
+			name:       "move genre",
+			update:     entity.GenreUpdate{ParentID: proto.String(parentID)},
+			wantUpdate: entity.GenreUpdate{ParentID: proto.String(parentID)},
+			repoCalled: true,
+		},
+		{
+			name:       "move genre under its descendant",
+			update:     entity.GenreUpdate{ParentID: proto.String(parentID)},
+			wantUpdate: entity.GenreUpdate{ParentID: proto.String(parentID)},
+			repoErr:    entity.ErrGenreCycle,
+			wantErr:    entity.ErrGenreCycle,
+			repoCalled: true,
+		},
+		{
+			name:    "move genre under itself",
+			update:  entity.GenreUpdate{ParentID: proto.String(genreID)},
+			wantErr: entity.ErrGenreCycle,
+		},
+		{
+			name:    "blank name",
+			update:  entity.GenreUpdate{Name: proto.String(" ")},
+			wantErr: entity.ErrInvalidGenreName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockGenreRepo := mocks.NewMockGenreRepository(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{Genre: mockGenreRepo})
+			ctx := t.Context()
+
+			if tt.repoCalled {
+				var genre *entity.Genre
+				if tt.repoErr == nil {
+					genre = &entity.Genre{ID: genreID}
+				}
+				mockGenreRepo.EXPECT().UpdateGenre(ctx, genreID, tt.wantUpdate).
+					Return(genre, tt.repoErr)
+			}
+
+			_, err := useCase.UpdateGenre(ctx, genreID, tt.update)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAddBookTags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		tags     []string
+		wantTags []string
+		wantErr  error
+	}{
+		{
+			name:     "tags are lowercased and deduplicated",
+			tags:     []string{"Classic", " classic ", "Space  Opera"},
+			wantTags: []string{"classic", "space opera"},
+		},
+		{
+			name:     "no tags",
+			tags:     nil,
+			wantTags: []string{},
+		},
+		{
+			name:    "blank tag",
+			tags:    []string{"classic", " "},
+			wantErr: entity.ErrInvalidTag,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Books:      mockBooksRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			if tt.wantErr == nil {
+				mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					},
+				)
+				mockBooksRepo.EXPECT().AddBook(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, book *entity.Book) (*entity.Book, error) {
+						book.ID = uuid.NewString()
+						return book, nil
+					},
+				)
+				mockOutboxRepo.EXPECT().SendMessage(ctx, gomock.Any(),
+					repository.OutboxKindBook, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			}
+
+			book, err := useCase.AddBook(ctx, &entity.Book{
+				Name: "book",
+				Tags: tt.tags,
+			})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTags, book.Tags)
+		})
+	}
+}
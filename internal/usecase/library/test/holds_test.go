@@ -0,0 +1,321 @@
+package library
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func TestPlaceHold(t *testing.T) {
+	t.Parallel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	activePatron := &entity.Patron{
+		Status:              entity.PatronStatusActive,
+		MembershipExpiresOn: today.AddDate(1, 0, 0),
+	}
+
+	tests := []struct {
+		name         string
+		patron       *entity.Patron
+		availability *entity.BookAvailability
+		repoErr      error
+		wantErr      error
+	}{
+		{
+			name:         "place hold",
+			patron:       activePatron,
+			availability: &entity.BookAvailability{Total: 2, OnLoan: 1, OnHold: 1},
+		},
+		{
+			name:         "book without copies",
+			patron:       activePatron,
+			availability: &entity.BookAvailability{},
+		},
+		{
+			name:         "book has an available copy",
+			patron:       activePatron,
+			availability: &entity.BookAvailability{Total: 2, Available: 1, OnLoan: 1},
+			wantErr:      entity.ErrBookAvailable,
+		},
+		{
+			name:         "patron already holds the book",
+			patron:       activePatron,
+			availability: &entity.BookAvailability{Total: 1, OnLoan: 1},
+			repoErr:      entity.ErrHoldAlreadyExists,
+			wantErr:      entity.ErrHoldAlreadyExists,
+		},
+		{
+			name: "blocked patron",
+			patron: &entity.Patron{
+				Status:              entity.PatronStatusBlocked,
+				MembershipExpiresOn: today.AddDate(1, 0, 0),
+			},
+			wantErr: entity.ErrPatronBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockCopyRepo := mocks.NewMockCopyRepository(ctrl)
+			mockPatronRepo := mocks.NewMockPatronRepository(ctrl)
+			mockHoldRepo := mocks.NewMockHoldRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Copy:       mockCopyRepo,
+				Patron:     mockPatronRepo,
+				Hold:       mockHoldRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			bookID := uuid.NewString()
+			patronID := uuid.NewString()
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+			mockPatronRepo.EXPECT().GetPatron(ctx, patronID).Return(tt.patron, nil)
+
+			if tt.availability != nil {
+				mockHoldRepo.EXPECT().LockHoldQueue(ctx, bookID).Return(nil)
+				mockCopyRepo.EXPECT().GetBookAvailability(ctx, bookID).Return(tt.availability, nil)
+			}
+
+			if tt.availability != nil && tt.availability.Available == 0 {
+				mockHoldRepo.EXPECT().PlaceHold(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, hold *entity.Hold) (*entity.Hold, error) {
+						if tt.repoErr != nil {
+							return nil, tt.repoErr
+						}
+						hold.ID = uuid.NewString()
+						hold.Status = entity.HoldStatusWaiting
+						hold.QueuePosition = 1
+						return hold, nil
+					},
+				)
+			}
+
+			hold, err := useCase.PlaceHold(ctx, bookID, patronID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, bookID, hold.BookID)
+			assert.Equal(t, patronID, hold.PatronID)
+			assert.Equal(t, entity.HoldStatusWaiting, hold.Status)
+		})
+	}
+}
+
+func TestCancelHold(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		status      entity.HoldStatus
+		hasNextHold bool
+		wantErr     error
+	}{
+		{
+			name:   "cancel waiting hold",
+			status: entity.HoldStatusWaiting,
+		},
+		{
+			name:   "cancel ready hold",
+			status: entity.HoldStatusReady,
+		},
+		{
+			name:        "cancel ready hold with a queue",
+			status:      entity.HoldStatusReady,
+			hasNextHold: true,
+		},
+		{
+			name:    "cancel fulfilled hold",
+			status:  entity.HoldStatusFulfilled,
+			wantErr: entity.ErrHoldNotActive,
+		},
+		{
+			name:    "cancel cancelled hold",
+			status:  entity.HoldStatusCancelled,
+			wantErr: entity.ErrHoldNotActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockCopyRepo := mocks.NewMockCopyRepository(ctrl)
+			mockHoldRepo := mocks.NewMockHoldRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Copy:       mockCopyRepo,
+				Hold:       mockHoldRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			hold := &entity.Hold{
+				ID:     uuid.NewString(),
+				BookID: uuid.NewString(),
+				Status: tt.status,
+			}
+			if tt.status == entity.HoldStatusReady {
+				hold.CopyID = uuid.NewString()
+			}
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+			// The book is locked before the hold.
+			gomock.InOrder(
+				mockHoldRepo.EXPECT().GetHold(ctx, hold.ID).Return(hold, nil),
+				mockHoldRepo.EXPECT().LockHoldQueue(ctx, hold.BookID).Return(nil),
+				mockHoldRepo.EXPECT().GetHoldForUpdate(ctx, hold.ID).Return(hold, nil),
+			)
+
+			if tt.wantErr == nil {
+				mockHoldRepo.EXPECT().SetHoldStatus(ctx, hold.ID, entity.HoldStatusCancelled).
+					Return(&entity.Hold{ID: hold.ID, Status: entity.HoldStatusCancelled}, nil)
+			}
+
+			if tt.status == entity.HoldStatusReady {
+				mockHoldRepo.EXPECT().LockHoldQueue(ctx, hold.BookID).Return(nil)
+
+				copyStatus := entity.CopyStatusAvailable
+				if tt.hasNextHold {
+					copyStatus = entity.CopyStatusOnHold
+					next := &entity.Hold{ID: uuid.NewString(), BookID: hold.BookID}
+					mockHoldRepo.EXPECT().GetNextWaitingHold(ctx, hold.BookID).Return(next, nil)
+					mockHoldRepo.EXPECT().MarkHoldReady(ctx, next.ID, hold.CopyID, gomock.Any()).
+						Return(next, nil)
+					mockOutboxRepo.EXPECT().SendMessage(ctx, gomock.Any(),
+						repository.OutboxKindHoldReady, gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(nil)
+				} else {
+					mockHoldRepo.EXPECT().GetNextWaitingHold(ctx, hold.BookID).
+						Return(nil, entity.ErrHoldNotFound)
+				}
+
+				mockCopyRepo.EXPECT().UpdateCopy(ctx, hold.CopyID, entity.CopyUpdate{Status: &copyStatus}).
+					Return(&entity.Copy{ID: hold.CopyID, Status: copyStatus}, nil)
+			}
+
+			got, err := useCase.CancelHold(ctx, hold.ID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, entity.HoldStatusCancelled, got.Status)
+		})
+	}
+}
+
+func TestListHolds(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockHoldRepo := mocks.NewMockHoldRepository(ctrl)
+	logger, _ := zap.NewProduction()
+	useCase := library.New(logger, library.Repositories{Hold: mockHoldRepo})
+	ctx := t.Context()
+
+	_, err := useCase.ListHolds(ctx, entity.HoldsFilter{})
+	require.ErrorIs(t, err, entity.ErrInvalidHoldsFilter)
+
+	filter := entity.HoldsFilter{PatronID: uuid.NewString()}
+	mockHoldRepo.EXPECT().ListHolds(ctx, filter).Return([]*entity.Hold{{ID: uuid.NewString()}}, nil)
+
+	holds, err := useCase.ListHolds(ctx, filter)
+	require.NoError(t, err)
+	assert.Len(t, holds, 1)
+}
+
+func TestExpireHolds(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockCopyRepo := mocks.NewMockCopyRepository(ctrl)
+	mockHoldRepo := mocks.NewMockHoldRepository(ctrl)
+	mockTransactor := mocks.NewMockTransactor(ctrl)
+	logger, _ := zap.NewProduction()
+	useCase := library.New(logger, library.Repositories{
+		Copy:       mockCopyRepo,
+		Hold:       mockHoldRepo,
+		Transactor: mockTransactor,
+	})
+	ctx := t.Context()
+
+	expired := []*entity.Hold{
+		{ID: uuid.NewString(), BookID: uuid.NewString(), CopyID: uuid.NewString(), Status: entity.HoldStatusReady},
+		{ID: uuid.NewString(), BookID: uuid.NewString(), CopyID: uuid.NewString(), Status: entity.HoldStatusReady},
+	}
+	// Picked up while the book was locked by the checkout.
+	fulfilled := &entity.Hold{ID: uuid.NewString(), BookID: uuid.NewString(), CopyID: uuid.NewString(),
+		Status: entity.HoldStatusFulfilled}
+	available := entity.CopyStatusAvailable
+
+	mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(len(expired) + 2)
+
+	calls := []any{
+		mockHoldRepo.EXPECT().GetExpiredHold(ctx, gomock.Any()).
+			Return(&entity.Hold{ID: fulfilled.ID, BookID: fulfilled.BookID, Status: entity.HoldStatusReady}, nil),
+		mockHoldRepo.EXPECT().LockHoldQueue(ctx, fulfilled.BookID).Return(nil),
+		mockHoldRepo.EXPECT().GetHoldForUpdate(ctx, fulfilled.ID).Return(fulfilled, nil),
+	}
+	for _, hold := range expired {
+		calls = append(calls,
+			mockHoldRepo.EXPECT().GetExpiredHold(ctx, gomock.Any()).Return(hold, nil),
+			mockHoldRepo.EXPECT().LockHoldQueue(ctx, hold.BookID).Return(nil),
+			mockHoldRepo.EXPECT().GetHoldForUpdate(ctx, hold.ID).Return(hold, nil),
+			mockHoldRepo.EXPECT().SetHoldStatus(ctx, hold.ID, entity.HoldStatusExpired).Return(hold, nil),
+			mockHoldRepo.EXPECT().LockHoldQueue(ctx, hold.BookID).Return(nil),
+			mockHoldRepo.EXPECT().GetNextWaitingHold(ctx, hold.BookID).Return(nil, entity.ErrHoldNotFound),
+			mockCopyRepo.EXPECT().UpdateCopy(ctx, hold.CopyID, entity.CopyUpdate{Status: &available}).
+				Return(&entity.Copy{ID: hold.CopyID, Status: available}, nil),
+		)
+	}
+	calls = append(calls,
+		mockHoldRepo.EXPECT().GetExpiredHold(ctx, gomock.Any()).Return(nil, entity.ErrHoldNotFound))
+	gomock.InOrder(calls...)
+
+	count, err := useCase.ExpireHolds(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(expired), count)
+}
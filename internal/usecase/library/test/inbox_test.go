@@ -0,0 +1,233 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func TestApplyBookPurchased(t *testing.T) {
+	t.Parallel()
+
+	authorID := uuid.NewString()
+	event := entity.BookPurchased{
+		Name:      "Dune",
+		AuthorIDs: []string{authorID},
+		ISBN:      "978-0-441-17271-9",
+		Language:  "en",
+	}
+
+	tests := []struct {
+		name      string
+		event     entity.BookPurchased
+		getErr    error
+		addErr    error
+		outboxErr error
+		wantAdded bool
+		wantErr   error
+	}{
+		{
+			name:      "apply book purchased",
+			event:     event,
+			getErr:    entity.ErrBookNotFound,
+			wantAdded: true,
+		},
+		{
+			name:  "apply book purchased | isbn already in catalog",
+			event: event,
+		},
+		{
+			name:      "apply book purchased | no isbn",
+			event:     entity.BookPurchased{Name: "Dune", AuthorIDs: []string{authorID}},
+			wantAdded: true,
+		},
+		{
+			name:    "apply book purchased | invalid isbn",
+			event:   entity.BookPurchased{Name: "Dune", ISBN: "123"},
+			wantErr: entity.ErrInvalidISBN,
+		},
+		{
+			name:    "apply book purchased | lookup error",
+			event:   event,
+			getErr:  errors.New("db error"),
+			wantErr: errors.New("db error"),
+		},
+		{
+			name:    "apply book purchased | author not found",
+			event:   event,
+			getErr:  entity.ErrBookNotFound,
+			addErr:  entity.ErrAuthorNotFound,
+			wantErr: entity.ErrAuthorNotFound,
+		},
+		{
+			name:      "apply book purchased | outbox error",
+			event:     event,
+			getErr:    entity.ErrBookNotFound,
+			outboxErr: errors.New("outbox error"),
+			wantAdded: true,
+			wantErr:   errors.New("outbox error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			logger, _ := zap.NewProduction()
+			// No transactor: the event is applied in the inbox's transaction.
+			useCase := library.New(logger, library.Repositories{Books: mockBooksRepo, Outbox: mockOutboxRepo})
+			ctx := t.Context()
+
+			if tt.event.ISBN != "" && !errors.Is(tt.wantErr, entity.ErrInvalidISBN) {
+				mockBooksRepo.EXPECT().GetBookByISBN(ctx, "9780441172719").Return(&entity.Book{}, tt.getErr)
+			}
+
+			if tt.wantAdded || tt.addErr != nil {
+				mockBooksRepo.EXPECT().AddBook(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, book *entity.Book) (*entity.Book, error) {
+						if tt.addErr != nil {
+							return nil, tt.addErr
+						}
+						require.Equal(t, tt.event.Name, book.Name)
+						require.Equal(t, tt.event.AuthorIDs, book.AuthorIDs)
+						book.ID = "book-1"
+						return book, nil
+					},
+				)
+			}
+
+			if tt.wantAdded {
+				mockOutboxRepo.EXPECT().SendMessage(ctx, "book_book-1",
+					repository.OutboxKindBook, "book-1", gomock.Any(), gomock.Any()).
+					Return(tt.outboxErr)
+			}
+
+			err := useCase.ApplyBookPurchased(ctx, tt.event)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestApplyAuthorMerged(t *testing.T) {
+	t.Parallel()
+
+	sourceID := uuid.NewString()
+	targetID := uuid.NewString()
+	event := entity.AuthorMerged{SourceID: sourceID, TargetID: targetID}
+
+	before := &entity.Book{ID: uuid.NewString(), AuthorIDs: []string{sourceID}, Version: 1}
+	after := &entity.Book{ID: before.ID, AuthorIDs: []string{targetID}, Version: 2}
+	deleted := &entity.Author{ID: sourceID, Version: 3}
+	bookChange, _ := json.Marshal(entity.BookChange{Before: before, After: after})
+	deletedAuthor, _ := json.Marshal(deleted)
+
+	tests := []struct {
+		name      string
+		event     entity.AuthorMerged
+		targetErr error
+		sourceErr error
+		updateErr error
+		wantErr   error
+	}{
+		{
+			name:  "apply author merged",
+			event: event,
+		},
+		{
+			name:    "apply author merged | into itself",
+			event:   entity.AuthorMerged{SourceID: sourceID, TargetID: sourceID},
+			wantErr: entity.ErrAuthorMergedIntoItself,
+		},
+		{
+			name:      "apply author merged | target not found",
+			event:     event,
+			targetErr: entity.ErrAuthorNotFound,
+			wantErr:   entity.ErrAuthorNotFound,
+		},
+		{
+			name:      "apply author merged | source not found",
+			event:     event,
+			sourceErr: entity.ErrAuthorNotFound,
+			wantErr:   entity.ErrAuthorNotFound,
+		},
+		{
+			name:      "apply author merged | book update error",
+			event:     event,
+			updateErr: errors.New("db error"),
+			wantErr:   errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Author: mockAuthorRepo,
+				Books:  mockBooksRepo,
+				Outbox: mockOutboxRepo,
+			})
+			ctx := t.Context()
+
+			if tt.event.SourceID != tt.event.TargetID {
+				mockAuthorRepo.EXPECT().GetAuthorForUpdate(ctx, targetID).
+					Return(&entity.Author{ID: targetID}, tt.targetErr)
+			}
+
+			if tt.targetErr == nil && tt.event.SourceID != tt.event.TargetID {
+				mockAuthorRepo.EXPECT().GetAuthorForUpdate(ctx, sourceID).
+					Return(&entity.Author{ID: sourceID}, tt.sourceErr)
+			}
+
+			if tt.wantErr == nil || tt.updateErr != nil {
+				mockAuthorRepo.EXPECT().GetAuthorBooks(ctx, sourceID, false).
+					Return([]*entity.Book{before}, nil)
+				mockBooksRepo.EXPECT().GetBookForUpdate(ctx, before.ID).Return(before, nil)
+				mockBooksRepo.EXPECT().UpdateBook(ctx, before.ID, entity.BookUpdate{
+					AddAuthorIDs:    []string{targetID},
+					RemoveAuthorIDs: []string{sourceID},
+				}, int64(0)).Return(after, tt.updateErr)
+			}
+
+			if tt.wantErr == nil {
+				mockOutboxRepo.EXPECT().SendMessage(ctx, "book_updated_"+after.ID+"_2",
+					repository.OutboxKindBookUpdated, after.ID, bookChange, gomock.Any()).Return(nil)
+				mockAuthorRepo.EXPECT().SoftDeleteAuthor(ctx, sourceID).Return(deleted, nil)
+				mockOutboxRepo.EXPECT().SendMessage(ctx, "author_deleted_"+sourceID+"_3",
+					repository.OutboxKindAuthorDeleted, sourceID, deletedAuthor, gomock.Any()).Return(nil)
+			}
+
+			err := useCase.ApplyAuthorMerged(ctx, tt.event)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
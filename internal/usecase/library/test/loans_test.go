@@ -0,0 +1,412 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func TestCheckoutBook(t *testing.T) {
+	t.Parallel()
+
+	const (
+		patronSelf = iota + 1
+		patronOther
+	)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	activePatron := &entity.Patron{
+		Status:              entity.PatronStatusActive,
+		MembershipExpiresOn: today.AddDate(1, 0, 0),
+	}
+
+	tests := []struct {
+		name       string
+		patron     *entity.Patron
+		copyStatus entity.CopyStatus
+		holdFor    int
+		openLoans  int
+		wantErr    error
+	}{
+		{
+			name:       "checkout book",
+			patron:     activePatron,
+			copyStatus: entity.CopyStatusAvailable,
+			openLoans:  4,
+		},
+		{
+			name: "blocked patron",
+			patron: &entity.Patron{
+				Status:              entity.PatronStatusBlocked,
+				MembershipExpiresOn: today.AddDate(1, 0, 0),
+			},
+			wantErr: entity.ErrPatronBlocked,
+		},
+		{
+			name: "expired membership",
+			patron: &entity.Patron{
+				Status:              entity.PatronStatusActive,
+				MembershipExpiresOn: today.AddDate(0, 0, -1),
+			},
+			wantErr: entity.ErrMembershipExpired,
+		},
+		{
+			name:       "copy already on loan",
+			patron:     activePatron,
+			copyStatus: entity.CopyStatusOnLoan,
+			wantErr:    entity.ErrCopyNotAvailable,
+		},
+		{
+			name:       "copy on hold for the patron",
+			patron:     activePatron,
+			copyStatus: entity.CopyStatusOnHold,
+			holdFor:    patronSelf,
+		},
+		{
+			name:       "copy on hold for another patron",
+			patron:     activePatron,
+			copyStatus: entity.CopyStatusOnHold,
+			holdFor:    patronOther,
+			wantErr:    entity.ErrCopyNotAvailable,
+		},
+		{
+			name:       "copy in repair",
+			patron:     activePatron,
+			copyStatus: entity.CopyStatusInRepair,
+			wantErr:    entity.ErrCopyNotAvailable,
+		},
+		{
+			name:       "loan limit reached",
+			patron:     activePatron,
+			copyStatus: entity.CopyStatusAvailable,
+			openLoans:  5,
+			wantErr:    entity.ErrLoanLimitReached,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockCopyRepo := mocks.NewMockCopyRepository(ctrl)
+			mockPatronRepo := mocks.NewMockPatronRepository(ctrl)
+			mockLoanRepo := mocks.NewMockLoanRepository(ctrl)
+			mockHoldRepo := mocks.NewMockHoldRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Copy:       mockCopyRepo,
+				Patron:     mockPatronRepo,
+				Loan:       mockLoanRepo,
+				Hold:       mockHoldRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			copyID := uuid.NewString()
+			bookID := uuid.NewString()
+			patronID := uuid.NewString()
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+			mockPatronRepo.EXPECT().GetPatronForUpdate(ctx, patronID).Return(tt.patron, nil)
+
+			if tt.copyStatus != "" {
+				bookCopy := &entity.Copy{ID: copyID, BookID: bookID, Status: tt.copyStatus}
+				// The hold queue of the book is locked before the copy.
+				gomock.InOrder(
+					mockCopyRepo.EXPECT().GetCopy(ctx, copyID).Return(bookCopy, nil),
+					mockHoldRepo.EXPECT().LockHoldQueue(ctx, bookID).Return(nil),
+					mockCopyRepo.EXPECT().GetCopyForUpdate(ctx, copyID).Return(bookCopy, nil),
+				)
+			}
+
+			holdID := uuid.NewString()
+			if tt.holdFor != 0 {
+				holdPatronID := patronID
+				if tt.holdFor == patronOther {
+					holdPatronID = uuid.NewString()
+				}
+				mockHoldRepo.EXPECT().GetReadyHoldForCopy(ctx, copyID).
+					Return(&entity.Hold{ID: holdID, PatronID: holdPatronID, CopyID: copyID}, nil)
+			}
+
+			if tt.copyStatus == entity.CopyStatusAvailable || tt.holdFor == patronSelf {
+				mockLoanRepo.EXPECT().CountOpenLoans(ctx, patronID).Return(tt.openLoans, nil)
+			}
+
+			if tt.holdFor == patronSelf {
+				mockHoldRepo.EXPECT().SetHoldStatus(ctx, holdID, entity.HoldStatusFulfilled).
+					Return(&entity.Hold{ID: holdID, Status: entity.HoldStatusFulfilled}, nil)
+			}
+
+			if tt.wantErr == nil {
+				onLoan := entity.CopyStatusOnLoan
+				mockLoanRepo.EXPECT().CreateLoan(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, loan *entity.Loan) (*entity.Loan, error) {
+						loan.ID = uuid.NewString()
+						loan.BookID = bookID
+						return loan, nil
+					},
+				)
+				mockCopyRepo.EXPECT().UpdateCopy(ctx, copyID, entity.CopyUpdate{Status: &onLoan}).
+					Return(&entity.Copy{ID: copyID, Status: onLoan}, nil)
+				mockOutboxRepo.EXPECT().SendMessage(ctx, gomock.Any(),
+					repository.OutboxKindLoanCheckedOut, gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ repository.OutboxKind, _ string, message []byte, _ string) error {
+						var loan entity.Loan
+						require.NoError(t, json.Unmarshal(message, &loan))
+						assert.Equal(t, bookID, loan.BookID)
+						return nil
+					})
+			}
+
+			loan, err := useCase.CheckoutBook(ctx, copyID, patronID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, copyID, loan.CopyID)
+			assert.Equal(t, patronID, loan.PatronID)
+			assert.Equal(t, today.AddDate(0, 0, 21), loan.DueOn)
+		})
+	}
+}
+
+func TestReturnBook(t *testing.T) {
+	t.Parallel()
+
+	returnedAt := time.Now()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		returnedAt *time.Time
+		nextHold   *entity.Hold
+		wantStatus entity.CopyStatus
+		wantErr    error
+	}{
+		{
+			name:       "return book",
+			wantStatus: entity.CopyStatusAvailable,
+		},
+		{
+			name:       "return book with a waiting hold",
+			nextHold:   &entity.Hold{ID: uuid.NewString(), Status: entity.HoldStatusWaiting},
+			wantStatus: entity.CopyStatusOnHold,
+		},
+		{
+			name:       "already returned",
+			returnedAt: &returnedAt,
+			wantErr:    entity.ErrLoanAlreadyReturned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockCopyRepo := mocks.NewMockCopyRepository(ctrl)
+			mockLoanRepo := mocks.NewMockLoanRepository(ctrl)
+			mockHoldRepo := mocks.NewMockHoldRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Copy:       mockCopyRepo,
+				Loan:       mockLoanRepo,
+				Hold:       mockHoldRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			loan := &entity.Loan{
+				ID:         uuid.NewString(),
+				CopyID:     uuid.NewString(),
+				BookID:     uuid.NewString(),
+				ReturnedAt: tt.returnedAt,
+			}
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+			mockLoanRepo.EXPECT().GetLoanForUpdate(ctx, loan.ID).Return(loan, nil)
+
+			if tt.wantErr == nil {
+				returned := *loan
+				returned.ReturnedAt = &returnedAt
+
+				mockLoanRepo.EXPECT().ReturnLoan(ctx, loan.ID).Return(&returned, nil)
+				mockOutboxRepo.EXPECT().SendMessage(ctx, gomock.Any(),
+					repository.OutboxKindLoanReturned, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+				mockHoldRepo.EXPECT().LockHoldQueue(ctx, loan.BookID).Return(nil)
+
+				if tt.nextHold == nil {
+					mockHoldRepo.EXPECT().GetNextWaitingHold(ctx, loan.BookID).
+						Return(nil, entity.ErrHoldNotFound)
+				} else {
+					expiresOn := today.AddDate(0, 0, 7)
+					ready := *tt.nextHold
+					ready.Status = entity.HoldStatusReady
+					ready.CopyID = loan.CopyID
+					ready.ExpiresOn = &expiresOn
+
+					mockHoldRepo.EXPECT().GetNextWaitingHold(ctx, loan.BookID).Return(tt.nextHold, nil)
+					mockHoldRepo.EXPECT().MarkHoldReady(ctx, tt.nextHold.ID, loan.CopyID, expiresOn).
+						Return(&ready, nil)
+					mockOutboxRepo.EXPECT().SendMessage(ctx,
+						"hold_ready_"+tt.nextHold.ID, repository.OutboxKindHoldReady, gomock.Any(),
+						gomock.Any(), gomock.Any()).
+						Return(nil)
+				}
+
+				mockCopyRepo.EXPECT().UpdateCopy(ctx, loan.CopyID, entity.CopyUpdate{Status: &tt.wantStatus}).
+					Return(&entity.Copy{ID: loan.CopyID, Status: tt.wantStatus}, nil)
+			}
+
+			got, err := useCase.ReturnBook(ctx, loan.ID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, got.ReturnedAt)
+		})
+	}
+}
+
+func TestRenewLoan(t *testing.T) {
+	t.Parallel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	returnedAt := time.Now()
+	activePatron := &entity.Patron{
+		Status:              entity.PatronStatusActive,
+		MembershipExpiresOn: today.AddDate(1, 0, 0),
+	}
+
+	tests := []struct {
+		name      string
+		loan      entity.Loan
+		patron    *entity.Patron
+		wantDueOn time.Time
+		wantErr   error
+	}{
+		{
+			name:      "renew loan",
+			loan:      entity.Loan{DueOn: today.AddDate(0, 0, 3)},
+			patron:    activePatron,
+			wantDueOn: today.AddDate(0, 0, 24),
+		},
+		{
+			name:      "renew overdue loan",
+			loan:      entity.Loan{DueOn: today.AddDate(0, 0, -10), Renewals: 1},
+			patron:    activePatron,
+			wantDueOn: today.AddDate(0, 0, 21),
+		},
+		{
+			name:    "renewal limit reached",
+			loan:    entity.Loan{DueOn: today, Renewals: 2},
+			wantErr: entity.ErrRenewalLimitReached,
+		},
+		{
+			name:    "already returned",
+			loan:    entity.Loan{DueOn: today, ReturnedAt: &returnedAt},
+			wantErr: entity.ErrLoanAlreadyReturned,
+		},
+		{
+			name: "blocked patron",
+			loan: entity.Loan{DueOn: today},
+			patron: &entity.Patron{
+				Status:              entity.PatronStatusBlocked,
+				MembershipExpiresOn: today.AddDate(1, 0, 0),
+			},
+			wantErr: entity.ErrPatronBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockPatronRepo := mocks.NewMockPatronRepository(ctrl)
+			mockLoanRepo := mocks.NewMockLoanRepository(ctrl)
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{
+				Patron:     mockPatronRepo,
+				Loan:       mockLoanRepo,
+				Outbox:     mockOutboxRepo,
+				Transactor: mockTransactor,
+			})
+			ctx := t.Context()
+
+			loan := tt.loan
+			loan.ID = uuid.NewString()
+			loan.PatronID = uuid.NewString()
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+			mockLoanRepo.EXPECT().GetLoanForUpdate(ctx, loan.ID).Return(&loan, nil)
+
+			if tt.patron != nil {
+				mockPatronRepo.EXPECT().GetPatron(ctx, loan.PatronID).Return(tt.patron, nil)
+			}
+
+			if tt.wantErr == nil {
+				renewed := loan
+				renewed.DueOn = tt.wantDueOn
+				renewed.Renewals++
+
+				mockLoanRepo.EXPECT().RenewLoan(ctx, loan.ID, tt.wantDueOn).Return(&renewed, nil)
+				mockOutboxRepo.EXPECT().SendMessage(ctx, gomock.Any(),
+					repository.OutboxKindLoanRenewed, gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			}
+
+			got, err := useCase.RenewLoan(ctx, loan.ID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDueOn, got.DueOn)
+			assert.Equal(t, tt.loan.Renewals+1, got.Renewals)
+		})
+	}
+}
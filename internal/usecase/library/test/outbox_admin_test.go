@@ -0,0 +1,212 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func TestListOutboxMessages(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	logger, _ := zap.NewProduction()
+	useCase := library.New(logger, library.Repositories{Outbox: mockOutboxRepo})
+	ctx := t.Context()
+
+	filter := repository.OutboxFilter{Status: repository.OutboxStatusDead}
+	createdAt := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+	messages := []*repository.OutboxMessage{
+		{IdempotencyKey: "a", CreatedAt: createdAt},
+		{IdempotencyKey: "b", CreatedAt: createdAt},
+		{IdempotencyKey: "c", CreatedAt: createdAt.Add(time.Second)},
+	}
+
+	mockOutboxRepo.EXPECT().ListMessages(ctx, filter, (*repository.OutboxCursor)(nil), 3).
+		Return(messages, nil)
+
+	page, nextPageToken, err := useCase.ListOutboxMessages(ctx, filter, 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, messages[:2], page)
+	require.NotEmpty(t, nextPageToken)
+
+	mockOutboxRepo.EXPECT().ListMessages(ctx, filter,
+		&repository.OutboxCursor{CreatedAt: createdAt, IdempotencyKey: "b"}, 3).
+		Return(messages[2:], nil)
+
+	page, nextPageToken, err = useCase.ListOutboxMessages(ctx, filter, 2, nextPageToken)
+	require.NoError(t, err)
+	assert.Equal(t, messages[2:], page)
+	assert.Empty(t, nextPageToken)
+
+	_, _, err = useCase.ListOutboxMessages(ctx, filter, 2, "token")
+	require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+}
+
+func TestListOutboxMessagesTokenOfAnotherFilter(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	logger, _ := zap.NewProduction()
+	useCase := library.New(logger, library.Repositories{Outbox: mockOutboxRepo})
+	ctx := t.Context()
+
+	createdAt := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+	filter := repository.OutboxFilter{
+		Status:       repository.OutboxStatusDead,
+		Kind:         repository.OutboxKindBook,
+		CreatedAfter: createdAt.Add(-time.Hour),
+	}
+	messages := []*repository.OutboxMessage{
+		{IdempotencyKey: "a", CreatedAt: createdAt},
+		{IdempotencyKey: "b", CreatedAt: createdAt},
+	}
+
+	mockOutboxRepo.EXPECT().ListMessages(ctx, filter, (*repository.OutboxCursor)(nil), 2).
+		Return(messages, nil)
+
+	_, nextPageToken, err := useCase.ListOutboxMessages(ctx, filter, 1, "")
+	require.NoError(t, err)
+
+	for _, other := range []repository.OutboxFilter{
+		{Status: repository.OutboxStatusFailed, Kind: filter.Kind, CreatedAfter: filter.CreatedAfter},
+		{Status: filter.Status, Kind: repository.OutboxKindAuthor, CreatedAfter: filter.CreatedAfter},
+		{Status: filter.Status, Kind: filter.Kind},
+		{Status: filter.Status, Kind: filter.Kind, CreatedAfter: filter.CreatedAfter, CreatedBefore: createdAt},
+	} {
+		_, _, err = useCase.ListOutboxMessages(ctx, other, 1, nextPageToken)
+		require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+	}
+}
+
+func TestRetryOutboxMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		status  repository.OutboxStatus
+		getErr  error
+		wantErr error
+	}{
+		{
+			name:   "retry dead message",
+			status: repository.OutboxStatusDead,
+		},
+		{
+			name:   "retry failed message",
+			status: repository.OutboxStatusFailed,
+		},
+		{
+			name:   "replay delivered message",
+			status: repository.OutboxStatusSuccess,
+		},
+		{
+			name:    "created message",
+			status:  repository.OutboxStatusCreated,
+			wantErr: entity.ErrOutboxMessagePending,
+		},
+		{
+			name:    "message in progress",
+			status:  repository.OutboxStatusInProgress,
+			wantErr: entity.ErrOutboxMessagePending,
+		},
+		{
+			name:    "message not found",
+			getErr:  entity.ErrOutboxMessageNotFound,
+			wantErr: entity.ErrOutboxMessageNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{Outbox: mockOutboxRepo, Transactor: mockTransactor})
+			ctx := t.Context()
+
+			const key = "book_updated_id_1"
+
+			mockTransactor.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+
+			if tt.getErr != nil {
+				mockOutboxRepo.EXPECT().GetMessageForUpdate(ctx, key).Return(nil, tt.getErr)
+			} else {
+				mockOutboxRepo.EXPECT().GetMessageForUpdate(ctx, key).Return(&repository.OutboxMessage{
+					IdempotencyKey: key,
+					Status:         tt.status,
+					Attempts:       10,
+				}, nil)
+			}
+
+			if tt.wantErr == nil {
+				mockOutboxRepo.EXPECT().RetryMessage(ctx, key).Return(&repository.OutboxMessage{
+					IdempotencyKey: key,
+					Status:         repository.OutboxStatusCreated,
+				}, nil)
+			}
+
+			message, err := useCase.RetryOutboxMessage(ctx, key)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, repository.OutboxStatusCreated, message.Status)
+			assert.Zero(t, message.Attempts)
+		})
+	}
+}
+
+func TestPurgeOutbox(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	logger, _ := zap.NewProduction()
+	useCase := library.New(logger, library.Repositories{Outbox: mockOutboxRepo})
+	ctx := t.Context()
+
+	// Full batches are followed by another one until a short batch.
+	gomock.InOrder(
+		mockOutboxRepo.EXPECT().PurgeProcessed(ctx, 24*time.Hour, 1000).Return(int64(1000), nil),
+		mockOutboxRepo.EXPECT().PurgeProcessed(ctx, 24*time.Hour, 1000).Return(int64(1000), nil),
+		mockOutboxRepo.EXPECT().PurgeProcessed(ctx, 24*time.Hour, 1000).Return(int64(42), nil),
+	)
+	deleted, err := useCase.PurgeOutbox(ctx, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2042), deleted)
+
+	gomock.InOrder(
+		mockOutboxRepo.EXPECT().PurgeProcessed(ctx, time.Hour, 1000).Return(int64(1000), nil),
+		mockOutboxRepo.EXPECT().PurgeProcessed(ctx, time.Hour, 1000).Return(int64(0), errors.New("db error")),
+	)
+	deleted, err = useCase.PurgeOutbox(ctx, time.Hour)
+	require.EqualError(t, err, "db error")
+	assert.Equal(t, int64(1000), deleted)
+}
@@ -0,0 +1,364 @@
+package library
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func TestRegisterPatron(t *testing.T) {
+	t.Parallel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		patron        *entity.Patron
+		repoErrs      []error
+		wantName      string
+		wantEmail     string
+		wantExpiresOn time.Time
+		wantErr       error
+	}{
+		{
+			name: "register patron",
+			patron: &entity.Patron{
+				Name:                "  Ada \t Lovelace ",
+				Email:               " Ada@Example.COM ",
+				MembershipExpiresOn: today.AddDate(0, 6, 0),
+			},
+			repoErrs:      []error{nil},
+			wantName:      "Ada Lovelace",
+			wantEmail:     "ada@example.com",
+			wantExpiresOn: today.AddDate(0, 6, 0),
+		},
+		{
+			name:          "membership defaults to a year",
+			patron:        &entity.Patron{Name: "Ada Lovelace"},
+			repoErrs:      []error{nil},
+			wantName:      "Ada Lovelace",
+			wantExpiresOn: today.AddDate(1, 0, 0),
+		},
+		{
+			name:          "membership expiring today",
+			patron:        &entity.Patron{Name: "Ada Lovelace", MembershipExpiresOn: today},
+			repoErrs:      []error{nil},
+			wantName:      "Ada Lovelace",
+			wantExpiresOn: today,
+		},
+		{
+			name:          "card number collision is retried",
+			patron:        &entity.Patron{Name: "Ada Lovelace"},
+			repoErrs:      []error{entity.ErrCardNumberAlreadyExists, nil},
+			wantName:      "Ada Lovelace",
+			wantExpiresOn: today.AddDate(1, 0, 0),
+		},
+		{
+			name:   "card number collisions exhaust retries",
+			patron: &entity.Patron{Name: "Ada Lovelace"},
+			repoErrs: []error{
+				entity.ErrCardNumberAlreadyExists,
+				entity.ErrCardNumberAlreadyExists,
+				entity.ErrCardNumberAlreadyExists,
+			},
+			wantErr: entity.ErrCardNumberAlreadyExists,
+		},
+		{
+			name: "membership expired in the past",
+			patron: &entity.Patron{
+				Name:                "Ada Lovelace",
+				MembershipExpiresOn: today.AddDate(0, 0, -1),
+			},
+			wantErr: entity.ErrInvalidMembershipExpiry,
+		},
+		{
+			name:    "blank name",
+			patron:  &entity.Patron{Name: " \t "},
+			wantErr: entity.ErrInvalidPatronName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockPatronRepo := mocks.NewMockPatronRepository(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{Patron: mockPatronRepo})
+			ctx := t.Context()
+
+			cardNumbers := make([]string, 0, len(tt.repoErrs))
+			for _, repoErr := range tt.repoErrs {
+				mockPatronRepo.EXPECT().RegisterPatron(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, patron *entity.Patron) (*entity.Patron, error) {
+						cardNumbers = append(cardNumbers, patron.CardNumber)
+						if repoErr != nil {
+							return nil, repoErr
+						}
+						patron.ID = uuid.NewString()
+						return patron, nil
+					},
+				)
+			}
+
+			patron, err := useCase.RegisterPatron(ctx, tt.patron)
+			for _, cardNumber := range cardNumbers {
+				assert.Len(t, cardNumber, 14)
+				assert.True(t, strings.HasPrefix(cardNumber, "29"))
+			}
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, patron.Name)
+			assert.Equal(t, tt.wantEmail, patron.Email)
+			assert.Equal(t, tt.wantExpiresOn, patron.MembershipExpiresOn)
+			assert.Equal(t, cardNumbers[len(cardNumbers)-1], patron.CardNumber)
+		})
+	}
+}
+
+func TestRegisteredCardNumberIsValid(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockPatronRepo := mocks.NewMockPatronRepository(ctrl)
+	logger, _ := zap.NewProduction()
+	useCase := library.New(logger, library.Repositories{Patron: mockPatronRepo})
+	ctx := t.Context()
+
+	mockPatronRepo.EXPECT().RegisterPatron(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, patron *entity.Patron) (*entity.Patron, error) {
+			return patron, nil
+		},
+	)
+
+	patron, err := useCase.RegisterPatron(ctx, &entity.Patron{Name: "Ada Lovelace"})
+	require.NoError(t, err)
+
+	mockPatronRepo.EXPECT().GetPatronByCardNumber(ctx, patron.CardNumber).Return(patron, nil)
+
+	_, err = useCase.GetPatronByCardNumber(ctx, patron.CardNumber)
+	require.NoError(t, err)
+}
+
+func TestGetPatronByCardNumber(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		cardNumber     string
+		wantCardNumber string
+		wantErr        error
+	}{
+		{
+			name:           "get patron by card number",
+			cardNumber:     "29000000000007",
+			wantCardNumber: "29000000000007",
+		},
+		{
+			name:           "spaces and hyphens are dropped",
+			cardNumber:     " 2900-0000 0000-07 ",
+			wantCardNumber: "29000000000007",
+		},
+		{
+			name:       "wrong check digit",
+			cardNumber: "29000000000002",
+			wantErr:    entity.ErrInvalidCardNumber,
+		},
+		{
+			name:       "wrong prefix",
+			cardNumber: "19000000000002",
+			wantErr:    entity.ErrInvalidCardNumber,
+		},
+		{
+			name:       "too short",
+			cardNumber: "2900000000001",
+			wantErr:    entity.ErrInvalidCardNumber,
+		},
+		{
+			name:       "not digits",
+			cardNumber: "2900000000000a",
+			wantErr:    entity.ErrInvalidCardNumber,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockPatronRepo := mocks.NewMockPatronRepository(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{Patron: mockPatronRepo})
+			ctx := t.Context()
+
+			if tt.wantErr == nil {
+				mockPatronRepo.EXPECT().GetPatronByCardNumber(ctx, tt.wantCardNumber).
+					Return(&entity.Patron{CardNumber: tt.wantCardNumber}, nil)
+			}
+
+			_, err := useCase.GetPatronByCardNumber(ctx, tt.cardNumber)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestUpdatePatron(t *testing.T) {
+	t.Parallel()
+
+	patronID := uuid.NewString()
+
+	tests := []struct {
+		name       string
+		update     entity.PatronUpdate
+		wantUpdate entity.PatronUpdate
+		wantErr    error
+	}{
+		{
+			name: "contact details are normalized",
+			update: entity.PatronUpdate{
+				Name:  proto.String(" Ada  King "),
+				Email: proto.String(" Ada@Example.ORG"),
+				Phone: proto.String(" +44 20 7946 0001 "),
+			},
+			wantUpdate: entity.PatronUpdate{
+				Name:  proto.String("Ada King"),
+				Email: proto.String("ada@example.org"),
+				Phone: proto.String("+44 20 7946 0001"),
+			},
+		},
+		{
+			name:    "blank name",
+			update:  entity.PatronUpdate{Name: proto.String(" ")},
+			wantErr: entity.ErrInvalidPatronName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockPatronRepo := mocks.NewMockPatronRepository(ctrl)
+			logger, _ := zap.NewProduction()
+			useCase := library.New(logger, library.Repositories{Patron: mockPatronRepo})
+			ctx := t.Context()
+
+			if tt.wantErr == nil {
+				mockPatronRepo.EXPECT().UpdatePatron(ctx, patronID, tt.wantUpdate).
+					Return(&entity.Patron{ID: patronID}, nil)
+			}
+
+			_, err := useCase.UpdatePatron(ctx, patronID, tt.update)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBlockPatron(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockPatronRepo := mocks.NewMockPatronRepository(ctrl)
+	logger, _ := zap.NewProduction()
+	useCase := library.New(logger, library.Repositories{Patron: mockPatronRepo})
+	ctx := t.Context()
+	patronID := uuid.NewString()
+
+	gomock.InOrder(
+		mockPatronRepo.EXPECT().SetPatronStatus(ctx, patronID,
+			entity.PatronStatusBlocked, "unpaid fines").
+			Return(&entity.Patron{ID: patronID, Status: entity.PatronStatusBlocked}, nil),
+		mockPatronRepo.EXPECT().SetPatronStatus(ctx, patronID, entity.PatronStatusActive, "").
+			Return(&entity.Patron{ID: patronID, Status: entity.PatronStatusActive}, nil),
+	)
+
+	patron, err := useCase.BlockPatron(ctx, patronID, " unpaid fines ")
+	require.NoError(t, err)
+	assert.Equal(t, entity.PatronStatusBlocked, patron.Status)
+
+	patron, err = useCase.UnblockPatron(ctx, patronID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PatronStatusActive, patron.Status)
+}
+
+func TestPatronCheckInGoodStanding(t *testing.T) {
+	t.Parallel()
+
+	expiresOn := time.Date(2030, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		patron  entity.Patron
+		now     time.Time
+		wantErr error
+	}{
+		{
+			name:   "active member",
+			patron: entity.Patron{Status: entity.PatronStatusActive, MembershipExpiresOn: expiresOn},
+			now:    expiresOn.AddDate(0, -1, 0),
+		},
+		{
+			name:   "last day of membership",
+			patron: entity.Patron{Status: entity.PatronStatusActive, MembershipExpiresOn: expiresOn},
+			now:    expiresOn.Add(23 * time.Hour),
+		},
+		{
+			name:    "expired membership",
+			patron:  entity.Patron{Status: entity.PatronStatusActive, MembershipExpiresOn: expiresOn},
+			now:     expiresOn.AddDate(0, 0, 1),
+			wantErr: entity.ErrMembershipExpired,
+		},
+		{
+			name:    "blocked patron",
+			patron:  entity.Patron{Status: entity.PatronStatusBlocked, MembershipExpiresOn: expiresOn},
+			now:     expiresOn.AddDate(0, -1, 0),
+			wantErr: entity.ErrPatronBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.patron.CheckInGoodStanding(tt.now)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package library
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+	"github.com/project/library/internal/usecase/library"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func TestSearchCatalog(t *testing.T) {
+	t.Parallel()
+
+	results := []*entity.SearchResult{
+		{Kind: entity.SearchResultBook, ID: uuid.NewString(), Name: "Harry Potter", Score: 0.8},
+		{Kind: entity.SearchResultAuthor, ID: uuid.NewString(), Name: "Harry Harrison", Score: 0.4},
+		{Kind: entity.SearchResultBook, ID: uuid.NewString(), Name: "Dirty Harry", Score: 0.1},
+	}
+
+	t.Run("paginates by offset", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Books: mockBooksRepo})
+		ctx := t.Context()
+
+		mockBooksRepo.EXPECT().SearchCatalog(ctx, entity.SearchFilter{Query: "harry poter"}, 0, 3).
+			Return(results, nil)
+
+		page, nextPageToken, err := useCase.SearchCatalog(ctx, entity.SearchFilter{Query: " harry   poter "}, 2, "")
+		require.NoError(t, err)
+		assert.Equal(t, results[:2], page)
+		require.NotEmpty(t, nextPageToken)
+
+		mockBooksRepo.EXPECT().SearchCatalog(ctx, entity.SearchFilter{Query: "harry poter"}, 2, 3).
+			Return(results[2:], nil)
+
+		page, nextPageToken, err = useCase.SearchCatalog(ctx, entity.SearchFilter{Query: "harry poter"}, 2, nextPageToken)
+		require.NoError(t, err)
+		assert.Equal(t, results[2:], page)
+		assert.Empty(t, nextPageToken)
+	})
+
+	t.Run("page token of another query", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Books: mockBooksRepo})
+		ctx := t.Context()
+
+		mockBooksRepo.EXPECT().SearchCatalog(ctx, entity.SearchFilter{Query: "harry"}, 0, 2).
+			Return(results[:2], nil)
+
+		_, nextPageToken, err := useCase.SearchCatalog(ctx, entity.SearchFilter{Query: "harry"}, 1, "")
+		require.NoError(t, err)
+
+		_, _, err = useCase.SearchCatalog(ctx, entity.SearchFilter{Query: "potter"}, 1, nextPageToken)
+		require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+	})
+
+	t.Run("page token of another genre", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Books: mockBooksRepo})
+		ctx := t.Context()
+
+		filter := entity.SearchFilter{Query: "harry", GenreID: uuid.NewString()}
+		mockBooksRepo.EXPECT().SearchCatalog(ctx, filter, 0, 2).
+			Return(results[:2], nil)
+
+		_, nextPageToken, err := useCase.SearchCatalog(ctx, filter, 1, "")
+		require.NoError(t, err)
+
+		_, _, err = useCase.SearchCatalog(ctx, entity.SearchFilter{Query: "harry"}, 1, nextPageToken)
+		require.ErrorIs(t, err, entity.ErrInvalidPageToken)
+	})
+
+	t.Run("blank query", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Books: mockBooksRepo})
+
+		_, _, err := useCase.SearchCatalog(t.Context(), entity.SearchFilter{Query: " \t "}, 10, "")
+		require.ErrorIs(t, err, entity.ErrEmptySearchQuery)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		mockBooksRepo := mocks.NewMockBooksRepository(ctrl)
+		logger, _ := zap.NewProduction()
+		useCase := library.New(logger, library.Repositories{Books: mockBooksRepo})
+		ctx := t.Context()
+
+		mockBooksRepo.EXPECT().SearchCatalog(ctx, entity.SearchFilter{Query: "harry"}, 0, 51).
+			Return(nil, errors.New("error search catalog"))
+
+		_, _, err := useCase.SearchCatalog(ctx, entity.SearchFilter{Query: "harry"}, 0, "")
+		require.Error(t, err)
+	})
+}
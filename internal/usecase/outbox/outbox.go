@@ -2,6 +2,10 @@ package outbox
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
 	"time"
 
 	"github.com/project/library/internal/metrics"
@@ -15,10 +19,14 @@ import (
 )
 
 type GlobalHandler = func(kind repository.OutboxKind) (KindHandler, error)
-type KindHandler = func(ctx context.Context, data []byte) error
+type KindHandler = func(ctx context.Context, message repository.OutboxData) error
 
 var tracer = otel.Tracer("library-service")
 
+// maxLastErrorLen bounds the error kept with a failed message; handler
+// errors may carry whole response bodies.
+const maxLastErrorLen = 1024
+
 type Outbox interface {
 	Start(ctx context.Context, workers int, batchSize int,
 		waitTime time.Duration, inProgressTTL time.Duration)
@@ -61,114 +69,231 @@ func (o *outboxImpl) Start(
 	}
 }
 
+// worker claims a batch of messages in a short transaction and delivers
+// them outside of it, so that slow receivers hold no locks. Every message
+// is acknowledged on its own; one whose outcome couldn't be recorded is
+// claimed again once inProgressTTL passes. Deliveries of a batch end within
+// half of inProgressTTL, so that no message is delivered again while its
+// first delivery is still going on; the other half is left for the acks.
 func (o *outboxImpl) worker(
 	ctx context.Context,
 	batchSize int,
-	waitTIme time.Duration,
+	waitTime time.Duration,
 	inProgressTTL time.Duration,
 ) {
 	log := o.logger.With(
 		zap.String("layer", "outbox"))
+
+	timer := time.NewTimer(waitTime)
+	defer timer.Stop()
+
 	for {
-		time.Sleep(waitTIme)
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
 
-		if !o.cfg.Outbox.Enabled {
-			continue
+		if o.cfg.Outbox.Enabled {
+			o.processBatch(ctx, log, batchSize, inProgressTTL)
 		}
 
-		err := o.transactor.WithTx(ctx, func(ctx context.Context) error {
-			messages, err := o.outboxRepository.GetMessages(
-				ctx, batchSize, inProgressTTL)
-
-			if err != nil {
-				log.Error("can not fetch messages from outbox", zap.Error(err))
-				return err
-			}
-
-			successKeys := make([]string, 0, len(messages))
-
-			for _, message := range messages {
-				start := time.Now()
-				key := message.IdempotencyKey
-				kind := message.Kind.String()
-
-				traceID, parseErr := trace.TraceIDFromHex(message.TraceID)
-				if parseErr != nil {
-					log.Warn("invalid trace_id",
-						zap.String("trace_id", message.TraceID),
-						zap.Error(parseErr))
-				}
-
-				var eventCtx context.Context
-				if parseErr == nil {
-					parentSC := trace.NewSpanContext(trace.SpanContextConfig{
-						TraceID:    traceID,
-						SpanID:     trace.SpanID{},
-						TraceFlags: trace.FlagsSampled,
-						Remote:     true,
-					})
-					eventCtx = trace.ContextWithRemoteSpanContext(context.Background(), parentSC)
-				} else {
-					eventCtx = context.Background()
-				}
-
-				eventCtx, span := tracer.Start(eventCtx, "ProcessOutboxEvent")
-				span.SetAttributes(
-					attribute.String("outbox.id", message.IdempotencyKey),
-					attribute.String("outbox.kind", message.Kind.String()),
-				)
-
-				var kindHandler KindHandler
-				kindHandler, err = o.globalHandler(message.Kind)
-
-				if err != nil {
-					log.Error("unexpected kind",
-						zap.String("trace_id", traceID.String()),
-						zap.String("span_id", span.SpanContext().SpanID().String()),
-						zap.Error(err))
-					metrics.OutboxTasksFailed.WithLabelValues(kind).Inc()
-					span.End()
-					continue
-				}
-
-				err = kindHandler(ctx, message.RawData)
-
-				duration := time.Since(start).Seconds()
-				metrics.OutboxTaskProcessingDuration.WithLabelValues(kind).Observe(duration)
-
-				if err != nil {
-					log.Error("kind error",
-						zap.String("trace_id", traceID.String()),
-						zap.String("span_id", span.SpanContext().SpanID().String()),
-						zap.Error(err))
-					metrics.OutboxTasksFailed.WithLabelValues(kind).Inc()
-					span.End()
-					continue
-				}
-
-				o.logger.Info("outbox worker executing",
-					zap.String("trace_id", traceID.String()),
-					zap.String("span_id", span.SpanContext().SpanID().String()),
-					zap.String("kind", message.Kind.String()),
-					zap.String("idempotency_key", message.IdempotencyKey))
-
-				successKeys = append(successKeys, key)
-				metrics.OutboxTasksProcessed.WithLabelValues(kind).Inc()
-				span.End()
-			}
-
-			err = o.outboxRepository.MarkAsProcessed(ctx, successKeys)
-			if err != nil {
-				o.logger.Error("mark as processed outbox error", zap.Error(err))
-				return err
-			}
-
-			return nil
-		})
+		timer.Reset(waitTime)
+	}
+}
+
+func (o *outboxImpl) processBatch(
+	ctx context.Context,
+	log *zap.Logger,
+	batchSize int,
+	inProgressTTL time.Duration,
+) {
+	var messages []repository.OutboxData
+
+	getMessages := o.outboxRepository.GetMessages
+	if o.cfg.Outbox.Ordered {
+		getMessages = o.outboxRepository.GetOrderedMessages
+	}
+
+	err := o.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		messages, err = getMessages(ctx, batchSize, inProgressTTL)
+		return err
+	})
+
+	if err != nil {
+		log.Error("can not fetch messages from outbox", zap.Error(err))
+		return
+	}
+
+	deadline := time.Now().Add(inProgressTTL / 2)
 
-		if err != nil {
-			o.logger.Error("worker transaction error", zap.Error(err))
+	for _, message := range messages {
+		// The rest of the batch stays IN_PROGRESS and is claimed again
+		// after inProgressTTL.
+		if ctx.Err() != nil || !time.Now().Before(deadline) {
 			return
 		}
+
+		o.processMessage(ctx, log, message, deadline)
+	}
+}
+
+func (o *outboxImpl) processMessage(
+	ctx context.Context,
+	log *zap.Logger,
+	message repository.OutboxData,
+	deadline time.Time,
+) {
+	start := time.Now()
+	kind := message.Kind.String()
+
+	traceID, parseErr := trace.TraceIDFromHex(message.TraceID)
+	if parseErr != nil {
+		log.Warn("invalid trace_id",
+			zap.String("trace_id", message.TraceID),
+			zap.Error(parseErr))
+	}
+
+	eventCtx := ctx
+	if parseErr == nil {
+		parentSC := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     trace.SpanID{},
+			TraceFlags: trace.FlagsSampled,
+			Remote:     true,
+		})
+		eventCtx = trace.ContextWithRemoteSpanContext(ctx, parentSC)
+	}
+
+	eventCtx, span := tracer.Start(eventCtx, "ProcessOutboxEvent")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("outbox.id", message.IdempotencyKey),
+		attribute.String("outbox.kind", kind),
+	)
+
+	log = log.With(
+		zap.String("trace_id", traceID.String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+		zap.String("kind", kind),
+		zap.String("idempotency_key", message.IdempotencyKey))
+
+	// The outcome of a delivery is recorded even if the worker is being
+	// stopped meanwhile.
+	ackCtx := context.WithoutCancel(eventCtx)
+
+	kindHandler, err := o.globalHandler(message.Kind)
+	if err != nil {
+		log.Error("unexpected kind", zap.Error(err))
+		metrics.OutboxTasksFailed.WithLabelValues(kind).Inc()
+		if err = o.markFailed(ackCtx, message, err, false); err != nil {
+			logOutcomeError(log, "can not dead-letter outbox message", err)
+		}
+		return
 	}
+
+	deliveryCtx, cancel := context.WithDeadline(eventCtx, o.deliveryDeadline(start, deadline))
+	err = deliver(deliveryCtx, kindHandler, message)
+	cancel()
+
+	duration := time.Since(start).Seconds()
+	metrics.OutboxTaskProcessingDuration.WithLabelValues(kind).Observe(duration)
+
+	if err != nil {
+		log.Error("kind error", zap.Error(err))
+		span.RecordError(err)
+		metrics.OutboxTasksFailed.WithLabelValues(kind).Inc()
+		if err = o.markFailed(ackCtx, message, err, true); err != nil {
+			logOutcomeError(log, "can not mark outbox message as failed", err)
+		}
+		return
+	}
+
+	if err = o.outboxRepository.MarkAsProcessed(ackCtx, message.IdempotencyKey, message.Attempts); err != nil {
+		logOutcomeError(log, "mark as processed outbox error", err)
+		return
+	}
+
+	log.Info("outbox worker executing")
+	metrics.OutboxTasksProcessed.WithLabelValues(kind).Inc()
+}
+
+// deliveryDeadline is the delivery timeout from start, unless the batch
+// deadline comes earlier.
+func (o *outboxImpl) deliveryDeadline(start time.Time, batchDeadline time.Time) time.Time {
+	if timeout := o.cfg.Outbox.DeliveryTimeoutMS; timeout > 0 && start.Add(timeout).Before(batchDeadline) {
+		return start.Add(timeout)
+	}
+
+	return batchDeadline
+}
+
+// logOutcomeError logs a failure to record the outcome of a delivery. A
+// message reclaimed after inProgressTTL is expected to happen now and then:
+// the worker that holds it now records its outcome.
+func logOutcomeError(log *zap.Logger, msg string, err error) {
+	if errors.Is(err, repository.ErrOutboxMessageReclaimed) {
+		log.Warn(msg, zap.Error(err))
+		return
+	}
+
+	log.Error(msg, zap.Error(err))
+}
+
+// deliver runs the handler, turning its panic into an error so that one
+// bad message doesn't bring the worker down.
+func deliver(ctx context.Context, handler KindHandler, message repository.OutboxData) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("outbox handler panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, message)
+}
+
+// markFailed schedules a retry of the message or, once it's out of
+// attempts or can't succeed at all, moves it to dead letter.
+func (o *outboxImpl) markFailed(
+	ctx context.Context,
+	message repository.OutboxData,
+	cause error,
+	retryable bool,
+) error {
+	lastError := cause.Error()
+	if len(lastError) > maxLastErrorLen {
+		lastError = strings.ToValidUTF8(lastError[:maxLastErrorLen], "")
+	}
+
+	if !retryable || message.Attempts >= o.cfg.Outbox.MaxAttempts {
+		if err := o.outboxRepository.MarkAsDead(ctx, message.IdempotencyKey, message.Attempts, lastError); err != nil {
+			return err
+		}
+
+		o.logger.Warn("outbox message is dead-lettered",
+			zap.String("idempotency_key", message.IdempotencyKey),
+			zap.String("kind", message.Kind.String()),
+			zap.Int("attempts", message.Attempts))
+		metrics.OutboxTasksDeadLettered.WithLabelValues(message.Kind.String()).Inc()
+
+		return nil
+	}
+
+	retryIn := Backoff(message.Attempts, o.cfg.Outbox.BackoffBaseMS, o.cfg.Outbox.BackoffMaxMS)
+
+	return o.outboxRepository.MarkAsFailed(ctx, message.IdempotencyKey, message.Attempts, lastError, retryIn)
+}
+
+// Backoff doubles the delay with every attempt, up to maxDelay. Half of
+// the delay is random, so messages that failed together are retried
+// apart.
+func Backoff(attempt int, base time.Duration, maxDelay time.Duration) time.Duration {
+	delay := maxDelay
+	if shift := max(attempt-1, 0); shift < 63 && base <= maxDelay>>shift {
+		delay = base << shift
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
 }
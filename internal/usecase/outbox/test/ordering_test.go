@@ -0,0 +1,224 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/project/library/config"
+	"github.com/project/library/internal/usecase/outbox"
+	"github.com/project/library/internal/usecase/repository"
+)
+
+type memoryOutboxMessage struct {
+	repository.OutboxData
+	status        repository.OutboxStatus
+	nextAttemptAt time.Time
+}
+
+// memoryOutbox claims messages the way the ordered fetch of the postgres
+// repository does: a message is due only once every earlier message of its
+// aggregate is delivered.
+type memoryOutbox struct {
+	repository.OutboxRepository
+
+	mu       sync.Mutex
+	messages []*memoryOutboxMessage
+}
+
+func (m *memoryOutbox) GetOrderedMessages(
+	_ context.Context,
+	batchSize int,
+	_ time.Duration,
+) ([]repository.OutboxData, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	undelivered := make(map[string]bool)
+	claimed := make([]repository.OutboxData, 0, batchSize)
+	for _, message := range m.messages {
+		due := (message.status == repository.OutboxStatusCreated || message.status == repository.OutboxStatusFailed) &&
+			!message.nextAttemptAt.After(time.Now())
+
+		if due && !undelivered[message.AggregateKey] && len(claimed) < batchSize {
+			message.status = repository.OutboxStatusInProgress
+			message.Attempts++
+			claimed = append(claimed, message.OutboxData)
+		}
+
+		if message.status != repository.OutboxStatusSuccess {
+			undelivered[message.AggregateKey] = true
+		}
+	}
+
+	return claimed, nil
+}
+
+func (m *memoryOutbox) MarkAsProcessed(_ context.Context, idempotencyKey string, attempts int) error {
+	return m.update(idempotencyKey, attempts, func(message *memoryOutboxMessage) {
+		message.status = repository.OutboxStatusSuccess
+	})
+}
+
+func (m *memoryOutbox) MarkAsFailed(
+	_ context.Context,
+	idempotencyKey string,
+	attempts int,
+	_ string,
+	retryIn time.Duration,
+) error {
+	return m.update(idempotencyKey, attempts, func(message *memoryOutboxMessage) {
+		message.status = repository.OutboxStatusFailed
+		message.nextAttemptAt = time.Now().Add(retryIn)
+	})
+}
+
+func (m *memoryOutbox) MarkAsDead(_ context.Context, idempotencyKey string, attempts int, _ string) error {
+	return m.update(idempotencyKey, attempts, func(message *memoryOutboxMessage) {
+		message.status = repository.OutboxStatusDead
+	})
+}
+
+// update applies the outcome while the message is claimed with attempts.
+func (m *memoryOutbox) update(idempotencyKey string, attempts int, apply func(message *memoryOutboxMessage)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, message := range m.messages {
+		if message.IdempotencyKey == idempotencyKey &&
+			message.status == repository.OutboxStatusInProgress && message.Attempts == attempts {
+			apply(message)
+			return nil
+		}
+	}
+
+	return repository.ErrOutboxMessageReclaimed
+}
+
+func (m *memoryOutbox) delivered() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, message := range m.messages {
+		if message.status != repository.OutboxStatusSuccess {
+			return false
+		}
+	}
+
+	return true
+}
+
+type inlineTransactor struct{}
+
+func (inlineTransactor) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// receiver checks that every delivery of an aggregate is the one right
+// after the last it accepted, and that no two run at once.
+type receiver struct {
+	mu         sync.Mutex
+	accepted   map[string]int64
+	inFlight   map[string]bool
+	violations []string
+}
+
+func (r *receiver) handle(_ context.Context, message repository.OutboxData) error {
+	r.mu.Lock()
+	if r.inFlight[message.AggregateKey] {
+		r.violations = append(r.violations, fmt.Sprintf("%s: concurrent delivery of %d",
+			message.AggregateKey, message.Sequence))
+	}
+	if want := r.accepted[message.AggregateKey] + 1; message.Sequence != want {
+		r.violations = append(r.violations, fmt.Sprintf("%s: got %d, want %d",
+			message.AggregateKey, message.Sequence, want))
+	}
+	r.inFlight[message.AggregateKey] = true
+	r.mu.Unlock()
+
+	time.Sleep(time.Duration(rand.IntN(200)) * time.Microsecond)
+	failed := rand.IntN(5) == 0
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inFlight[message.AggregateKey] = false
+	if failed {
+		return errors.New("non success response: 503")
+	}
+	r.accepted[message.AggregateKey] = message.Sequence
+
+	return nil
+}
+
+func TestOrderedDeliveryWithManyWorkers(t *testing.T) {
+	t.Parallel()
+
+	const (
+		aggregates = 20
+		perAggr    = 30
+		workers    = 16
+	)
+
+	// The messages of the aggregates are interleaved, as they would be
+	// when written by concurrent requests.
+	repo := &memoryOutbox{}
+	for sequence := int64(1); sequence <= perAggr; sequence++ {
+		for aggregate := range aggregates {
+			aggregateKey := fmt.Sprintf("book-%d", aggregate)
+			repo.messages = append(repo.messages, &memoryOutboxMessage{
+				OutboxData: repository.OutboxData{
+					IdempotencyKey: fmt.Sprintf("book_updated_%s_%d", aggregateKey, sequence),
+					Kind:           repository.OutboxKindBookUpdated,
+					AggregateKey:   aggregateKey,
+					Sequence:       sequence,
+				},
+				status: repository.OutboxStatusCreated,
+			})
+		}
+	}
+
+	recv := &receiver{
+		accepted: make(map[string]int64),
+		inFlight: make(map[string]bool),
+	}
+	globalHandler := func(repository.OutboxKind) (outbox.KindHandler, error) {
+		return recv.handle, nil
+	}
+
+	cfg := &config.Config{
+		Outbox: config.Outbox{
+			Enabled:       true,
+			Ordered:       true,
+			MaxAttempts:   1000,
+			BackoffBaseMS: time.Millisecond,
+			BackoffMaxMS:  2 * time.Millisecond,
+		},
+	}
+
+	logger := zap.NewNop()
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	service := outbox.New(logger, repo, globalHandler, cfg, inlineTransactor{})
+	service.Start(ctx, workers, 4, time.Millisecond, time.Minute)
+
+	require.Eventually(t, repo.delivered, 20*time.Second, 5*time.Millisecond)
+	cancel()
+
+	recv.mu.Lock()
+	defer recv.mu.Unlock()
+
+	assert.Empty(t, recv.violations)
+	for aggregate := range aggregates {
+		assert.Equal(t, int64(perAggr), recv.accepted[fmt.Sprintf("book-%d", aggregate)])
+	}
+}
@@ -0,0 +1,323 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/project/library/config"
+	"github.com/project/library/internal/metrics"
+	"github.com/project/library/internal/usecase/outbox"
+	"github.com/project/library/internal/usecase/repository"
+	"github.com/project/library/internal/usecase/repository/mocks"
+)
+
+func newConfig() *config.Config {
+	return &config.Config{
+		Outbox: config.Outbox{
+			Enabled:       true,
+			MaxAttempts:   3,
+			BackoffBaseMS: time.Second,
+			BackoffMaxMS:  time.Minute,
+		},
+	}
+}
+
+func passThroughTx(mockTransactor *mocks.MockTransactor) {
+	mockTransactor.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		},
+	).AnyTimes()
+}
+
+func TestWorkerAcknowledgesEachMessage(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockTransactor := mocks.NewMockTransactor(ctrl)
+	logger, _ := zap.NewProduction()
+	passThroughTx(mockTransactor)
+
+	messages := []repository.OutboxData{
+		{IdempotencyKey: "ok", Kind: repository.OutboxKindBook, RawData: []byte("ok"), Attempts: 1},
+		{IdempotencyKey: "fail", Kind: repository.OutboxKindBook, RawData: []byte("fail"), Attempts: 1},
+		{IdempotencyKey: "panic", Kind: repository.OutboxKindBook, RawData: []byte("panic"), Attempts: 3},
+		{IdempotencyKey: "unknown", Kind: repository.OutboxKindUndefined, Attempts: 1},
+	}
+
+	handler := func(_ context.Context, message repository.OutboxData) error {
+		switch string(message.RawData) {
+		case "fail":
+			return errors.New("non success response: 503")
+		case "panic":
+			panic("boom")
+		default:
+			return nil
+		}
+	}
+	globalHandler := func(kind repository.OutboxKind) (outbox.KindHandler, error) {
+		if kind == repository.OutboxKindUndefined {
+			return nil, errors.New("unsupported outbox kind: 0")
+		}
+		return handler, nil
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+
+	gomock.InOrder(
+		mockOutboxRepo.EXPECT().GetMessages(gomock.Any(), 10, time.Minute).Return(messages, nil),
+		mockOutboxRepo.EXPECT().GetMessages(gomock.Any(), 10, time.Minute).
+			DoAndReturn(func(context.Context, int, time.Duration) ([]repository.OutboxData, error) {
+				cancel()
+				close(done)
+				return nil, nil
+			}),
+	)
+	// A failure to acknowledge one message doesn't affect the others.
+	mockOutboxRepo.EXPECT().MarkAsProcessed(gomock.Any(), "ok", 1).Return(errors.New("db error"))
+	mockOutboxRepo.EXPECT().MarkAsFailed(gomock.Any(), "fail", 1, "non success response: 503", gomock.Any()).
+		Return(repository.ErrOutboxMessageReclaimed)
+	mockOutboxRepo.EXPECT().MarkAsDead(gomock.Any(), "panic", 3, "outbox handler panicked: boom").Return(nil)
+	mockOutboxRepo.EXPECT().MarkAsDead(gomock.Any(), "unknown", 1, "unsupported outbox kind: 0").Return(nil)
+
+	service := outbox.New(logger, mockOutboxRepo, globalHandler, newConfig(), mockTransactor)
+	service.Start(ctx, 1, 10, time.Millisecond, time.Minute)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker didn't fetch the second batch")
+	}
+}
+
+func TestWorkerRecoversFromFetchError(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockTransactor := mocks.NewMockTransactor(ctrl)
+	logger, _ := zap.NewProduction()
+	passThroughTx(mockTransactor)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+
+	gomock.InOrder(
+		mockOutboxRepo.EXPECT().GetMessages(gomock.Any(), 10, time.Minute).
+			Return(nil, errors.New("connection reset")).Times(2),
+		mockOutboxRepo.EXPECT().GetMessages(gomock.Any(), 10, time.Minute).
+			DoAndReturn(func(context.Context, int, time.Duration) ([]repository.OutboxData, error) {
+				cancel()
+				close(done)
+				return nil, nil
+			}),
+	)
+
+	service := outbox.New(logger, mockOutboxRepo, nil, newConfig(), mockTransactor)
+	service.Start(ctx, 1, 10, time.Millisecond, time.Minute)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker stopped after a fetch error")
+	}
+}
+
+func TestWorkerStopsOnCancel(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockTransactor := mocks.NewMockTransactor(ctrl)
+	logger, _ := zap.NewProduction()
+	passThroughTx(mockTransactor)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+
+	messages := []repository.OutboxData{
+		{IdempotencyKey: "first", Kind: repository.OutboxKindBook},
+		{IdempotencyKey: "second", Kind: repository.OutboxKindBook},
+	}
+
+	// The worker is stopped while delivering the first message: its
+	// outcome is still recorded, the second message is left for another
+	// worker, and nothing is fetched any more.
+	mockOutboxRepo.EXPECT().GetMessages(gomock.Any(), 10, time.Minute).Return(messages, nil)
+	mockOutboxRepo.EXPECT().MarkAsProcessed(gomock.Any(), "first", 0).
+		DoAndReturn(func(ctx context.Context, _ string, _ int) error {
+			assert.NoError(t, ctx.Err())
+			close(done)
+			return nil
+		})
+
+	globalHandler := func(repository.OutboxKind) (outbox.KindHandler, error) {
+		return func(context.Context, repository.OutboxData) error {
+			cancel()
+			return nil
+		}, nil
+	}
+
+	service := outbox.New(logger, mockOutboxRepo, globalHandler, newConfig(), mockTransactor)
+	service.Start(ctx, 1, 10, time.Millisecond, time.Minute)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker didn't acknowledge the message")
+	}
+
+	// Any further fetch would be an unexpected call.
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestWorkerBoundsDeliveries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		deliveryTimeout time.Duration
+		inProgressTTL   time.Duration
+		wantNext        bool
+	}{
+		{
+			name:            "delivery timeout",
+			deliveryTimeout: 20 * time.Millisecond,
+			inProgressTTL:   time.Minute,
+			wantNext:        true,
+		},
+		{
+			// A batch is delivered within half of the TTL, so the message
+			// left is not delivered until the batch is claimed again.
+			name:            "in progress TTL",
+			deliveryTimeout: time.Hour,
+			inProgressTTL:   40 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			logger, _ := zap.NewProduction()
+			passThroughTx(mockTransactor)
+
+			cfg := newConfig()
+			cfg.Outbox.DeliveryTimeoutMS = tt.deliveryTimeout
+
+			messages := []repository.OutboxData{
+				{IdempotencyKey: "slow", Kind: repository.OutboxKindBook, RawData: []byte("slow"), Attempts: 1},
+				{IdempotencyKey: "next", Kind: repository.OutboxKindBook, RawData: []byte("next"), Attempts: 1},
+			}
+
+			globalHandler := func(repository.OutboxKind) (outbox.KindHandler, error) {
+				return func(ctx context.Context, message repository.OutboxData) error {
+					if string(message.RawData) == "next" {
+						return nil
+					}
+
+					<-ctx.Done()
+					return ctx.Err()
+				}, nil
+			}
+
+			ctx, cancel := context.WithCancel(t.Context())
+			done := make(chan struct{})
+
+			gomock.InOrder(
+				mockOutboxRepo.EXPECT().GetMessages(gomock.Any(), 10, tt.inProgressTTL).Return(messages, nil),
+				mockOutboxRepo.EXPECT().MarkAsFailed(gomock.Any(), "slow", 1, "context deadline exceeded", gomock.Any()).
+					Return(nil),
+				mockOutboxRepo.EXPECT().GetMessages(gomock.Any(), 10, tt.inProgressTTL).
+					DoAndReturn(func(context.Context, int, time.Duration) ([]repository.OutboxData, error) {
+						cancel()
+						close(done)
+						return nil, nil
+					}),
+			)
+			if tt.wantNext {
+				mockOutboxRepo.EXPECT().MarkAsProcessed(gomock.Any(), "next", 1).Return(nil)
+			}
+
+			service := outbox.New(logger, mockOutboxRepo, globalHandler, cfg, mockTransactor)
+			service.Start(ctx, 1, 10, time.Millisecond, tt.inProgressTTL)
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("delivery wasn't cancelled")
+			}
+		})
+	}
+}
+
+func TestWorkerCountsRecordedDeadLetters(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockTransactor := mocks.NewMockTransactor(ctrl)
+	logger, _ := zap.NewProduction()
+	passThroughTx(mockTransactor)
+
+	// The other tests dead-letter book messages only, so the author
+	// counter is left to this one.
+	deadLettered := metrics.OutboxTasksDeadLettered.WithLabelValues(repository.OutboxKindAuthor.String())
+	before := testutil.ToFloat64(deadLettered)
+
+	messages := []repository.OutboxData{
+		{IdempotencyKey: "reclaimed", Kind: repository.OutboxKindAuthor, Attempts: 3},
+		{IdempotencyKey: "dead", Kind: repository.OutboxKindAuthor, Attempts: 3},
+	}
+
+	globalHandler := func(repository.OutboxKind) (outbox.KindHandler, error) {
+		return func(context.Context, repository.OutboxData) error {
+			return errors.New("non success response: 503")
+		}, nil
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+
+	gomock.InOrder(
+		mockOutboxRepo.EXPECT().GetMessages(gomock.Any(), 10, time.Minute).Return(messages, nil),
+		mockOutboxRepo.EXPECT().GetMessages(gomock.Any(), 10, time.Minute).
+			DoAndReturn(func(context.Context, int, time.Duration) ([]repository.OutboxData, error) {
+				cancel()
+				close(done)
+				return nil, nil
+			}),
+	)
+	// A message reclaimed by another worker isn't dead-lettered by this one.
+	mockOutboxRepo.EXPECT().MarkAsDead(gomock.Any(), "reclaimed", 3, "non success response: 503").
+		Return(repository.ErrOutboxMessageReclaimed)
+	mockOutboxRepo.EXPECT().MarkAsDead(gomock.Any(), "dead", 3, "non success response: 503").Return(nil)
+
+	service := outbox.New(logger, mockOutboxRepo, globalHandler, newConfig(), mockTransactor)
+	service.Start(ctx, 1, 10, time.Millisecond, time.Minute)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker didn't fetch the second batch")
+	}
+
+	assert.InDelta(t, before+1, testutil.ToFloat64(deadLettered), 0)
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+var _ CatalogChangeRepository = (*catalogChangeRepository)(nil)
+var _ CatalogNotifier = (*catalogNotifier)(nil)
+
+// catalogChangeChannel is notified by the trigger on catalog_change.
+const catalogChangeChannel = "catalog_change"
+
+const catalogListenRetryInterval = time.Second
+
+// catalogChangesInjector carries the changes a transaction made to the
+// catalog until it commits.
+type catalogChangesInjector struct{}
+
+type pendingCatalogChanges struct {
+	changes []*CatalogChange
+}
+
+// addCatalogChange appends the change to the log when the transaction in
+// ctx commits, or right away outside of Transactor.WithTx.
+func addCatalogChange(ctx context.Context, conn PgxIface, change *CatalogChange) error {
+	if pending, ok := ctx.Value(catalogChangesInjector{}).(*pendingCatalogChanges); ok {
+		pending.changes = append(pending.changes, change)
+		return nil
+	}
+
+	return appendCatalogChanges(ctx, conn, []*CatalogChange{change})
+}
+
+// appendCatalogChanges appends the changes to the log. The position row
+// stays locked until the commit, which keeps the positions in the commit
+// order, so it must be the last lock the transaction takes: one taken
+// after it would deadlock with a transaction holding that lock and
+// waiting for the position.
+func appendCatalogChanges(ctx context.Context, conn PgxIface, changes []*CatalogChange) error {
+	const query = `
+WITH next AS (
+    UPDATE catalog_change_position
+    SET position = position + 1
+    RETURNING position
+)
+INSERT INTO catalog_change (position, kind, entity_id, data, trace_id)
+SELECT position, $1, $2, $3, $4
+FROM next;
+`
+
+	for _, change := range changes {
+		_, err := conn.Exec(ctx, query, change.Kind, change.EntityID, change.RawData, change.TraceID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type catalogChangeRepository struct {
+	db     PgxIface
+	logger *zap.Logger
+}
+
+func NewCatalogChanges(db PgxIface, logger *zap.Logger) *catalogChangeRepository {
+	return &catalogChangeRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListChanges returns up to limit changes following the position after.
+func (c *catalogChangeRepository) ListChanges(
+	ctx context.Context,
+	after int64,
+	limit int,
+) ([]*CatalogChange, error) {
+	const query = `
+SELECT position, kind, entity_id, data, COALESCE(trace_id, ''), created_at
+FROM catalog_change
+WHERE position > $1
+ORDER BY position
+LIMIT $2`
+
+	rows, err := c.db.Query(ctx, query, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	result := make([]*CatalogChange, 0)
+
+	for rows.Next() {
+		var change CatalogChange
+
+		if err := rows.Scan(&change.Position, &change.Kind, &change.EntityID, &change.RawData,
+			&change.TraceID, &change.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		result = append(result, &change)
+	}
+
+	return result, rows.Err()
+}
+
+// catalogNotifier listens to the catalog change notifications on a
+// connection taken out of the pool and passes them on to the subscribers.
+type catalogNotifier struct {
+	pool   *pgxpool.Pool
+	logger *zap.Logger
+
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func NewCatalogNotifier(pool *pgxpool.Pool, logger *zap.Logger) *catalogNotifier {
+	return &catalogNotifier{
+		pool:        pool,
+		logger:      logger,
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe returns a channel that receives a value after changes are
+// committed, and the function that stops it. Wake-ups that come while
+// the subscriber is busy are merged into one.
+func (n *catalogNotifier) Subscribe() (<-chan struct{}, func()) {
+	wakeUp := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	n.subscribers[wakeUp] = struct{}{}
+	n.mu.Unlock()
+
+	return wakeUp, func() {
+		n.mu.Lock()
+		delete(n.subscribers, wakeUp)
+		n.mu.Unlock()
+	}
+}
+
+// Run listens until ctx is done, reconnecting when the connection is lost.
+func (n *catalogNotifier) Run(ctx context.Context) {
+	for {
+		err := n.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		n.logger.Warn("catalog change listener is lost", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(catalogListenRetryInterval):
+		}
+	}
+}
+
+func (n *catalogNotifier) listen(ctx context.Context) error {
+	pooled, err := n.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The connection keeps listening until it's closed, so it doesn't go
+	// back to the pool.
+	conn := pooled.Hijack()
+	defer func() {
+		if err := conn.Close(context.Background()); err != nil {
+			n.logger.Warn("can not close catalog change listener", zap.Error(err))
+		}
+	}()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+catalogChangeChannel); err != nil {
+		return err
+	}
+
+	// Changes committed while nobody was listening are picked up now.
+	n.broadcast()
+
+	for {
+		if _, err = conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+
+		n.broadcast()
+	}
+}
+
+func (n *catalogNotifier) broadcast() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for wakeUp := range n.subscribers {
+		select {
+		case wakeUp <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+)
+
+const copyColumns = `id, book_id, barcode, branch, shelf_location, condition, acquired_on,
+	status, created_at, updated_at`
+
+const copyBarcodeIndex = "idx_copy_barcode"
+
+func (p *postgresRepository) AddCopy(
+	ctx context.Context,
+	bookCopy *entity.Copy,
+) (*entity.Copy, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("book_id", bookCopy.BookID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start AddCopy")
+
+	// Copies can't be added to a deleted book, so the book row is read
+	// rather than relying on the foreign key alone.
+	const insertCopy = `
+INSERT INTO copy (book_id, barcode, branch, shelf_location, condition, acquired_on, status)
+SELECT id, $2, $3, $4, $5, $6, $7
+FROM book
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING ` + copyColumns + `;
+`
+
+	var created *entity.Copy
+	err := measureQueryLatency("insert_copy", func() error {
+		var scanErr error
+		created, scanErr = scanCopy(p.conn(ctx).QueryRow(ctx, insertCopy,
+			bookCopy.BookID, bookCopy.Barcode, bookCopy.Branch, bookCopy.ShelfLocation,
+			bookCopy.Condition, bookCopy.AcquiredOn, bookCopy.Status))
+		return scanErr
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrBookNotFound, span)
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetCopy(
+	ctx context.Context,
+	copyID string,
+) (*entity.Copy, error) {
+	const getCopy = `
+SELECT ` + copyColumns + `
+FROM copy
+WHERE id = $1;
+`
+
+	return p.changeCopy(ctx, "get_copy", getCopy, copyID)
+}
+
+func (p *postgresRepository) GetCopyForUpdate(
+	ctx context.Context,
+	copyID string,
+) (*entity.Copy, error) {
+	const getCopyForUpdate = `
+SELECT ` + copyColumns + `
+FROM copy
+WHERE id = $1
+FOR UPDATE;
+`
+
+	return p.changeCopy(ctx, "get_copy_for_update", getCopyForUpdate, copyID)
+}
+
+func (p *postgresRepository) UpdateCopy(
+	ctx context.Context,
+	copyID string,
+	update entity.CopyUpdate,
+) (*entity.Copy, error) {
+	const updateCopy = `
+UPDATE copy SET
+	branch = COALESCE($2, branch),
+	shelf_location = COALESCE($3, shelf_location),
+	condition = COALESCE($4, condition),
+	acquired_on = CASE WHEN $5::boolean THEN $6::date ELSE acquired_on END,
+	status = COALESCE($7, status)
+WHERE id = $1
+RETURNING ` + copyColumns + `;
+`
+
+	return p.changeCopy(ctx, "update_copy", updateCopy, copyID,
+		update.Branch, update.ShelfLocation, update.Condition,
+		update.SetAcquiredOn, update.AcquiredOn, update.Status)
+}
+
+func (p *postgresRepository) DeleteCopy(
+	ctx context.Context,
+	copyID string,
+) (*entity.Copy, error) {
+	const deleteCopy = `
+DELETE FROM copy
+WHERE id = $1
+RETURNING ` + copyColumns + `;
+`
+
+	return p.changeCopy(ctx, "delete_copy", deleteCopy, copyID)
+}
+
+// changeCopy runs a query that returns the copy with the given id.
+func (p *postgresRepository) changeCopy(
+	ctx context.Context,
+	operation string,
+	query string,
+	copyID string,
+	args ...any,
+) (*entity.Copy, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("copy_id", copyID),
+		zap.String("operation", operation),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start changeCopy")
+
+	var bookCopy *entity.Copy
+	err := measureQueryLatency(operation, func() error {
+		var err error
+		bookCopy, err = scanCopy(p.conn(ctx).QueryRow(ctx, query, append([]any{copyID}, args...)...))
+		return err
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrCopyNotFound, span)
+	}
+
+	return bookCopy, nil
+}
+
+func (p *postgresRepository) ListCopies(
+	ctx context.Context,
+	bookID string,
+	filter entity.CopiesFilter,
+) ([]*entity.Copy, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("book_id", bookID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start ListCopies")
+
+	const listCopies = `
+SELECT ` + copyColumns + `
+FROM copy
+WHERE book_id = $1
+  AND ($2 = '' OR branch = $2)
+  AND ($3 = '' OR status = $3)
+ORDER BY branch, barcode;
+`
+
+	var rows pgx.Rows
+	err := measureQueryLatency("list_copies", func() error {
+		var err error
+		rows, err = p.conn(ctx).Query(ctx, listCopies, bookID, filter.Branch, string(filter.Status))
+		return err
+	})
+	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+	defer rows.Close()
+
+	copies := make([]*entity.Copy, 0)
+	for rows.Next() {
+		bookCopy, err := scanCopy(rows)
+		if err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+
+		copies = append(copies, bookCopy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	if len(copies) == 0 {
+		if err = p.checkBookExists(ctx, bookID); err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+	}
+
+	return copies, nil
+}
+
+func (p *postgresRepository) GetBookAvailability(
+	ctx context.Context,
+	bookID string,
+) (*entity.BookAvailability, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("book_id", bookID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start GetBookAvailability")
+
+	const getBookAvailability = `
+SELECT
+	count(*),
+	count(*) FILTER (WHERE status = 'available'),
+	count(*) FILTER (WHERE status = 'on_loan'),
+	count(*) FILTER (WHERE status = 'on_hold'),
+	count(*) FILTER (WHERE status = 'lost'),
+	count(*) FILTER (WHERE status = 'in_repair')
+FROM copy
+WHERE book_id = $1;
+`
+
+	var availability entity.BookAvailability
+	err := measureQueryLatency("get_book_availability", func() error {
+		return p.conn(ctx).QueryRow(ctx, getBookAvailability, bookID).Scan(
+			&availability.Total, &availability.Available, &availability.OnLoan,
+			&availability.OnHold, &availability.Lost, &availability.InRepair)
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	return &availability, nil
+}
+
+// checkBookExists tells an unknown book from a book without copies.
+func (p *postgresRepository) checkBookExists(ctx context.Context, bookID string) error {
+	const bookExists = `SELECT EXISTS (SELECT 1 FROM book WHERE id = $1 AND deleted_at IS NULL);`
+
+	var found bool
+	if err := p.conn(ctx).QueryRow(ctx, bookExists, bookID).Scan(&found); err != nil {
+		return err
+	}
+
+	if !found {
+		return entity.ErrBookNotFound
+	}
+
+	return nil
+}
+
+func scanCopy(row pgx.Row) (*entity.Copy, error) {
+	var bookCopy entity.Copy
+
+	if err := row.Scan(&bookCopy.ID, &bookCopy.BookID, &bookCopy.Barcode, &bookCopy.Branch,
+		&bookCopy.ShelfLocation, &bookCopy.Condition, &bookCopy.AcquiredOn, &bookCopy.Status,
+		&bookCopy.CreatedAt, &bookCopy.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &bookCopy, nil
+}
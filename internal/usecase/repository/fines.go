@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+)
+
+const fineColumns = `id, loan_id, patron_id, amount, paid, accrued_on, created_at, updated_at`
+
+// UpsertFine records the fine of the loan. The amount never goes down, so
+// renewing an overdue loan doesn't waive the fine accrued so far.
+func (p *postgresRepository) UpsertFine(
+	ctx context.Context,
+	fine *entity.Fine,
+) (*entity.Fine, error) {
+	const upsertFine = `
+INSERT INTO fine (loan_id, patron_id, amount, accrued_on)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (loan_id) DO UPDATE SET
+	amount = GREATEST(fine.amount, EXCLUDED.amount),
+	accrued_on = EXCLUDED.accrued_on
+RETURNING ` + fineColumns + `;
+`
+
+	return p.queryFine(ctx, "upsert_fine", upsertFine,
+		fine.LoanID, fine.PatronID, fine.Amount, fine.AccruedOn)
+}
+
+func (p *postgresRepository) GetFineForUpdate(
+	ctx context.Context,
+	fineID string,
+) (*entity.Fine, error) {
+	const getFineForUpdate = `
+SELECT ` + fineColumns + `
+FROM fine
+WHERE id = $1
+FOR UPDATE;
+`
+
+	return p.queryFine(ctx, "get_fine_for_update", getFineForUpdate, fineID)
+}
+
+func (p *postgresRepository) PayFine(
+	ctx context.Context,
+	fineID string,
+	amount int64,
+) (*entity.Fine, error) {
+	const payFine = `
+UPDATE fine SET
+	paid = paid + $2
+WHERE id = $1
+RETURNING ` + fineColumns + `;
+`
+
+	return p.queryFine(ctx, "pay_fine", payFine, fineID, amount)
+}
+
+func (p *postgresRepository) ListOutstandingFines(
+	ctx context.Context,
+	patronID string,
+) ([]*entity.Fine, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("patron_id", patronID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start ListOutstandingFines")
+
+	const listOutstandingFines = `
+SELECT ` + fineColumns + `
+FROM fine
+WHERE patron_id = $1 AND paid < amount
+ORDER BY created_at, id;
+`
+
+	var rows pgx.Rows
+	err := measureQueryLatency("list_outstanding_fines", func() error {
+		var err error
+		rows, err = p.conn(ctx).Query(ctx, listOutstandingFines, patronID)
+		return err
+	})
+	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+	defer rows.Close()
+
+	fines := make([]*entity.Fine, 0)
+	for rows.Next() {
+		fine, err := scanFine(rows)
+		if err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+
+		fines = append(fines, fine)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	return fines, nil
+}
+
+// queryFine runs a query that returns a single fine.
+func (p *postgresRepository) queryFine(
+	ctx context.Context,
+	operation string,
+	query string,
+	args ...any,
+) (*entity.Fine, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("operation", operation),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start queryFine")
+
+	var fine *entity.Fine
+	err := measureQueryLatency(operation, func() error {
+		var err error
+		fine, err = scanFine(p.conn(ctx).QueryRow(ctx, query, args...))
+		return err
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrFineNotFound, span)
+	}
+
+	return fine, nil
+}
+
+func scanFine(row pgx.Row) (*entity.Fine, error) {
+	var fine entity.Fine
+
+	if err := row.Scan(&fine.ID, &fine.LoanID, &fine.PatronID, &fine.Amount, &fine.Paid,
+		&fine.AccruedOn, &fine.CreatedAt, &fine.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &fine, nil
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+)
+
+const genreColumns = `id, name, parent_id, created_at`
+
+const genreParentNameIndex = "idx_genre_parent_name"
+
+// genreTreeLock serializes moves within the taxonomy, so that two
+// concurrent moves can't close a cycle that neither of them sees alone.
+const genreTreeLock = `SELECT pg_advisory_xact_lock(hashtext('genre_tree'));`
+
+func (p *postgresRepository) CreateGenre(
+	ctx context.Context,
+	genre *entity.Genre,
+) (*entity.Genre, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start CreateGenre")
+
+	const insertGenre = `
+INSERT INTO genre (name, parent_id)
+VALUES ($1, $2)
+RETURNING ` + genreColumns + `;
+`
+
+	var created *entity.Genre
+	err := measureQueryLatency("insert_genre", func() error {
+		var scanErr error
+		created, scanErr = scanGenre(p.conn(ctx).QueryRow(ctx, insertGenre,
+			genre.Name, optionalUUID(genre.ParentID)))
+		return scanErr
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrGenreNotFound, span)
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetGenre(
+	ctx context.Context,
+	genreID string,
+) (*entity.Genre, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("genre_id", genreID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start GetGenre")
+
+	const getGenre = `
+SELECT ` + genreColumns + `
+FROM genre
+WHERE id = $1;
+`
+
+	var genre *entity.Genre
+	err := measureQueryLatency("get_genre", func() error {
+		var scanErr error
+		genre, scanErr = scanGenre(p.conn(ctx).QueryRow(ctx, getGenre, genreID))
+		return scanErr
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrGenreNotFound, span)
+	}
+
+	return genre, nil
+}
+
+func (p *postgresRepository) UpdateGenre(
+	ctx context.Context,
+	genreID string,
+	update entity.GenreUpdate,
+) (respGenre *entity.Genre, txErr error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("genre_id", genreID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start UpdateGenre")
+
+	tx, rollback, err := p.beginTx(ctx)
+	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+	defer rollback(txErr)
+
+	if update.ParentID != nil && *update.ParentID != "" {
+		if err = p.checkGenreMove(ctx, tx, genreID, *update.ParentID); err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+	}
+
+	const updateGenre = `
+UPDATE genre SET
+	name = COALESCE($2, name),
+	parent_id = CASE WHEN $3::boolean THEN $4::uuid ELSE parent_id END
+WHERE id = $1
+RETURNING ` + genreColumns + `;
+`
+
+	var parentID *string
+	if update.ParentID != nil {
+		parentID = optionalUUID(*update.ParentID)
+	}
+
+	var genre *entity.Genre
+	err = measureQueryLatency("update_genre", func() error {
+		var scanErr error
+		genre, scanErr = scanGenre(tx.QueryRow(ctx, updateGenre, genreID,
+			update.Name, update.ParentID != nil, parentID))
+		return scanErr
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrGenreNotFound, span)
+	}
+
+	return genre, nil
+}
+
+// checkGenreMove rejects moving a genre under itself or its descendant.
+func (p *postgresRepository) checkGenreMove(
+	ctx context.Context,
+	tx pgx.Tx,
+	genreID string,
+	parentID string,
+) error {
+	if _, err := tx.Exec(ctx, genreTreeLock); err != nil {
+		return err
+	}
+
+	const isAncestor = `
+WITH RECURSIVE ancestors AS (
+	SELECT id, parent_id FROM genre WHERE id = $1
+	UNION ALL
+	SELECT genre.id, genre.parent_id FROM genre JOIN ancestors ON genre.id = ancestors.parent_id
+)
+SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2);
+`
+
+	var cycle bool
+	if err := tx.QueryRow(ctx, isAncestor, parentID, genreID).Scan(&cycle); err != nil {
+		return err
+	}
+
+	if cycle {
+		return entity.ErrGenreCycle
+	}
+
+	return nil
+}
+
+func (p *postgresRepository) DeleteGenre(
+	ctx context.Context,
+	genreID string,
+) error {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("genre_id", genreID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start DeleteGenre")
+
+	const deleteGenre = `
+DELETE FROM genre
+WHERE id = $1
+RETURNING id;
+`
+
+	err := measureQueryLatency("delete_genre", func() error {
+		var id string
+		return p.conn(ctx).QueryRow(ctx, deleteGenre, genreID).Scan(&id)
+	})
+
+	// Subgenres and book assignments restrict the delete.
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return mapPostgresError(entity.ErrGenreInUse, entity.ErrGenreInUse, span)
+	}
+
+	if err != nil {
+		return mapPostgresError(err, entity.ErrGenreNotFound, span)
+	}
+
+	return nil
+}
+
+func (p *postgresRepository) ListGenres(
+	ctx context.Context,
+	rootID string,
+) ([]*entity.Genre, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("root_id", rootID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start ListGenres")
+
+	listGenres := `
+SELECT ` + genreColumns + `
+FROM genre
+ORDER BY lower(name), id;
+`
+	args := make([]any, 0, 1)
+
+	if rootID != "" {
+		listGenres = fmt.Sprintf(`
+SELECT %s
+FROM genre
+WHERE id IN (%s)
+ORDER BY lower(name), id;
+`, genreColumns, fmt.Sprintf(genreSubtree, "$1"))
+		args = append(args, rootID)
+	}
+
+	var rows pgx.Rows
+	err := measureQueryLatency("list_genres", func() error {
+		var err error
+		rows, err = p.conn(ctx).Query(ctx, listGenres, args...)
+		return err
+	})
+	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+	defer rows.Close()
+
+	genres := make([]*entity.Genre, 0)
+	for rows.Next() {
+		genre, err := scanGenre(rows)
+		if err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+
+		genres = append(genres, genre)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	if rootID != "" && len(genres) == 0 {
+		return nil, mapPostgresError(entity.ErrGenreNotFound, entity.ErrGenreNotFound, span)
+	}
+
+	return genres, nil
+}
+
+func scanGenre(row pgx.Row) (*entity.Genre, error) {
+	var genre entity.Genre
+	var parentID *string
+
+	if err := row.Scan(&genre.ID, &genre.Name, &parentID, &genre.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if parentID != nil {
+		genre.ParentID = *parentID
+	}
+
+	return &genre, nil
+}
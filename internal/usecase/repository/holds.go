@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+)
+
+// holdColumns computes the queue position of waiting holds. Subqueries in
+// RETURNING don't see the row being inserted, so only older holds are
+// counted.
+const holdColumns = `hold.id, hold.book_id, hold.patron_id, COALESCE(hold.copy_id::text, ''),
+	hold.status,
+	CASE WHEN hold.status = 'waiting' THEN 1 + (
+		SELECT count(*)
+		FROM hold queued
+		WHERE queued.book_id = hold.book_id
+		  AND queued.status = 'waiting'
+		  AND (queued.placed_at, queued.id) < (hold.placed_at, hold.id)
+	) ELSE 0 END,
+	hold.placed_at, hold.ready_at, hold.expires_on, hold.created_at, hold.updated_at`
+
+const holdOpenPatronBookIndex = "idx_hold_open_patron_book"
+
+func (p *postgresRepository) PlaceHold(
+	ctx context.Context,
+	hold *entity.Hold,
+) (*entity.Hold, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("book_id", hold.BookID),
+		zap.String("patron_id", hold.PatronID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start PlaceHold")
+
+	const insertHold = `
+INSERT INTO hold (book_id, patron_id)
+VALUES ($1, $2)
+RETURNING ` + holdColumns + `;
+`
+
+	var created *entity.Hold
+	err := measureQueryLatency("insert_hold", func() error {
+		var err error
+		created, err = scanHold(p.conn(ctx).QueryRow(ctx, insertHold, hold.BookID, hold.PatronID))
+		return err
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrBookNotFound, span)
+	}
+
+	return created, nil
+}
+
+// LockHoldQueue locks the book row, so the hold queue of the book and
+// the availability of its copies change one transaction at a time.
+func (p *postgresRepository) LockHoldQueue(
+	ctx context.Context,
+	bookID string,
+) error {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("book_id", bookID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start LockHoldQueue")
+
+	const lockBook = `SELECT id FROM book WHERE id = $1 FOR UPDATE;`
+
+	err := measureQueryLatency("lock_hold_queue", func() error {
+		var id string
+		return p.conn(ctx).QueryRow(ctx, lockBook, bookID).Scan(&id)
+	})
+
+	if err != nil {
+		return mapPostgresError(err, entity.ErrBookNotFound, span)
+	}
+
+	return nil
+}
+
+func (p *postgresRepository) GetHold(
+	ctx context.Context,
+	holdID string,
+) (*entity.Hold, error) {
+	const getHold = `
+SELECT ` + holdColumns + `
+FROM hold
+WHERE id = $1;
+`
+
+	return p.queryHold(ctx, "get_hold", getHold, holdID)
+}
+
+func (p *postgresRepository) GetHoldForUpdate(
+	ctx context.Context,
+	holdID string,
+) (*entity.Hold, error) {
+	const getHoldForUpdate = `
+SELECT ` + holdColumns + `
+FROM hold
+WHERE id = $1
+FOR UPDATE;
+`
+
+	return p.queryHold(ctx, "get_hold_for_update", getHoldForUpdate, holdID)
+}
+
+// GetReadyHoldForCopy returns the ready hold the copy is set aside for.
+func (p *postgresRepository) GetReadyHoldForCopy(
+	ctx context.Context,
+	copyID string,
+) (*entity.Hold, error) {
+	const getReadyHoldForCopy = `
+SELECT ` + holdColumns + `
+FROM hold
+WHERE copy_id = $1 AND status = 'ready'
+FOR UPDATE;
+`
+
+	return p.queryHold(ctx, "get_ready_hold_for_copy", getReadyHoldForCopy, copyID)
+}
+
+// GetNextWaitingHold returns the oldest waiting hold of the book. Holds
+// locked by a concurrent transaction are skipped, that transaction is
+// about to serve them.
+func (p *postgresRepository) GetNextWaitingHold(
+	ctx context.Context,
+	bookID string,
+) (*entity.Hold, error) {
+	const getNextWaitingHold = `
+SELECT ` + holdColumns + `
+FROM hold
+WHERE book_id = $1 AND status = 'waiting'
+ORDER BY placed_at, id
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+`
+
+	return p.queryHold(ctx, "get_next_waiting_hold", getNextWaitingHold, bookID)
+}
+
+// GetExpiredHold returns a ready hold which expired before the given date.
+// The hold isn't locked: its book is locked first.
+func (p *postgresRepository) GetExpiredHold(
+	ctx context.Context,
+	before time.Time,
+) (*entity.Hold, error) {
+	const getExpiredHold = `
+SELECT ` + holdColumns + `
+FROM hold
+WHERE status = 'ready' AND expires_on < $1
+ORDER BY expires_on
+LIMIT 1;
+`
+
+	return p.queryHold(ctx, "get_expired_hold", getExpiredHold, before)
+}
+
+func (p *postgresRepository) MarkHoldReady(
+	ctx context.Context,
+	holdID string,
+	copyID string,
+	expiresOn time.Time,
+) (*entity.Hold, error) {
+	const markHoldReady = `
+UPDATE hold SET
+	status = 'ready',
+	copy_id = $2,
+	ready_at = now(),
+	expires_on = $3
+WHERE id = $1
+RETURNING ` + holdColumns + `;
+`
+
+	return p.queryHold(ctx, "mark_hold_ready", markHoldReady, holdID, copyID, expiresOn)
+}
+
+func (p *postgresRepository) SetHoldStatus(
+	ctx context.Context,
+	holdID string,
+	status entity.HoldStatus,
+) (*entity.Hold, error) {
+	const setHoldStatus = `
+UPDATE hold SET
+	status = $2
+WHERE id = $1
+RETURNING ` + holdColumns + `;
+`
+
+	return p.queryHold(ctx, "set_hold_status", setHoldStatus, holdID, status)
+}
+
+func (p *postgresRepository) ListHolds(
+	ctx context.Context,
+	filter entity.HoldsFilter,
+) ([]*entity.Hold, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("book_id", filter.BookID),
+		zap.String("patron_id", filter.PatronID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start ListHolds")
+
+	const listHolds = `
+SELECT ` + holdColumns + `
+FROM hold
+WHERE status IN ('waiting', 'ready')
+  AND ($1 = '' OR book_id = $1::uuid)
+  AND ($2 = '' OR patron_id = $2::uuid)
+ORDER BY book_id, status = 'waiting', placed_at, id;
+`
+
+	var rows pgx.Rows
+	err := measureQueryLatency("list_holds", func() error {
+		var err error
+		rows, err = p.conn(ctx).Query(ctx, listHolds, filter.BookID, filter.PatronID)
+		return err
+	})
+	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+	defer rows.Close()
+
+	holds := make([]*entity.Hold, 0)
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+
+		holds = append(holds, hold)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	return holds, nil
+}
+
+// queryHold runs a query that returns a single hold.
+func (p *postgresRepository) queryHold(
+	ctx context.Context,
+	operation string,
+	query string,
+	args ...any,
+) (*entity.Hold, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("operation", operation),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start queryHold")
+
+	var hold *entity.Hold
+	err := measureQueryLatency(operation, func() error {
+		var err error
+		hold, err = scanHold(p.conn(ctx).QueryRow(ctx, query, args...))
+		return err
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrHoldNotFound, span)
+	}
+
+	return hold, nil
+}
+
+func scanHold(row pgx.Row) (*entity.Hold, error) {
+	var hold entity.Hold
+
+	if err := row.Scan(&hold.ID, &hold.BookID, &hold.PatronID, &hold.CopyID, &hold.Status,
+		&hold.QueuePosition, &hold.PlacedAt, &hold.ReadyAt, &hold.ExpiresOn,
+		&hold.CreatedAt, &hold.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/metrics"
+)
+
+var _ InboxRepository = (*inboxRepository)(nil)
+
+// ErrInboxMessageReclaimed is returned by MarkAsProcessed, MarkAsFailed and
+// MarkAsDead when the message was claimed again after inProgressTTL, or
+// already processed, meanwhile.
+var ErrInboxMessageReclaimed = errors.New("inbox message was claimed by another worker")
+
+type inboxRepository struct {
+	db     PgxIface
+	logger *zap.Logger
+}
+
+func NewInbox(db PgxIface, logger *zap.Logger) *inboxRepository {
+	return &inboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ReceiveMessage stores the message unless one with messageID is already
+// there, and tells whether it was stored.
+func (i *inboxRepository) ReceiveMessage(
+	ctx context.Context,
+	messageID string,
+	kind InboxKind,
+	message []byte,
+	traceID string,
+) (bool, error) {
+	const query = `
+INSERT INTO inbox (message_id, kind, data, status, trace_id)
+VALUES($1, $2, $3, 'CREATED', $4)
+ON CONFLICT (message_id) DO NOTHING`
+
+	tag, err := i.conn(ctx).Exec(ctx, query, messageID, kind, message, traceID)
+	if err != nil {
+		return false, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		metrics.InboxTasksDuplicated.WithLabelValues(kind.String()).Inc()
+		return false, nil
+	}
+
+	metrics.InboxTasksCreated.WithLabelValues(kind.String()).Inc()
+
+	return true, nil
+}
+
+// GetMessages moves up to batchSize messages due for processing to
+// IN_PROGRESS.
+func (i *inboxRepository) GetMessages(
+	ctx context.Context,
+	batchSize int,
+	inProgressTTL time.Duration,
+) ([]InboxData, error) {
+	const query = `
+UPDATE inbox
+SET status = 'IN_PROGRESS', attempts = attempts + 1
+WHERE message_id IN (
+    SELECT message_id
+    FROM inbox
+    WHERE
+        status <> 'SUCCESS' AND (
+            (status IN ('CREATED', 'FAILED') AND next_attempt_at <= now())
+                OR (status = 'IN_PROGRESS' AND updated_at < now() - $1::interval))
+    ORDER BY created_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+	)
+	RETURNING message_id, kind, data, COALESCE(trace_id, ''), attempts, created_at;`
+
+	interval := fmt.Sprintf("%d ms", inProgressTTL.Milliseconds())
+
+	rows, err := i.conn(ctx).Query(ctx, query, interval, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	result := make([]InboxData, 0)
+
+	for rows.Next() {
+		var message InboxData
+
+		if err := rows.Scan(&message.MessageID, &message.Kind, &message.RawData, &message.TraceID,
+			&message.Attempts, &message.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		result = append(result, message)
+	}
+
+	return result, rows.Err()
+}
+
+// MarkAsProcessed must run in the transaction that applies the message:
+// the row lock it takes keeps another worker from claiming the message
+// until the transaction ends.
+func (i *inboxRepository) MarkAsProcessed(
+	ctx context.Context,
+	messageID string,
+	attempts int,
+) error {
+	const query = `
+UPDATE inbox
+SET status = 'SUCCESS', last_error = NULL
+WHERE message_id = $1 AND status = 'IN_PROGRESS' AND attempts = $2;
+`
+
+	return i.markClaimed(ctx, query, messageID, attempts)
+}
+
+// MarkAsFailed schedules the message to be processed again in retryIn.
+func (i *inboxRepository) MarkAsFailed(
+	ctx context.Context,
+	messageID string,
+	attempts int,
+	lastError string,
+	retryIn time.Duration,
+) error {
+	const query = `
+UPDATE inbox
+SET status = 'FAILED', last_error = $3, next_attempt_at = now() + $4::interval
+WHERE message_id = $1 AND status = 'IN_PROGRESS' AND attempts = $2;
+`
+
+	interval := fmt.Sprintf("%d ms", retryIn.Milliseconds())
+
+	return i.markClaimed(ctx, query, messageID, attempts, lastError, interval)
+}
+
+// MarkAsDead moves the message to dead letter, so it's never processed
+// again.
+func (i *inboxRepository) MarkAsDead(
+	ctx context.Context,
+	messageID string,
+	attempts int,
+	lastError string,
+) error {
+	const query = `
+UPDATE inbox
+SET status = 'DEAD', last_error = $3
+WHERE message_id = $1 AND status = 'IN_PROGRESS' AND attempts = $2;
+`
+
+	return i.markClaimed(ctx, query, messageID, attempts, lastError)
+}
+
+// markClaimed runs the outcome query, whose first arguments are the
+// message ID and the attempts the message was claimed with.
+func (i *inboxRepository) markClaimed(ctx context.Context, query string, args ...any) error {
+	tag, err := i.conn(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrInboxMessageReclaimed
+	}
+
+	return nil
+}
+
+// conn returns the transaction from ctx if there is one.
+func (i *inboxRepository) conn(ctx context.Context) PgxIface {
+	if tx, err := extractTx(ctx); err == nil {
+		return tx
+	}
+
+	return i.db
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -23,24 +24,108 @@ type (
 	AuthorRepository interface {
 		RegisterAuthor(ctx context.Context, author *entity.Author) (*entity.Author, error)
 		GetAuthorInfo(ctx context.Context, authorID string) (*entity.Author, error)
-		ChangeAuthor(ctx context.Context, authorID string, newAuthorName string) error
-		GetAuthorBooks(ctx context.Context, authorID string) ([]*entity.Book, error)
+		GetAuthorForUpdate(ctx context.Context, authorID string) (*entity.Author, error)
+		ChangeAuthor(ctx context.Context, authorID string, update entity.AuthorUpdate, expectedVersion int64) (*entity.Author, error)
+		GetAuthorBooks(ctx context.Context, authorID string, showDeleted bool) ([]*entity.Book, error)
+		SoftDeleteAuthor(ctx context.Context, authorID string) (*entity.Author, error)
+		RestoreAuthor(ctx context.Context, authorID string) (*entity.Author, error)
+		PurgeAuthor(ctx context.Context, authorID string) (*entity.Author, error)
+		ListAuthors(ctx context.Context, filter entity.AuthorsFilter, after *entity.AuthorCursor, limit int) ([]*entity.Author, error)
 	}
 
 	BooksRepository interface {
 		AddBook(ctx context.Context, book *entity.Book) (*entity.Book, error)
-		GetBook(ctx context.Context, bookID string) (*entity.Book, error)
-		UpdateBook(ctx context.Context, bookID string, newBookName string, authorIDs []string) error
+		GetBook(ctx context.Context, bookID string, showDeleted bool) (*entity.Book, error)
+		GetBookByISBN(ctx context.Context, isbn string) (*entity.Book, error)
+		GetBookForUpdate(ctx context.Context, bookID string) (*entity.Book, error)
+		UpdateBook(ctx context.Context, bookID string, update entity.BookUpdate, expectedVersion int64) (*entity.Book, error)
+		ListBooks(ctx context.Context, filter entity.BooksFilter, after *entity.BookCursor, limit int) ([]*entity.Book, error)
+		SoftDeleteBook(ctx context.Context, bookID string) (*entity.Book, error)
+		RestoreBook(ctx context.Context, bookID string) (*entity.Book, error)
+		PurgeBook(ctx context.Context, bookID string) (*entity.Book, error)
+		SearchCatalog(ctx context.Context, filter entity.SearchFilter, offset int, limit int) ([]*entity.SearchResult, error)
+	}
+
+	GenreRepository interface {
+		CreateGenre(ctx context.Context, genre *entity.Genre) (*entity.Genre, error)
+		GetGenre(ctx context.Context, genreID string) (*entity.Genre, error)
+		UpdateGenre(ctx context.Context, genreID string, update entity.GenreUpdate) (*entity.Genre, error)
+		DeleteGenre(ctx context.Context, genreID string) error
+		ListGenres(ctx context.Context, rootID string) ([]*entity.Genre, error)
+	}
+
+	CopyRepository interface {
+		AddCopy(ctx context.Context, copy *entity.Copy) (*entity.Copy, error)
+		GetCopy(ctx context.Context, copyID string) (*entity.Copy, error)
+		GetCopyForUpdate(ctx context.Context, copyID string) (*entity.Copy, error)
+		UpdateCopy(ctx context.Context, copyID string, update entity.CopyUpdate) (*entity.Copy, error)
+		DeleteCopy(ctx context.Context, copyID string) (*entity.Copy, error)
+		ListCopies(ctx context.Context, bookID string, filter entity.CopiesFilter) ([]*entity.Copy, error)
+		GetBookAvailability(ctx context.Context, bookID string) (*entity.BookAvailability, error)
+	}
+
+	PatronRepository interface {
+		RegisterPatron(ctx context.Context, patron *entity.Patron) (*entity.Patron, error)
+		GetPatron(ctx context.Context, patronID string) (*entity.Patron, error)
+		GetPatronByCardNumber(ctx context.Context, cardNumber string) (*entity.Patron, error)
+		GetPatronForUpdate(ctx context.Context, patronID string) (*entity.Patron, error)
+		UpdatePatron(ctx context.Context, patronID string, update entity.PatronUpdate) (*entity.Patron, error)
+		SetPatronStatus(ctx context.Context, patronID string, status entity.PatronStatus, reason string) (*entity.Patron, error)
+	}
+
+	LoanRepository interface {
+		CreateLoan(ctx context.Context, loan *entity.Loan) (*entity.Loan, error)
+		GetLoanForUpdate(ctx context.Context, loanID string) (*entity.Loan, error)
+		CountOpenLoans(ctx context.Context, patronID string) (int, error)
+		ReturnLoan(ctx context.Context, loanID string) (*entity.Loan, error)
+		RenewLoan(ctx context.Context, loanID string, dueOn time.Time) (*entity.Loan, error)
+		ListOverdueLoans(ctx context.Context, asOf time.Time, graceDays int) ([]*entity.Loan, error)
+	}
+
+	FineRepository interface {
+		UpsertFine(ctx context.Context, fine *entity.Fine) (*entity.Fine, error)
+		GetFineForUpdate(ctx context.Context, fineID string) (*entity.Fine, error)
+		PayFine(ctx context.Context, fineID string, amount int64) (*entity.Fine, error)
+		ListOutstandingFines(ctx context.Context, patronID string) ([]*entity.Fine, error)
+	}
+
+	HoldRepository interface {
+		PlaceHold(ctx context.Context, hold *entity.Hold) (*entity.Hold, error)
+		LockHoldQueue(ctx context.Context, bookID string) error
+		GetHold(ctx context.Context, holdID string) (*entity.Hold, error)
+		GetHoldForUpdate(ctx context.Context, holdID string) (*entity.Hold, error)
+		GetReadyHoldForCopy(ctx context.Context, copyID string) (*entity.Hold, error)
+		GetNextWaitingHold(ctx context.Context, bookID string) (*entity.Hold, error)
+		GetExpiredHold(ctx context.Context, before time.Time) (*entity.Hold, error)
+		MarkHoldReady(ctx context.Context, holdID string, copyID string, expiresOn time.Time) (*entity.Hold, error)
+		SetHoldStatus(ctx context.Context, holdID string, status entity.HoldStatus) (*entity.Hold, error)
+		ListHolds(ctx context.Context, filter entity.HoldsFilter) ([]*entity.Hold, error)
 	}
 
 	Transactor interface {
 		WithTx(ctx context.Context, function func(ctx context.Context) error) error
 	}
 
+	LeaderElector interface {
+		TryLead(ctx context.Context) (bool, error)
+		Resign(ctx context.Context)
+	}
+
 	OutboxRepository interface {
-		SendMessage(ctx context.Context, idempotencyKey string, kind OutboxKind, message []byte, traceID string) error
+		SendMessage(ctx context.Context, idempotencyKey string, kind OutboxKind, aggregateKey string,
+			message []byte, traceID string) error
 		GetMessages(ctx context.Context, batchSize int, inProgressTTL time.Duration) ([]OutboxData, error)
-		MarkAsProcessed(ctx context.Context, idempotencyKeys []string) error
+		GetOrderedMessages(ctx context.Context, batchSize int, inProgressTTL time.Duration) ([]OutboxData, error)
+		MarkAsProcessed(ctx context.Context, idempotencyKey string, attempts int) error
+		MarkAsFailed(ctx context.Context, idempotencyKey string, attempts int, lastError string,
+			retryIn time.Duration) error
+		MarkAsDead(ctx context.Context, idempotencyKey string, attempts int, lastError string) error
+		ListMessages(ctx context.Context, filter OutboxFilter, after *OutboxCursor, limit int) ([]*OutboxMessage, error)
+		GetMessage(ctx context.Context, idempotencyKey string) (*OutboxMessage, error)
+		GetMessageForUpdate(ctx context.Context, idempotencyKey string) (*OutboxMessage, error)
+		RetryMessage(ctx context.Context, idempotencyKey string) (*OutboxMessage, error)
+		RetryDeadMessages(ctx context.Context) (int64, error)
+		PurgeProcessed(ctx context.Context, olderThan time.Duration, limit int) (int64, error)
 	}
 
 	OutboxData struct {
@@ -48,15 +133,130 @@ type (
 		Kind           OutboxKind
 		RawData        []byte
 		TraceID        string
+		// Attempts counts the deliveries started, including this one.
+		Attempts  int
+		CreatedAt time.Time
+		// AggregateKey is the entity the message is about, and Sequence
+		// numbers the messages of the aggregate from 1 with no gaps. Both
+		// are empty for messages stored before aggregates were.
+		AggregateKey string
+		Sequence     int64
+	}
+
+	// InboxRepository stores the events received from other services.
+	// MarkAsProcessed, MarkAsFailed and MarkAsDead succeed only while the
+	// worker that claimed the message with attempts still holds it, so that
+	// the change applied in the same transaction is rolled back when
+	// another worker took over, and a late failure doesn't override it.
+	InboxRepository interface {
+		ReceiveMessage(ctx context.Context, messageID string, kind InboxKind, message []byte, traceID string) (bool, error)
+		GetMessages(ctx context.Context, batchSize int, inProgressTTL time.Duration) ([]InboxData, error)
+		MarkAsProcessed(ctx context.Context, messageID string, attempts int) error
+		MarkAsFailed(ctx context.Context, messageID string, attempts int, lastError string, retryIn time.Duration) error
+		MarkAsDead(ctx context.Context, messageID string, attempts int, lastError string) error
 	}
+
+	InboxData struct {
+		MessageID string
+		Kind      InboxKind
+		RawData   []byte
+		TraceID   string
+		// Attempts counts the applications started, including this one.
+		Attempts  int
+		CreatedAt time.Time
+	}
+
+	// CatalogChangeRepository reads the change log of books and authors
+	// that SendMessage appends to. Positions grow by one in the order the
+	// changes are committed.
+	CatalogChangeRepository interface {
+		ListChanges(ctx context.Context, after int64, limit int) ([]*CatalogChange, error)
+	}
+
+	// CatalogNotifier wakes the subscribers up once a change is committed
+	// to the log. Several changes may come with one wake-up, and a wake-up
+	// may come with none.
+	CatalogNotifier interface {
+		Subscribe() (<-chan struct{}, func())
+	}
+
+	CatalogChange struct {
+		Position int64
+		Kind     OutboxKind
+		EntityID string
+		RawData  []byte
+		TraceID  string
+		// CreatedAt is when the change was made.
+		CreatedAt time.Time
+	}
+
+	// OutboxMessage is the whole outbox row, as shown to operators.
+	OutboxMessage struct {
+		IdempotencyKey string
+		Kind           OutboxKind
+		Status         OutboxStatus
+		RawData        []byte
+		TraceID        string
+		Attempts       int
+		NextAttemptAt  time.Time
+		LastError      string
+		CreatedAt      time.Time
+		UpdatedAt      time.Time
+	}
+
+	// OutboxFilter narrows ListMessages. Zero fields match any message;
+	// CreatedAfter is inclusive and CreatedBefore exclusive.
+	OutboxFilter struct {
+		Status        OutboxStatus
+		Kind          OutboxKind
+		CreatedAfter  time.Time
+		CreatedBefore time.Time
+	}
+
+	OutboxCursor struct {
+		CreatedAt      time.Time
+		IdempotencyKey string
+	}
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusCreated    OutboxStatus = "CREATED"
+	OutboxStatusInProgress OutboxStatus = "IN_PROGRESS"
+	OutboxStatusSuccess    OutboxStatus = "SUCCESS"
+	OutboxStatusFailed     OutboxStatus = "FAILED"
+	OutboxStatusDead       OutboxStatus = "DEAD"
 )
 
+// Pending tells whether the message is still waiting for its first delivery
+// or being delivered right now.
+func (s OutboxStatus) Pending() bool {
+	return s == OutboxStatusCreated || s == OutboxStatusInProgress
+}
+
 type OutboxKind int
 
 const (
 	OutboxKindUndefined OutboxKind = iota
 	OutboxKindBook
 	OutboxKindAuthor
+	OutboxKindBookDeleted
+	OutboxKindBookRestored
+	OutboxKindBookPurged
+	OutboxKindAuthorDeleted
+	OutboxKindAuthorRestored
+	OutboxKindAuthorPurged
+	OutboxKindBookUpdated
+	OutboxKindAuthorUpdated
+	OutboxKindCopy
+	OutboxKindCopyUpdated
+	OutboxKindCopyDeleted
+	OutboxKindLoanCheckedOut
+	OutboxKindLoanReturned
+	OutboxKindLoanRenewed
+	OutboxKindHoldReady
+	OutboxKindLoanOverdue
 )
 
 func (o OutboxKind) String() string {
@@ -65,7 +265,91 @@ func (o OutboxKind) String() string {
 		return "book"
 	case OutboxKindAuthor:
 		return "author"
+	case OutboxKindBookDeleted:
+		return "book_deleted"
+	case OutboxKindBookRestored:
+		return "book_restored"
+	case OutboxKindBookPurged:
+		return "book_purged"
+	case OutboxKindAuthorDeleted:
+		return "author_deleted"
+	case OutboxKindAuthorRestored:
+		return "author_restored"
+	case OutboxKindAuthorPurged:
+		return "author_purged"
+	case OutboxKindBookUpdated:
+		return "book_updated"
+	case OutboxKindAuthorUpdated:
+		return "author_updated"
+	case OutboxKindCopy:
+		return "copy"
+	case OutboxKindCopyUpdated:
+		return "copy_updated"
+	case OutboxKindCopyDeleted:
+		return "copy_deleted"
+	case OutboxKindLoanCheckedOut:
+		return "loan_checked_out"
+	case OutboxKindLoanReturned:
+		return "loan_returned"
+	case OutboxKindLoanRenewed:
+		return "loan_renewed"
+	case OutboxKindHoldReady:
+		return "hold_ready"
+	case OutboxKindLoanOverdue:
+		return "loan_overdue"
 	default:
 		return "undefined"
 	}
 }
+
+// EventType names the kind for event consumers as library.<entity>.<action>:
+// book_deleted is library.book.deleted, and a kind without an action,
+// such as book, is the creation of the entity.
+func (o OutboxKind) EventType() string {
+	entityName, action, ok := strings.Cut(o.String(), "_")
+	if !ok {
+		action = "created"
+	}
+
+	return "library." + entityName + "." + action
+}
+
+// Catalog tells whether the kind is a change of a book or an author,
+// which goes to the catalog change log.
+func (o OutboxKind) Catalog() bool {
+	entityName, _, _ := strings.Cut(o.String(), "_")
+	return entityName == "book" || entityName == "author"
+}
+
+// OutboxKinds lists every defined kind.
+func OutboxKinds() []OutboxKind {
+	var kinds []OutboxKind
+	for kind := OutboxKindBook; kind.String() != "undefined"; kind++ {
+		kinds = append(kinds, kind)
+	}
+
+	return kinds
+}
+
+// ParseOutboxKind is the inverse of OutboxKind.String.
+func ParseOutboxKind(name string) (OutboxKind, bool) {
+	for _, kind := range OutboxKinds() {
+		if kind.String() == name {
+			return kind, true
+		}
+	}
+
+	return OutboxKindUndefined, false
+}
+
+// InboxKind is the event type chosen by the sender of an inbox message.
+type InboxKind string
+
+const (
+	InboxKindBookPurchased InboxKind = "book_purchased"
+	InboxKindAuthorMerged  InboxKind = "author_merged"
+)
+
+func (k InboxKind) String() string {
+	return string(k)
+}
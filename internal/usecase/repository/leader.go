@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+var _ LeaderElector = (*leaderElector)(nil)
+
+// leaderElector elects a single leader among the replicas with a session
+// level advisory lock. The leader keeps the lock on a connection taken out
+// of the pool and loses it when the connection is closed. It isn't safe
+// for concurrent use.
+type leaderElector struct {
+	pool   *pgxpool.Pool
+	logger *zap.Logger
+	key    int64
+	conn   *pgx.Conn
+}
+
+func NewLeaderElector(pool *pgxpool.Pool, logger *zap.Logger, key int64) *leaderElector {
+	return &leaderElector{
+		pool:   pool,
+		logger: logger,
+		key:    key,
+	}
+}
+
+// TryLead tells whether this replica is the leader, trying to become one
+// if it isn't.
+func (l *leaderElector) TryLead(ctx context.Context) (bool, error) {
+	if l.conn != nil {
+		err := l.conn.Ping(ctx)
+		if err == nil {
+			return true, nil
+		}
+
+		l.logger.Warn("leader connection is lost",
+			zap.Int64("key", l.key), zap.Error(err))
+		l.Resign(ctx)
+	}
+
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var acquired bool
+	err = conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired)
+	if err != nil || !acquired {
+		conn.Release()
+		return false, err
+	}
+
+	l.conn = conn.Hijack()
+	l.logger.Info("became leader", zap.Int64("key", l.key))
+
+	return true, nil
+}
+
+// Resign gives up the leadership by closing the connection holding the
+// lock.
+func (l *leaderElector) Resign(ctx context.Context) {
+	if l.conn == nil {
+		return
+	}
+
+	if err := l.conn.Close(ctx); err != nil {
+		l.logger.Warn("can not close leader connection",
+			zap.Int64("key", l.key), zap.Error(err))
+	}
+	l.conn = nil
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+)
+
+const loanColumns = `id, copy_id, book_id, patron_id, checked_out_at, due_on, returned_at, renewals,
+	created_at, updated_at`
+
+const loanOpenCopyIndex = "idx_loan_open_copy"
+
+// Loans are taken on locked copies of existing books, so these foreign
+// keys are violated only by deleting a copy or a book that was lent.
+const (
+	loanCopyForeignKey = "loan_copy_id_fkey"
+	loanBookForeignKey = "loan_book_id_fkey"
+)
+
+func (p *postgresRepository) CreateLoan(
+	ctx context.Context,
+	loan *entity.Loan,
+) (*entity.Loan, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("copy_id", loan.CopyID),
+		zap.String("patron_id", loan.PatronID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start CreateLoan")
+
+	const insertLoan = `
+INSERT INTO loan (copy_id, book_id, patron_id, due_on)
+SELECT id, book_id, $2, $3
+FROM copy
+WHERE id = $1
+RETURNING ` + loanColumns + `;
+`
+
+	var created *entity.Loan
+	err := measureQueryLatency("insert_loan", func() error {
+		var err error
+		created, err = scanLoan(p.conn(ctx).QueryRow(ctx, insertLoan,
+			loan.CopyID, loan.PatronID, loan.DueOn))
+		return err
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrCopyNotFound, span)
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetLoanForUpdate(
+	ctx context.Context,
+	loanID string,
+) (*entity.Loan, error) {
+	const getLoanForUpdate = `
+SELECT ` + loanColumns + `
+FROM loan
+WHERE id = $1
+FOR UPDATE;
+`
+
+	return p.changeLoan(ctx, "get_loan_for_update", getLoanForUpdate, loanID)
+}
+
+func (p *postgresRepository) CountOpenLoans(
+	ctx context.Context,
+	patronID string,
+) (int, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("patron_id", patronID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start CountOpenLoans")
+
+	const countOpenLoans = `
+SELECT count(*)
+FROM loan
+WHERE patron_id = $1 AND returned_at IS NULL;
+`
+
+	var count int
+	err := measureQueryLatency("count_open_loans", func() error {
+		return p.conn(ctx).QueryRow(ctx, countOpenLoans, patronID).Scan(&count)
+	})
+
+	if err != nil {
+		return 0, mapPostgresError(err, err, span)
+	}
+
+	return count, nil
+}
+
+func (p *postgresRepository) ReturnLoan(
+	ctx context.Context,
+	loanID string,
+) (*entity.Loan, error) {
+	const returnLoan = `
+UPDATE loan SET
+	returned_at = now()
+WHERE id = $1 AND returned_at IS NULL
+RETURNING ` + loanColumns + `;
+`
+
+	return p.changeLoan(ctx, "return_loan", returnLoan, loanID)
+}
+
+func (p *postgresRepository) RenewLoan(
+	ctx context.Context,
+	loanID string,
+	dueOn time.Time,
+) (*entity.Loan, error) {
+	const renewLoan = `
+UPDATE loan SET
+	due_on = $2,
+	renewals = renewals + 1
+WHERE id = $1 AND returned_at IS NULL
+RETURNING ` + loanColumns + `;
+`
+
+	return p.changeLoan(ctx, "renew_loan", renewLoan, loanID, dueOn)
+}
+
+// ListOverdueLoans lists the open loans due before asOf, and the loans
+// returned more than graceDays late whose fine wasn't accrued through the
+// return day yet.
+func (p *postgresRepository) ListOverdueLoans(
+	ctx context.Context,
+	asOf time.Time,
+	graceDays int,
+) ([]*entity.Loan, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.Time("as_of", asOf),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start ListOverdueLoans")
+
+	const listOverdueLoans = `
+SELECT ` + loanColumns + `
+FROM loan
+WHERE due_on < $1
+  AND (returned_at IS NULL OR (
+	returned_at::date > due_on + $2::int
+	AND NOT EXISTS (
+		SELECT 1
+		FROM fine
+		WHERE fine.loan_id = loan.id AND fine.accrued_on >= loan.returned_at::date
+	)
+  ))
+ORDER BY due_on, id;
+`
+
+	var rows pgx.Rows
+	err := measureQueryLatency("list_overdue_loans", func() error {
+		var err error
+		rows, err = p.conn(ctx).Query(ctx, listOverdueLoans, asOf, graceDays)
+		return err
+	})
+	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+	defer rows.Close()
+
+	loans := make([]*entity.Loan, 0)
+	for rows.Next() {
+		loan, err := scanLoan(rows)
+		if err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+
+		loans = append(loans, loan)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	return loans, nil
+}
+
+// changeLoan runs a query that returns the loan with the given id.
+func (p *postgresRepository) changeLoan(
+	ctx context.Context,
+	operation string,
+	query string,
+	loanID string,
+	args ...any,
+) (*entity.Loan, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("operation", operation),
+		zap.String("loan_id", loanID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start changeLoan")
+
+	var loan *entity.Loan
+	err := measureQueryLatency(operation, func() error {
+		var err error
+		loan, err = scanLoan(p.conn(ctx).QueryRow(ctx, query, append([]any{loanID}, args...)...))
+		return err
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrLoanNotFound, span)
+	}
+
+	return loan, nil
+}
+
+func scanLoan(row pgx.Row) (*entity.Loan, error) {
+	var loan entity.Loan
+
+	if err := row.Scan(&loan.ID, &loan.CopyID, &loan.BookID, &loan.PatronID, &loan.CheckedOutAt,
+		&loan.DueOn, &loan.ReturnedAt, &loan.Renewals, &loan.CreatedAt,
+		&loan.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &loan, nil
+}
@@ -2,16 +2,24 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/project/library/internal/entity"
 	"github.com/project/library/internal/metrics"
 	"go.uber.org/zap"
 )
 
 var _ OutboxRepository = (*outboxRepository)(nil)
 
+// ErrOutboxMessageReclaimed is returned when the outcome of a delivery
+// comes after the message was claimed again, or its outcome was recorded.
+var ErrOutboxMessageReclaimed = errors.New("outbox message was claimed by another worker")
+
 type outboxRepository struct {
 	db     PgxIface
 	logger *zap.Logger
@@ -24,29 +32,65 @@ func NewOutbox(db PgxIface, logger *zap.Logger) *outboxRepository {
 	}
 }
 
+// SendMessage stores the message as the next one of its aggregate. It must
+// run in the transaction of the change the message is about: the aggregate
+// stays locked until then, so the sequence follows the commit order.
 func (o *outboxRepository) SendMessage(
 	ctx context.Context,
 	idempotencyKey string,
 	kind OutboxKind,
+	aggregateKey string,
 	message []byte,
 	traceID string,
 ) error {
 	const query = `
-INSERT INTO outbox (idempotency_key, data, status, kind, trace_id)
-VALUES($1, $2, 'CREATED', $3, $4)
+INSERT INTO outbox (idempotency_key, data, status, kind, trace_id, aggregate_key)
+VALUES($1, $2, 'CREATED', $3, $4, $5)
 ON CONFLICT (idempotency_key) DO NOTHING`
 
-	var err error
-	if tx, txErr := extractTx(ctx); txErr == nil {
-		_, err = tx.Exec(ctx, query, idempotencyKey, message, kind, traceID)
-	} else {
-		_, err = o.db.Exec(ctx, query, idempotencyKey, message, kind, traceID)
-	}
+	// The sequence is taken only for a new message, so that a duplicate
+	// doesn't leave a gap.
+	const sequenceQuery = `
+WITH next AS (
+    INSERT INTO outbox_aggregate (aggregate_key, sequence)
+    VALUES ($2, 1)
+    ON CONFLICT (aggregate_key) DO UPDATE SET sequence = outbox_aggregate.sequence + 1
+    RETURNING sequence
+)
+UPDATE outbox
+SET sequence = (SELECT sequence FROM next)
+WHERE idempotency_key = $1;
+`
 
+	conn := o.conn(ctx)
+
+	tag, err := conn.Exec(ctx, query, idempotencyKey, message, kind, traceID, aggregateKey)
 	if err != nil {
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	if _, err = conn.Exec(ctx, sequenceQuery, idempotencyKey, aggregateKey); err != nil {
+		return err
+	}
+
+	// Book and author messages also go to the catalog change log, once
+	// the transaction is about to commit.
+	if kind.Catalog() {
+		err = addCatalogChange(ctx, conn, &CatalogChange{
+			Kind:     kind,
+			EntityID: aggregateKey,
+			RawData:  message,
+			TraceID:  traceID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	metrics.OutboxTasksCreated.WithLabelValues(kind.String()).Inc()
 
 	return nil
@@ -56,33 +100,56 @@ func (o *outboxRepository) GetMessages(
 	ctx context.Context, batchSize int,
 	inProgressTTL time.Duration,
 ) ([]OutboxData, error) {
-	const query = `
+	return o.claimMessages(ctx, "", batchSize, inProgressTTL)
+}
+
+// GetOrderedMessages is GetMessages that claims a message only once every
+// earlier message of its aggregate is delivered, so a batch holds at most
+// one message per aggregate and no other worker can claim the next one
+// meanwhile. A dead message holds its aggregate back until it's retried.
+func (o *outboxRepository) GetOrderedMessages(
+	ctx context.Context, batchSize int,
+	inProgressTTL time.Duration,
+) ([]OutboxData, error) {
+	const afterPredecessors = `
+        AND NOT EXISTS (
+            SELECT 1
+            FROM outbox earlier
+            WHERE earlier.aggregate_key = outbox.aggregate_key
+                AND earlier.sequence < outbox.sequence
+                AND earlier.status <> 'SUCCESS')`
+
+	return o.claimMessages(ctx, afterPredecessors, batchSize, inProgressTTL)
+}
+
+// claimMessages moves up to batchSize messages due for delivery to
+// IN_PROGRESS, with condition narrowing the messages due.
+func (o *outboxRepository) claimMessages(
+	ctx context.Context,
+	condition string,
+	batchSize int,
+	inProgressTTL time.Duration,
+) ([]OutboxData, error) {
+	query := `
 UPDATE outbox
-SET status = 'IN_PROGRESS'
+SET status = 'IN_PROGRESS', attempts = attempts + 1
 WHERE idempotency_key IN (
     SELECT idempotency_key
     FROM outbox
     WHERE
-        (status = 'CREATED'
-            OR (status = 'IN_PROGRESS' AND updated_at < now() - $1::interval))
+        status <> 'SUCCESS' AND (
+            (status IN ('CREATED', 'FAILED') AND next_attempt_at <= now())
+                OR (status = 'IN_PROGRESS' AND updated_at < now() - $1::interval))` + condition + `
     ORDER BY created_at
     LIMIT $2
     FOR UPDATE SKIP LOCKED
 	)
-	RETURNING idempotency_key, data, kind, trace_id;`
+	RETURNING idempotency_key, data, kind, trace_id, attempts, created_at,
+	    COALESCE(aggregate_key, ''), COALESCE(sequence, 0);`
 
 	interval := fmt.Sprintf("%d ms", inProgressTTL.Milliseconds())
-	var (
-		err  error
-		rows pgx.Rows
-	)
-	tx, txErr := extractTx(ctx)
-	if txErr == nil {
-		rows, err = tx.Query(ctx, query, interval, batchSize)
-	} else {
-		rows, err = o.db.Query(ctx, query, interval, batchSize)
-	}
 
+	rows, err := o.conn(ctx).Query(ctx, query, interval, batchSize)
 	if err != nil {
 		return nil, err
 	}
@@ -92,50 +159,278 @@ WHERE idempotency_key IN (
 	result := make([]OutboxData, 0)
 
 	for rows.Next() {
-		var key string
-		var rawData []byte
-		var kind OutboxKind
-		var traceID string
+		var message OutboxData
 
-		if err := rows.Scan(&key, &rawData, &kind, &traceID); err != nil {
+		if err := rows.Scan(&message.IdempotencyKey, &message.RawData, &message.Kind, &message.TraceID,
+			&message.Attempts, &message.CreatedAt, &message.AggregateKey, &message.Sequence); err != nil {
 			return nil, err
 		}
 
-		result = append(result, OutboxData{
-			IdempotencyKey: key,
-			RawData:        rawData,
-			Kind:           kind,
-			TraceID:        traceID,
-		})
+		result = append(result, message)
 	}
 
 	return result, rows.Err()
 }
 
+// MarkAsProcessed, MarkAsFailed and MarkAsDead record the outcome only
+// while the worker that claimed the message with attempts still holds it.
+// Otherwise the message was claimed again after inProgressTTL, and its
+// outcome is up to the worker that holds it now.
 func (o *outboxRepository) MarkAsProcessed(
 	ctx context.Context,
-	idempotencyKeys []string,
+	idempotencyKey string,
+	attempts int,
 ) error {
-	if len(idempotencyKeys) == 0 {
-		return nil
-	}
-
 	const query = `
 UPDATE outbox
 SET status = 'SUCCESS'
-WHERE idempotency_key = ANY($1);
+WHERE idempotency_key = $1 AND status = 'IN_PROGRESS' AND attempts = $2;
 `
 
-	var err error
-	if tx, txErr := extractTx(ctx); txErr == nil {
-		_, err = tx.Exec(ctx, query, idempotencyKeys)
-	} else {
-		_, err = o.db.Exec(ctx, query, idempotencyKeys)
-	}
+	return o.markClaimed(ctx, query, idempotencyKey, attempts)
+}
 
+// MarkAsFailed schedules the message to be retried in retryIn.
+func (o *outboxRepository) MarkAsFailed(
+	ctx context.Context,
+	idempotencyKey string,
+	attempts int,
+	lastError string,
+	retryIn time.Duration,
+) error {
+	const query = `
+UPDATE outbox
+SET status = 'FAILED', last_error = $3, next_attempt_at = now() + $4::interval
+WHERE idempotency_key = $1 AND status = 'IN_PROGRESS' AND attempts = $2;
+`
+
+	interval := fmt.Sprintf("%d ms", retryIn.Milliseconds())
+
+	return o.markClaimed(ctx, query, idempotencyKey, attempts, lastError, interval)
+}
+
+// MarkAsDead moves the message to dead letter, so it's never retried.
+func (o *outboxRepository) MarkAsDead(
+	ctx context.Context,
+	idempotencyKey string,
+	attempts int,
+	lastError string,
+) error {
+	const query = `
+UPDATE outbox
+SET status = 'DEAD', last_error = $3
+WHERE idempotency_key = $1 AND status = 'IN_PROGRESS' AND attempts = $2;
+`
+
+	return o.markClaimed(ctx, query, idempotencyKey, attempts, lastError)
+}
+
+// markClaimed runs the outcome query, whose first arguments are the key
+// and the attempts the message was claimed with.
+func (o *outboxRepository) markClaimed(ctx context.Context, query string, args ...any) error {
+	tag, err := o.conn(ctx).Exec(ctx, query, args...)
 	if err != nil {
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return ErrOutboxMessageReclaimed
+	}
+
 	return nil
 }
+
+const outboxMessageColumns = `idempotency_key, kind, status, data, COALESCE(trace_id, ''),
+	attempts, next_attempt_at, COALESCE(last_error, ''), created_at, updated_at`
+
+// ListMessages pages through the messages oldest first.
+func (o *outboxRepository) ListMessages(
+	ctx context.Context,
+	filter OutboxFilter,
+	after *OutboxCursor,
+	limit int,
+) ([]*OutboxMessage, error) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+	addArg := func(arg any) string {
+		args = append(args, arg)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+addArg(string(filter.Status)))
+	}
+	if filter.Kind != OutboxKindUndefined {
+		conditions = append(conditions, "kind = "+addArg(filter.Kind))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conditions = append(conditions, "created_at >= "+addArg(filter.CreatedAfter))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		conditions = append(conditions, "created_at < "+addArg(filter.CreatedBefore))
+	}
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, idempotency_key) > (%s, %s)",
+			addArg(after.CreatedAt), addArg(after.IdempotencyKey)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+SELECT %s
+FROM outbox
+%s
+ORDER BY created_at, idempotency_key
+LIMIT %s;
+`, outboxMessageColumns, where, addArg(limit))
+
+	rows, err := o.conn(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]*OutboxMessage, 0, limit)
+	for rows.Next() {
+		message, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+func (o *outboxRepository) GetMessage(
+	ctx context.Context,
+	idempotencyKey string,
+) (*OutboxMessage, error) {
+	const query = `
+SELECT ` + outboxMessageColumns + `
+FROM outbox
+WHERE idempotency_key = $1;
+`
+
+	return o.queryMessage(ctx, query, idempotencyKey)
+}
+
+func (o *outboxRepository) GetMessageForUpdate(
+	ctx context.Context,
+	idempotencyKey string,
+) (*OutboxMessage, error) {
+	const query = `
+SELECT ` + outboxMessageColumns + `
+FROM outbox
+WHERE idempotency_key = $1
+FOR UPDATE;
+`
+
+	return o.queryMessage(ctx, query, idempotencyKey)
+}
+
+// RetryMessage queues the message for delivery right away, with the
+// attempts starting over.
+func (o *outboxRepository) RetryMessage(
+	ctx context.Context,
+	idempotencyKey string,
+) (*OutboxMessage, error) {
+	const query = `
+UPDATE outbox
+SET status = 'CREATED', attempts = 0, next_attempt_at = now(), last_error = NULL
+WHERE idempotency_key = $1
+RETURNING ` + outboxMessageColumns + `;
+`
+
+	return o.queryMessage(ctx, query, idempotencyKey)
+}
+
+// RetryDeadMessages queues every dead message for delivery and returns
+// how many there were.
+func (o *outboxRepository) RetryDeadMessages(ctx context.Context) (int64, error) {
+	const query = `
+UPDATE outbox
+SET status = 'CREATED', attempts = 0, next_attempt_at = now(), last_error = NULL
+WHERE status = 'DEAD';
+`
+
+	tag, err := o.conn(ctx).Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// PurgeProcessed deletes up to limit delivered messages last updated more
+// than olderThan ago and returns how many it deleted.
+func (o *outboxRepository) PurgeProcessed(
+	ctx context.Context,
+	olderThan time.Duration,
+	limit int,
+) (int64, error) {
+	const query = `
+DELETE FROM outbox
+WHERE idempotency_key IN (
+    SELECT idempotency_key
+    FROM outbox
+    WHERE status = 'SUCCESS' AND updated_at < now() - $1::interval
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+);
+`
+
+	interval := fmt.Sprintf("%d ms", olderThan.Milliseconds())
+
+	tag, err := o.conn(ctx).Exec(ctx, query, interval, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	metrics.OutboxTasksPurged.Add(float64(tag.RowsAffected()))
+
+	return tag.RowsAffected(), nil
+}
+
+func (o *outboxRepository) queryMessage(
+	ctx context.Context,
+	query string,
+	args ...any,
+) (*OutboxMessage, error) {
+	message, err := scanOutboxMessage(o.conn(ctx).QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, entity.ErrOutboxMessageNotFound
+	}
+
+	return message, err
+}
+
+// conn returns the transaction from ctx if there is one.
+func (o *outboxRepository) conn(ctx context.Context) PgxIface {
+	if tx, err := extractTx(ctx); err == nil {
+		return tx
+	}
+
+	return o.db
+}
+
+func scanOutboxMessage(row pgx.Row) (*OutboxMessage, error) {
+	var (
+		message OutboxMessage
+		status  string
+	)
+
+	if err := row.Scan(&message.IdempotencyKey, &message.Kind, &status, &message.RawData,
+		&message.TraceID, &message.Attempts, &message.NextAttemptAt, &message.LastError,
+		&message.CreatedAt, &message.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	message.Status = OutboxStatus(status)
+
+	return &message, nil
+}
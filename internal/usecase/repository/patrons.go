@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/entity"
+)
+
+const patronColumns = `id, card_number, name, email, phone, address, membership_expires_on,
+	status, block_reason, created_at, updated_at`
+
+const patronCardNumberIndex = "idx_patron_card_number"
+
+func (p *postgresRepository) RegisterPatron(
+	ctx context.Context,
+	patron *entity.Patron,
+) (*entity.Patron, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start RegisterPatron")
+
+	const insertPatron = `
+INSERT INTO patron (card_number, name, email, phone, address, membership_expires_on)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING ` + patronColumns + `;
+`
+
+	var created *entity.Patron
+	err := measureQueryLatency("insert_patron", func() error {
+		var err error
+		created, err = scanPatron(p.conn(ctx).QueryRow(ctx, insertPatron,
+			patron.CardNumber, patron.Name, patron.Email, patron.Phone,
+			patron.Address, patron.MembershipExpiresOn))
+		return err
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	return created, nil
+}
+
+func (p *postgresRepository) GetPatron(
+	ctx context.Context,
+	patronID string,
+) (*entity.Patron, error) {
+	const getPatron = `
+SELECT ` + patronColumns + `
+FROM patron
+WHERE id = $1;
+`
+
+	return p.changePatron(ctx, "get_patron", getPatron, patronID)
+}
+
+func (p *postgresRepository) GetPatronForUpdate(
+	ctx context.Context,
+	patronID string,
+) (*entity.Patron, error) {
+	const getPatronForUpdate = `
+SELECT ` + patronColumns + `
+FROM patron
+WHERE id = $1
+FOR UPDATE;
+`
+
+	return p.changePatron(ctx, "get_patron_for_update", getPatronForUpdate, patronID)
+}
+
+func (p *postgresRepository) GetPatronByCardNumber(
+	ctx context.Context,
+	cardNumber string,
+) (*entity.Patron, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start GetPatronByCardNumber")
+
+	const getPatronByCardNumber = `
+SELECT ` + patronColumns + `
+FROM patron
+WHERE card_number = $1;
+`
+
+	var patron *entity.Patron
+	err := measureQueryLatency("get_patron_by_card_number", func() error {
+		var err error
+		patron, err = scanPatron(p.conn(ctx).QueryRow(ctx, getPatronByCardNumber, cardNumber))
+		return err
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrPatronNotFound, span)
+	}
+
+	return patron, nil
+}
+
+func (p *postgresRepository) UpdatePatron(
+	ctx context.Context,
+	patronID string,
+	update entity.PatronUpdate,
+) (*entity.Patron, error) {
+	const updatePatron = `
+UPDATE patron SET
+	name = COALESCE($2, name),
+	email = COALESCE($3, email),
+	phone = COALESCE($4, phone),
+	address = COALESCE($5, address),
+	membership_expires_on = COALESCE($6, membership_expires_on)
+WHERE id = $1
+RETURNING ` + patronColumns + `;
+`
+
+	return p.changePatron(ctx, "update_patron", updatePatron, patronID,
+		update.Name, update.Email, update.Phone, update.Address, update.MembershipExpiresOn)
+}
+
+func (p *postgresRepository) SetPatronStatus(
+	ctx context.Context,
+	patronID string,
+	status entity.PatronStatus,
+	reason string,
+) (*entity.Patron, error) {
+	const setPatronStatus = `
+UPDATE patron SET
+	status = $2,
+	block_reason = $3
+WHERE id = $1
+RETURNING ` + patronColumns + `;
+`
+
+	return p.changePatron(ctx, "set_patron_status", setPatronStatus, patronID, status, reason)
+}
+
+// changePatron runs a query that returns the patron with the given id.
+func (p *postgresRepository) changePatron(
+	ctx context.Context,
+	operation string,
+	query string,
+	patronID string,
+	args ...any,
+) (*entity.Patron, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("operation", operation),
+		zap.String("patron_id", patronID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start changePatron")
+
+	var patron *entity.Patron
+	err := measureQueryLatency(operation, func() error {
+		var err error
+		patron, err = scanPatron(p.conn(ctx).QueryRow(ctx, query, append([]any{patronID}, args...)...))
+		return err
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrPatronNotFound, span)
+	}
+
+	return patron, nil
+}
+
+func scanPatron(row pgx.Row) (*entity.Patron, error) {
+	var patron entity.Patron
+
+	if err := row.Scan(&patron.ID, &patron.CardNumber, &patron.Name, &patron.Email,
+		&patron.Phone, &patron.Address, &patron.MembershipExpiresOn, &patron.Status,
+		&patron.BlockReason, &patron.CreatedAt, &patron.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return &patron, nil
+}
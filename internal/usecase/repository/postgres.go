@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,9 +21,79 @@ import (
 
 var _ AuthorRepository = (*postgresRepository)(nil)
 var _ BooksRepository = (*postgresRepository)(nil)
+var _ GenreRepository = (*postgresRepository)(nil)
 
 var ErrForeignKeyViolation = &pgconn.PgError{Code: "23503"}
 
+const (
+	uniqueViolation      = "23505"
+	foreignKeyViolation  = "23503"
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// bookColumns is the column order expected by scanBook, which reads
+// bookRelations right after them.
+const bookColumns = `
+	id,
+	name,
+	isbn,
+	publisher,
+	publication_year,
+	language,
+	page_count,
+	description,
+	created_at,
+	updated_at,
+	deleted_at,
+	version`
+
+// bookRelations are the author ids, genre ids and tags of the row
+// named book.
+const bookRelations = `
+	ARRAY(SELECT author_id FROM author_book WHERE book_id = book.id),
+	ARRAY(SELECT genre_id FROM book_genre WHERE book_id = book.id),
+	ARRAY(SELECT tag FROM book_tag WHERE book_id = book.id ORDER BY tag)`
+
+const returningBook = `
+RETURNING` + bookColumns + `,` + bookRelations + `;
+`
+
+const selectBook = `
+SELECT` + bookColumns + `,` + bookRelations + `
+FROM book
+`
+
+// genreSubtree selects the ids of the genre given as its only argument
+// and all of its descendants.
+const genreSubtree = `
+WITH RECURSIVE subtree AS (
+	SELECT id FROM genre WHERE id = %[1]s
+	UNION ALL
+	SELECT genre.id FROM genre JOIN subtree ON genre.parent_id = subtree.id
+)
+SELECT id FROM subtree`
+
+const bookISBNIndex = "idx_book_isbn"
+
+// authorColumns is the column order expected by scanAuthor.
+const authorColumns = `
+	id,
+	name,
+	birth_date,
+	death_date,
+	nationality,
+	biography,
+	alternate_names,
+	deleted_at,
+	version`
+
+const returningAuthor = `
+RETURNING` + authorColumns + `;
+`
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func measureQueryLatency(operation string, queryFunc func() error) error {
 	start := time.Now()
 	err := queryFunc()
@@ -64,15 +137,16 @@ func (p *postgresRepository) AddBook(
 	defer rollback(txErr)
 
 	const insertBook = `
-INSERT INTO book (name)
-VALUES ($1)
-RETURNING id, created_at, updated_at;
+INSERT INTO book (name, isbn, publisher, publication_year, language, page_count, description)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, created_at, updated_at, version;
 `
 
 	id := uuid.UUID{}
 	err = measureQueryLatency("insert_book", func() error {
-		return tx.QueryRow(ctx, insertBook, book.Name).Scan(
-			&id, &book.CreatedAt, &book.UpdatedAt)
+		return tx.QueryRow(ctx, insertBook, book.Name, book.ISBN, book.Publisher,
+			book.PublicationYear, book.Language, book.PageCount, book.Description).Scan(
+			&id, &book.CreatedAt, &book.UpdatedAt, &book.Version)
 	})
 
 	if err != nil {
@@ -86,12 +160,25 @@ RETURNING id, created_at, updated_at;
 		return nil, mapPostgresError(err, entity.ErrAuthorNotFound, span)
 	}
 
+	if len(book.GenreIDs) > 0 {
+		if err = replaceBookGenres(ctx, tx, book.ID, book.GenreIDs); err != nil {
+			return nil, mapPostgresError(err, entity.ErrGenreNotFound, span)
+		}
+	}
+
+	if len(book.Tags) > 0 {
+		if err = replaceBookTags(ctx, tx, book.ID, book.Tags); err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+	}
+
 	return book, nil
 }
 
 func (p *postgresRepository) GetBook(
 	ctx context.Context,
 	bookID string,
+	showDeleted bool,
 ) (*entity.Book, error) {
 	span := trace.SpanFromContext(ctx)
 
@@ -103,45 +190,62 @@ func (p *postgresRepository) GetBook(
 	)
 	log.Info("start GetBook")
 
-	const GetBook = `
-SELECT 
-  book.id, 
-  book.name, 
-  book.created_at, 
-  book.updated_at, 
-  array_agg(author_book.author_id) AS author_ids
-FROM 
-  book
-LEFT JOIN 
-  author_book ON book.id = author_book.book_id
-WHERE 
-  book.id = $1
-GROUP BY 
-  book.id;
+	const GetBook = selectBook + `
+WHERE id = $1 AND ($2::boolean OR deleted_at IS NULL);
 `
-	var book entity.Book
-	var authorIDs []uuid.UUID
+	var book *entity.Book
 
 	err := measureQueryLatency("get_book", func() error {
-		return p.db.QueryRow(ctx, GetBook, bookID).Scan(&book.ID,
-			&book.Name, &book.CreatedAt, &book.UpdatedAt, &authorIDs)
+		var err error
+		book, err = scanBook(p.db.QueryRow(ctx, GetBook, bookID, showDeleted))
+		return err
 	})
 
 	if err != nil {
 		return nil, mapPostgresError(err, entity.ErrBookNotFound, span)
 	}
 
-	book.AuthorIDs = convertUUIDsToStrings(authorIDs)
+	return book, nil
+}
 
-	return &book, nil
+func (p *postgresRepository) GetBookByISBN(
+	ctx context.Context,
+	isbn string,
+) (*entity.Book, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("isbn", isbn),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start GetBookByISBN")
+
+	const GetBookByISBN = selectBook + `
+WHERE isbn = $1 AND deleted_at IS NULL;
+`
+	var book *entity.Book
+
+	err := measureQueryLatency("get_book_by_isbn", func() error {
+		var err error
+		book, err = scanBook(p.conn(ctx).QueryRow(ctx, GetBookByISBN, isbn))
+		return err
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrBookNotFound, span)
+	}
+
+	return book, nil
 }
 
 func (p *postgresRepository) UpdateBook(
 	ctx context.Context,
 	bookID string,
-	newBookName string,
-	authorIDs []string,
-) (txErr error) {
+	update entity.BookUpdate,
+	expectedVersion int64,
+) (respBook *entity.Book, txErr error) {
 	span := trace.SpanFromContext(ctx)
 
 	log := p.logger.With(
@@ -154,21 +258,38 @@ func (p *postgresRepository) UpdateBook(
 
 	tx, rollback, err := p.beginTx(ctx)
 	if err != nil {
-		return mapPostgresError(err, err, span)
+		return nil, mapPostgresError(err, err, span)
 	}
 	defer rollback(txErr)
 
+	// The row is updated even when only the relations change so that the
+	// version and updated_at move with every change of the book.
 	const UpdateBook = `
-UPDATE book SET name = $1 WHERE id = $2;
+UPDATE book SET
+	name = COALESCE($1, name),
+	isbn = COALESCE($4, isbn),
+	publisher = COALESCE($5, publisher),
+	publication_year = COALESCE($6, publication_year),
+	language = COALESCE($7, language),
+	page_count = COALESCE($8, page_count),
+	description = COALESCE($9, description)
+WHERE id = $2 AND deleted_at IS NULL AND ($3::bigint = 0 OR version = $3)
+RETURNING version;
 `
 
+	var version int64
 	err = measureQueryLatency("update_book", func() error {
-		_, err := tx.Exec(ctx, UpdateBook, newBookName, bookID)
-		return err
+		return tx.QueryRow(ctx, UpdateBook, update.Name, bookID, expectedVersion,
+			update.ISBN, update.Publisher, update.PublicationYear, update.Language,
+			update.PageCount, update.Description).
+			Scan(&version)
 	})
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, p.updateMissError(ctx, tx, "book", bookID, entity.ErrBookNotFound, span)
+	}
 	if err != nil {
-		return mapPostgresError(err, err, span)
+		return nil, mapPostgresError(err, err, span)
 	}
 
 	const UpdateAuthorBooks = `
@@ -182,12 +303,250 @@ WHERE book_id = $2
   AND author_id NOT IN (SELECT unnest($1::uuid[]));
 `
 
-	_, err = tx.Exec(ctx, UpdateAuthorBooks, authorIDs, bookID)
+	const AddAuthorBooks = `
+INSERT INTO author_book (author_id, book_id)
+SELECT unnest($1::uuid[]), $2
+ON CONFLICT (author_id, book_id) DO NOTHING;
+`
+
+	const RemoveAuthorBooks = `
+DELETE FROM author_book
+WHERE book_id = $2
+  AND author_id = ANY($1::uuid[]);
+`
+
+	if update.ReplaceAuthors {
+		_, err = tx.Exec(ctx, UpdateAuthorBooks, update.AuthorIDs, bookID)
+		if err != nil {
+			return nil, mapPostgresError(err, entity.ErrAuthorNotFound, span)
+		}
+	}
+
+	if len(update.RemoveAuthorIDs) > 0 {
+		_, err = tx.Exec(ctx, RemoveAuthorBooks, update.RemoveAuthorIDs, bookID)
+		if err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+	}
+
+	if len(update.AddAuthorIDs) > 0 {
+		_, err = tx.Exec(ctx, AddAuthorBooks, update.AddAuthorIDs, bookID)
+		if err != nil {
+			return nil, mapPostgresError(err, entity.ErrAuthorNotFound, span)
+		}
+	}
+
+	if update.ReplaceGenres {
+		if err = replaceBookGenres(ctx, tx, bookID, update.GenreIDs); err != nil {
+			return nil, mapPostgresError(err, entity.ErrGenreNotFound, span)
+		}
+	}
+
+	if update.ReplaceTags {
+		if err = replaceBookTags(ctx, tx, bookID, update.Tags); err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+	}
+
+	book, err := scanBook(tx.QueryRow(ctx, selectBook+"WHERE id = $1;", bookID))
+	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	return book, nil
+}
+
+func (p *postgresRepository) GetBookForUpdate(
+	ctx context.Context,
+	bookID string,
+) (*entity.Book, error) {
+	const getBookForUpdate = selectBook + `
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
+`
+
+	return p.changeBook(ctx, "get_book_for_update", getBookForUpdate, bookID)
+}
+
+func (p *postgresRepository) ListBooks(
+	ctx context.Context,
+	filter entity.BooksFilter,
+	after *entity.BookCursor,
+	limit int,
+) ([]*entity.Book, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start ListBooks")
+
+	conditions := []string{"deleted_at IS NULL"}
+	args := make([]any, 0)
+	addArg := func(arg any) string {
+		args = append(args, arg)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.NamePrefix != "" {
+		conditions = append(conditions,
+			"name LIKE "+addArg(escapeLike(filter.NamePrefix)+"%"))
+	}
+	if filter.AuthorID != "" {
+		conditions = append(conditions,
+			"id IN (SELECT book_id FROM author_book WHERE author_id = "+addArg(filter.AuthorID)+")")
+	}
+	if filter.GenreID != "" {
+		conditions = append(conditions,
+			"id IN (SELECT book_id FROM book_genre WHERE genre_id IN ("+
+				fmt.Sprintf(genreSubtree, addArg(filter.GenreID))+"))")
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions,
+			"id IN (SELECT book_id FROM book_tag WHERE tag = "+addArg(filter.Tag)+")")
+	}
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= "+addArg(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < "+addArg(*filter.CreatedBefore))
+	}
+	if filter.UpdatedAfter != nil {
+		conditions = append(conditions, "updated_at >= "+addArg(*filter.UpdatedAfter))
+	}
+	if filter.UpdatedBefore != nil {
+		conditions = append(conditions, "updated_at < "+addArg(*filter.UpdatedBefore))
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.SortOrder == entity.SortOrderDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) %s (%s, %s)",
+			comparison, addArg(after.CreatedAt), addArg(after.ID)))
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	// The page is cut in a CTE first so that the (created_at, id) index
+	// drives the scan and authors are looked up only for the selected books.
+	listBooks := fmt.Sprintf(`
+WITH page AS (
+	SELECT %[4]s
+	FROM book
+	%[1]s
+	ORDER BY created_at %[2]s, id %[2]s
+	LIMIT %[3]s
+)
+SELECT
+	book.*,%[5]s
+FROM
+	page AS book
+ORDER BY
+	book.created_at %[2]s, book.id %[2]s;
+`, where, direction, addArg(limit), bookColumns, bookRelations)
+
+	var rows pgx.Rows
+	err := measureQueryLatency("list_books", func() error {
+		var err error
+		rows, err = p.db.Query(ctx, listBooks, args...)
+		return err
+	})
 	if err != nil {
-		return mapPostgresError(err, entity.ErrAuthorNotFound, span)
+		return nil, mapPostgresError(err, err, span)
 	}
+	defer rows.Close()
+
+	books := make([]*entity.Book, 0, limit)
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
 
-	return nil
+		books = append(books, book)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	return books, nil
+}
+
+func (p *postgresRepository) SoftDeleteBook(
+	ctx context.Context,
+	bookID string,
+) (*entity.Book, error) {
+	const softDeleteBook = `
+UPDATE book
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+` + returningBook
+
+	return p.changeBook(ctx, "soft_delete_book", softDeleteBook, bookID)
+}
+
+func (p *postgresRepository) RestoreBook(
+	ctx context.Context,
+	bookID string,
+) (*entity.Book, error) {
+	const restoreBook = `
+UPDATE book
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+` + returningBook
+
+	return p.changeBook(ctx, "restore_book", restoreBook, bookID)
+}
+
+func (p *postgresRepository) PurgeBook(
+	ctx context.Context,
+	bookID string,
+) (*entity.Book, error) {
+	const purgeBook = `
+DELETE FROM book
+WHERE id = $1
+` + returningBook
+
+	return p.changeBook(ctx, "purge_book", purgeBook, bookID)
+}
+
+// changeBook runs a single-row statement that yields a book in the
+// column order of selectBook and returningBook.
+func (p *postgresRepository) changeBook(
+	ctx context.Context,
+	operation string,
+	query string,
+	bookID string,
+) (*entity.Book, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("operation", operation),
+		zap.String("book_id", bookID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start changeBook")
+
+	var book *entity.Book
+	err := measureQueryLatency(operation, func() error {
+		var err error
+		book, err = scanBook(p.conn(ctx).QueryRow(ctx, query, bookID))
+		return err
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrBookNotFound, span)
+	}
+
+	return book, nil
 }
 
 func (p *postgresRepository) RegisterAuthor(
@@ -211,13 +570,15 @@ func (p *postgresRepository) RegisterAuthor(
 	defer rollback(txErr)
 
 	const InsertAuthor = `
-INSERT INTO author ( name)
-VALUES ($1)
-RETURNING id;
+INSERT INTO author (name, birth_date, death_date, nationality, biography, alternate_names)
+VALUES ($1, $2, $3, $4, $5, COALESCE($6::text[], '{}'))
+RETURNING id, version;
 `
 	id := uuid.UUID{}
 	err = measureQueryLatency("insert_author", func() error {
-		return tx.QueryRow(ctx, InsertAuthor, author.Name).Scan(&id)
+		return tx.QueryRow(ctx, InsertAuthor, author.Name, author.BirthDate, author.DeathDate,
+			author.Nationality, author.Biography, author.AlternateNames).
+			Scan(&id, &author.Version)
 	})
 
 	if err != nil {
@@ -243,30 +604,32 @@ func (p *postgresRepository) GetAuthorInfo(
 	log.Info("start GetAuthorInfo")
 
 	const GetQueryAuthor = `
-SELECT id, name
+SELECT` + authorColumns + `
 FROM author
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
 `
 
-	var author entity.Author
+	var author *entity.Author
 
 	err := measureQueryLatency("get_author_info", func() error {
-		return p.db.QueryRow(ctx, GetQueryAuthor, authorID).
-			Scan(&author.ID, &author.Name)
+		var scanErr error
+		author, scanErr = scanAuthor(p.db.QueryRow(ctx, GetQueryAuthor, authorID))
+		return scanErr
 	})
 
 	if err != nil {
 		return nil, mapPostgresError(err, entity.ErrAuthorNotFound, span)
 	}
 
-	return &author, nil
+	return author, nil
 }
 
 func (p *postgresRepository) ChangeAuthor(
 	ctx context.Context,
 	authorID string,
-	newAuthorName string,
-) (txErr error) {
+	update entity.AuthorUpdate,
+	expectedVersion int64,
+) (respAuthor *entity.Author, txErr error) {
 	span := trace.SpanFromContext(ctx)
 
 	log := p.logger.With(
@@ -279,28 +642,83 @@ func (p *postgresRepository) ChangeAuthor(
 
 	tx, rollback, err := p.beginTx(ctx)
 	if err != nil {
-		return mapPostgresError(err, err, span)
+		return nil, mapPostgresError(err, err, span)
 	}
 	defer rollback(txErr)
 
 	const UpdateAuthor = `
-UPDATE author SET name = $1 WHERE id = $2;
-`
+UPDATE author SET
+	name = COALESCE($1, name),
+	birth_date = CASE WHEN $4::boolean THEN $5::date ELSE birth_date END,
+	death_date = CASE WHEN $6::boolean THEN $7::date ELSE death_date END,
+	nationality = COALESCE($8, nationality),
+	biography = COALESCE($9, biography),
+	alternate_names = CASE WHEN $10::boolean
+		THEN COALESCE($11::text[], '{}') ELSE alternate_names END
+WHERE id = $2 AND deleted_at IS NULL AND ($3::bigint = 0 OR version = $3)
+` + returningAuthor
+
+	var author *entity.Author
 	err = measureQueryLatency("update_author", func() error {
-		_, err = tx.Exec(ctx, UpdateAuthor, newAuthorName, authorID)
-		return err
+		var scanErr error
+		author, scanErr = scanAuthor(tx.QueryRow(ctx, UpdateAuthor, update.Name, authorID, expectedVersion,
+			update.SetBirthDate, update.BirthDate, update.SetDeathDate, update.DeathDate,
+			update.Nationality, update.Biography, update.SetAlternateNames, update.AlternateNames))
+		return scanErr
 	})
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, p.updateMissError(ctx, tx, "author", authorID, entity.ErrAuthorNotFound, span)
+	}
 	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	return author, nil
+}
+
+func (p *postgresRepository) GetAuthorForUpdate(
+	ctx context.Context,
+	authorID string,
+) (*entity.Author, error) {
+	const getAuthorForUpdate = `
+SELECT` + authorColumns + `
+FROM author
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
+`
+
+	return p.changeAuthor(ctx, "get_author_for_update", getAuthorForUpdate, authorID)
+}
+
+// updateMissError tells why a conditional update matched no rows:
+// either the row is gone or its version has moved on.
+func (p *postgresRepository) updateMissError(
+	ctx context.Context,
+	tx pgx.Tx,
+	table string,
+	id string,
+	notFoundErr error,
+	span trace.Span,
+) error {
+	exists := `SELECT EXISTS (SELECT 1 FROM ` + table + ` WHERE id = $1 AND deleted_at IS NULL);`
+
+	var found bool
+	if err := tx.QueryRow(ctx, exists, id).Scan(&found); err != nil {
 		return mapPostgresError(err, err, span)
 	}
 
-	return nil
+	if found {
+		return mapPostgresError(entity.ErrVersionMismatch, entity.ErrVersionMismatch, span)
+	}
+
+	return mapPostgresError(notFoundErr, notFoundErr, span)
 }
 
 func (p *postgresRepository) GetAuthorBooks(
 	ctx context.Context,
 	authorID string,
+	showDeleted bool,
 ) ([]*entity.Book, error) {
 	span := trace.SpanFromContext(ctx)
 
@@ -311,28 +729,17 @@ func (p *postgresRepository) GetAuthorBooks(
 		zap.String("span_id", span.SpanContext().SpanID().String()),
 	)
 	log.Info("start GetAuthorBooks")
-	const GetBooksWithAuthors = `
-SELECT
-	book.id,
-	book.name,
-	book.created_at,
-	book.updated_at,
-	array_agg(author_book.author_id)
-FROM
-	book
-LEFT JOIN
-	author_book ON book.id = author_book.book_id
+	const GetBooksWithAuthors = selectBook + `
 WHERE
-	book.id IN (
+	id IN (
 		SELECT book_id
 		FROM author_book
 		WHERE author_id = $1
 	)
-GROUP BY
-	book.id;
+	AND ($2::boolean OR deleted_at IS NULL);
 `
 
-	rows, err := p.db.Query(ctx, GetBooksWithAuthors, authorID)
+	rows, err := p.conn(ctx).Query(ctx, GetBooksWithAuthors, authorID, showDeleted)
 	if err != nil {
 		return nil, mapPostgresError(err, err, span)
 	}
@@ -340,21 +747,292 @@ GROUP BY
 
 	books := make([]*entity.Book, 0)
 	for rows.Next() {
-		var book entity.Book
-		var authorIDs []uuid.UUID
-
-		if err = rows.Scan(&book.ID, &book.Name, &book.CreatedAt,
-			&book.UpdatedAt, &authorIDs); err != nil {
+		book, err := scanBook(rows)
+		if err != nil {
 			return nil, mapPostgresError(err, err, span)
 		}
 
-		book.AuthorIDs = convertUUIDsToStrings(authorIDs)
-		books = append(books, &book)
+		books = append(books, book)
 	}
 
 	return books, nil
 }
 
+func (p *postgresRepository) SoftDeleteAuthor(
+	ctx context.Context,
+	authorID string,
+) (*entity.Author, error) {
+	const softDeleteAuthor = `
+UPDATE author
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+` + returningAuthor
+
+	return p.changeAuthor(ctx, "soft_delete_author", softDeleteAuthor, authorID)
+}
+
+func (p *postgresRepository) RestoreAuthor(
+	ctx context.Context,
+	authorID string,
+) (*entity.Author, error) {
+	const restoreAuthor = `
+UPDATE author
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+` + returningAuthor
+
+	return p.changeAuthor(ctx, "restore_author", restoreAuthor, authorID)
+}
+
+func (p *postgresRepository) PurgeAuthor(
+	ctx context.Context,
+	authorID string,
+) (*entity.Author, error) {
+	const purgeAuthor = `
+DELETE FROM author
+WHERE id = $1
+` + returningAuthor
+
+	return p.changeAuthor(ctx, "purge_author", purgeAuthor, authorID)
+}
+
+// changeAuthor runs a single-row statement that yields an author in the
+// column order of returningAuthor.
+func (p *postgresRepository) changeAuthor(
+	ctx context.Context,
+	operation string,
+	query string,
+	authorID string,
+) (*entity.Author, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("operation", operation),
+		zap.String("author_id", authorID),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start changeAuthor")
+
+	var author *entity.Author
+	err := measureQueryLatency(operation, func() error {
+		var scanErr error
+		author, scanErr = scanAuthor(p.conn(ctx).QueryRow(ctx, query, authorID))
+		return scanErr
+	})
+
+	if err != nil {
+		return nil, mapPostgresError(err, entity.ErrAuthorNotFound, span)
+	}
+
+	return author, nil
+}
+
+func (p *postgresRepository) ListAuthors(
+	ctx context.Context,
+	filter entity.AuthorsFilter,
+	after *entity.AuthorCursor,
+	limit int,
+) ([]*entity.Author, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start ListAuthors")
+
+	conditions := []string{"deleted_at IS NULL"}
+	args := make([]any, 0)
+	addArg := func(arg any) string {
+		args = append(args, arg)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.NameQuery != "" {
+		pattern := escapeLike(filter.NameQuery) + "%"
+		if filter.NameMatch == entity.NameMatchSubstring {
+			pattern = "%" + pattern
+		}
+		conditions = append(conditions, "lower(name) LIKE lower("+addArg(pattern)+")")
+	}
+
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(lower(name), id) > (lower(%s), %s)",
+			addArg(after.Name), addArg(after.ID)))
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	listAuthors := fmt.Sprintf(`
+WITH page AS (
+	SELECT id, name
+	FROM author
+	%[1]s
+	ORDER BY lower(name), id
+	LIMIT %[2]s
+)
+SELECT
+	page.id,
+	page.name,
+	count(book.id)
+FROM
+	page
+LEFT JOIN
+	author_book ON page.id = author_book.author_id
+LEFT JOIN
+	book ON book.id = author_book.book_id AND book.deleted_at IS NULL
+GROUP BY
+	page.id, page.name
+ORDER BY
+	lower(page.name), page.id;
+`, where, addArg(limit))
+
+	var rows pgx.Rows
+	err := measureQueryLatency("list_authors", func() error {
+		var err error
+		rows, err = p.db.Query(ctx, listAuthors, args...)
+		return err
+	})
+	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+	defer rows.Close()
+
+	authors := make([]*entity.Author, 0, limit)
+	for rows.Next() {
+		var author entity.Author
+
+		if err = rows.Scan(&author.ID, &author.Name, &author.BookCount); err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+
+		authors = append(authors, &author)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	return authors, nil
+}
+
+// SearchCatalog ranks books and authors by full-text relevance. Names
+// that don't match as words but are close to the query by trigram word
+// similarity are returned too, so that misspelled queries still find them.
+func (p *postgresRepository) SearchCatalog(
+	ctx context.Context,
+	filter entity.SearchFilter,
+	offset int,
+	limit int,
+) ([]*entity.SearchResult, error) {
+	span := trace.SpanFromContext(ctx)
+
+	log := p.logger.With(
+		zap.String("layer", "postgres"),
+		zap.String("trace_id", span.SpanContext().TraceID().String()),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	)
+	log.Info("start SearchCatalog")
+
+	// Snippets are built for the page only. The text is HTML-escaped
+	// first, so the <b></b> around the hits is the only markup in them.
+	searchCatalog := `
+WITH query AS (
+	SELECT websearch_to_tsquery('simple', $1) AS ts, $1::text AS raw
+),
+matches AS (
+	SELECT
+		'book' AS kind,
+		book.id,
+		book.name,
+		book.name || ' ' || book.description AS document,
+		GREATEST(ts_rank_cd(book.search_vector, query.ts),
+			word_similarity(query.raw, book.name)) AS score
+	FROM book, query
+	WHERE book.deleted_at IS NULL
+	  AND (book.search_vector @@ query.ts OR query.raw <% book.name)
+	  AND ($4::uuid IS NULL OR book.id IN (
+		SELECT book_id FROM book_genre WHERE genre_id IN (` + fmt.Sprintf(genreSubtree, "$4") + `)))
+	UNION ALL
+	SELECT
+		'author',
+		author.id,
+		author.name,
+		author.name || ' ' || array_to_string(author.alternate_names, ' '),
+		GREATEST(ts_rank_cd(author.search_vector, query.ts),
+			word_similarity(query.raw, author.name))
+	FROM author, query
+	WHERE author.deleted_at IS NULL
+	  AND (author.search_vector @@ query.ts OR query.raw <% author.name)
+	  AND $4::uuid IS NULL
+),
+page AS (
+	SELECT kind, id, name, document, score
+	FROM matches
+	ORDER BY score DESC, kind, id
+	LIMIT $2 OFFSET $3
+)
+SELECT
+	kind,
+	id,
+	name,
+	ts_headline('simple',
+		replace(replace(replace(document, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), query.ts,
+		'MaxFragments=2, MaxWords=20, MinWords=5'),
+	score::float8
+FROM page, query
+ORDER BY score DESC, kind, id;
+`
+
+	var rows pgx.Rows
+	err := measureQueryLatency("search_catalog", func() error {
+		var err error
+		rows, err = p.db.Query(ctx, searchCatalog, filter.Query, limit, offset,
+			optionalUUID(filter.GenreID))
+		return err
+	})
+	if err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+	defer rows.Close()
+
+	results := make([]*entity.SearchResult, 0, limit)
+	for rows.Next() {
+		var (
+			result entity.SearchResult
+			kind   string
+		)
+
+		if err = rows.Scan(&kind, &result.ID, &result.Name, &result.Snippet, &result.Score); err != nil {
+			return nil, mapPostgresError(err, err, span)
+		}
+
+		if kind == "author" {
+			result.Kind = entity.SearchResultAuthor
+		}
+
+		results = append(results, &result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, mapPostgresError(err, err, span)
+	}
+
+	return results, nil
+}
+
+// conn returns the transaction from ctx if there is one.
+func (p *postgresRepository) conn(ctx context.Context) PgxIface {
+	if tx, err := extractTx(ctx); err == nil {
+		return tx
+	}
+
+	return p.db
+}
+
 func (p *postgresRepository) beginTx(
 	ctx context.Context,
 ) (pgx.Tx, func(txErr error), error) {
@@ -404,6 +1082,80 @@ func bulkInsertInAuthorBook(
 	return err
 }
 
+func scanBook(row pgx.Row) (*entity.Book, error) {
+	var book entity.Book
+	var authorIDs, genreIDs []uuid.UUID
+
+	if err := row.Scan(&book.ID, &book.Name, &book.ISBN, &book.Publisher,
+		&book.PublicationYear, &book.Language, &book.PageCount, &book.Description,
+		&book.CreatedAt, &book.UpdatedAt, &book.DeletedAt, &book.Version,
+		&authorIDs, &genreIDs, &book.Tags); err != nil {
+		return nil, err
+	}
+
+	book.AuthorIDs = convertUUIDsToStrings(authorIDs)
+	book.GenreIDs = convertUUIDsToStrings(genreIDs)
+	return &book, nil
+}
+
+// replaceBookGenres sets the genres of a book to exactly genreIDs.
+func replaceBookGenres(ctx context.Context, tx pgx.Tx, bookID string, genreIDs []string) error {
+	const replaceGenres = `
+WITH inserted AS (
+	INSERT INTO book_genre (genre_id, book_id)
+	SELECT unnest($1::uuid[]), $2
+	ON CONFLICT (genre_id, book_id) DO NOTHING
+)
+DELETE FROM book_genre
+WHERE book_id = $2
+  AND genre_id NOT IN (SELECT unnest($1::uuid[]));
+`
+
+	_, err := tx.Exec(ctx, replaceGenres, genreIDs, bookID)
+	return err
+}
+
+// replaceBookTags sets the tags of a book to exactly tags.
+func replaceBookTags(ctx context.Context, tx pgx.Tx, bookID string, tags []string) error {
+	const replaceTags = `
+WITH inserted AS (
+	INSERT INTO book_tag (book_id, tag)
+	SELECT $2, unnest($1::text[])
+	ON CONFLICT (book_id, tag) DO NOTHING
+)
+DELETE FROM book_tag
+WHERE book_id = $2
+  AND tag NOT IN (SELECT unnest($1::text[]));
+`
+
+	_, err := tx.Exec(ctx, replaceTags, tags, bookID)
+	return err
+}
+
+func optionalUUID(id string) *string {
+	if id == "" {
+		return nil
+	}
+
+	return &id
+}
+
+func scanAuthor(row pgx.Row) (*entity.Author, error) {
+	var author entity.Author
+
+	if err := row.Scan(&author.ID, &author.Name, &author.BirthDate, &author.DeathDate,
+		&author.Nationality, &author.Biography, &author.AlternateNames,
+		&author.DeletedAt, &author.Version); err != nil {
+		return nil, err
+	}
+
+	return &author, nil
+}
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
 func convertUUIDsToStrings(uuids []uuid.UUID) []string {
 	strs := make([]string, len(uuids))
 	for i, id := range uuids {
@@ -423,8 +1175,38 @@ func mapPostgresError(err error, notFoundErr error, span trace.Span) (returnErr
 	if errors.Is(err, sql.ErrNoRows) {
 		return notFoundErr
 	}
-	if errors.As(err, &ErrForeignKeyViolation) {
-		return notFoundErr
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		switch pgErr.ConstraintName {
+		case bookISBNIndex:
+			return entity.ErrISBNAlreadyExists
+		case genreParentNameIndex:
+			return entity.ErrGenreAlreadyExists
+		case copyBarcodeIndex:
+			return entity.ErrBarcodeAlreadyExists
+		case patronCardNumberIndex:
+			return entity.ErrCardNumberAlreadyExists
+		case loanOpenCopyIndex:
+			return entity.ErrCopyNotAvailable
+		case holdOpenPatronBookIndex:
+			return entity.ErrHoldAlreadyExists
+		}
+	}
+
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case foreignKeyViolation:
+			switch pgErr.ConstraintName {
+			case loanCopyForeignKey:
+				return entity.ErrCopyHasLoans
+			case loanBookForeignKey:
+				return entity.ErrBookHasLoans
+			}
+			return notFoundErr
+		case serializationFailure, deadlockDetected:
+			return entity.ErrConcurrentUpdate
+		}
 	}
 
 	return err
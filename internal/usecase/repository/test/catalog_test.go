@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func TestListCatalogChanges(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rows    *pgxmock.Rows
+		mockErr error
+		want    []*repository.CatalogChange
+		wantErr bool
+	}{
+		{
+			name: "list changes",
+			rows: pgxmock.NewRows([]string{"position", "kind", "entity_id", "data", "trace_id", "created_at"}).
+				AddRow(int64(4), repository.OutboxKindBook, "book-1", []byte("change4"), "trace1", createdAt).
+				AddRow(int64(5), repository.OutboxKindAuthorDeleted, "author-1", []byte("change5"), "", createdAt),
+			want: []*repository.CatalogChange{
+				{
+					Position:  4,
+					Kind:      repository.OutboxKindBook,
+					EntityID:  "book-1",
+					RawData:   []byte("change4"),
+					TraceID:   "trace1",
+					CreatedAt: createdAt,
+				},
+				{
+					Position:  5,
+					Kind:      repository.OutboxKindAuthorDeleted,
+					EntityID:  "author-1",
+					RawData:   []byte("change5"),
+					CreatedAt: createdAt,
+				},
+			},
+		},
+		{
+			name: "list changes | caught up",
+			rows: pgxmock.NewRows([]string{"position", "kind", "entity_id", "data", "trace_id", "created_at"}),
+			want: []*repository.CatalogChange{},
+		},
+		{
+			name:    "list changes | database error",
+			mockErr: fmt.Errorf("database error"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			logger, _ := zap.NewProduction()
+			changes := repository.NewCatalogChanges(mockDB, logger)
+			ctx := t.Context()
+
+			query := mockDB.ExpectQuery("SELECT position, .* FROM catalog_change WHERE position > \\$1 ORDER BY position").
+				WithArgs(int64(3), 2)
+			if tt.mockErr != nil {
+				query.WillReturnError(tt.mockErr)
+			} else {
+				query.WillReturnRows(tt.rows)
+			}
+
+			got, err := changes.ListChanges(ctx, 3, 2)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+
+			require.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
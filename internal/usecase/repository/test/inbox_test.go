@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/project/library/internal/usecase/repository"
+)
+
+func TestReceiveMessage(t *testing.T) {
+	t.Parallel()
+
+	messageID := "acq-1"
+	kind := repository.InboxKindBookPurchased
+	message := []byte(`{"name":"Dune"}`)
+	traceID := "test-trace-id"
+
+	tests := []struct {
+		name      string
+		inserted  int64
+		insertErr error
+		want      bool
+		wantErr   bool
+	}{
+		{
+			name:     "receive message",
+			inserted: 1,
+			want:     true,
+		},
+		{
+			name:     "receive message | duplicate",
+			inserted: 0,
+			want:     false,
+		},
+		{
+			name:      "receive message | failure",
+			insertErr: fmt.Errorf("test error"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			logger, _ := zap.NewProduction()
+			inboxRepo := repository.NewInbox(mockDB, logger)
+			ctx := t.Context()
+
+			insert := mockDB.ExpectExec("INSERT INTO inbox .* ON CONFLICT \\(message_id\\) DO NOTHING").
+				WithArgs(messageID, kind, message, traceID)
+			if tt.insertErr != nil {
+				insert.WillReturnError(tt.insertErr)
+			} else {
+				insert.WillReturnResult(pgxmock.NewResult("INSERT", tt.inserted))
+			}
+
+			got, err := inboxRepo.ReceiveMessage(ctx, messageID, kind, message, traceID)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+
+			require.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetInboxMessages(t *testing.T) {
+	t.Parallel()
+
+	mockDB, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	logger, _ := zap.NewProduction()
+	inboxRepo := repository.NewInbox(mockDB, logger)
+	ctx := t.Context()
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mockDB.ExpectQuery("UPDATE inbox SET status = 'IN_PROGRESS', attempts = attempts \\+ 1").
+		WithArgs("5000 ms", 2).
+		WillReturnRows(pgxmock.NewRows([]string{"message_id", "kind", "data", "trace_id", "attempts", "created_at"}).
+			AddRow("acq-1", repository.InboxKindBookPurchased, []byte("message1"), "trace1", 1, createdAt).
+			AddRow("acq-2", repository.InboxKindAuthorMerged, []byte("message2"), "", 2, createdAt))
+
+	got, err := inboxRepo.GetMessages(ctx, 2, 5*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, []repository.InboxData{
+		{
+			MessageID: "acq-1",
+			Kind:      repository.InboxKindBookPurchased,
+			RawData:   []byte("message1"),
+			TraceID:   "trace1",
+			Attempts:  1,
+			CreatedAt: createdAt,
+		},
+		{
+			MessageID: "acq-2",
+			Kind:      repository.InboxKindAuthorMerged,
+			RawData:   []byte("message2"),
+			Attempts:  2,
+			CreatedAt: createdAt,
+		},
+	}, got)
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestMarkInboxMessageAsProcessed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		updated int64
+		mockErr error
+		wantErr error
+	}{
+		{
+			name:    "mark as processed",
+			updated: 1,
+		},
+		{
+			name:    "mark as processed | claimed by another worker",
+			updated: 0,
+			wantErr: repository.ErrInboxMessageReclaimed,
+		},
+		{
+			name:    "mark as processed | database error",
+			mockErr: fmt.Errorf("database error"),
+			wantErr: fmt.Errorf("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			logger, _ := zap.NewProduction()
+			inboxRepo := repository.NewInbox(mockDB, logger)
+			ctx := t.Context()
+
+			update := mockDB.ExpectExec("UPDATE inbox SET status = 'SUCCESS'").
+				WithArgs("acq-1", 2)
+			if tt.mockErr != nil {
+				update.WillReturnError(tt.mockErr)
+			} else {
+				update.WillReturnResult(pgxmock.NewResult("UPDATE", tt.updated))
+			}
+
+			err = inboxRepo.MarkAsProcessed(ctx, "acq-1", 2)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMarkInboxMessageAsFailed(t *testing.T) {
+	t.Parallel()
+
+	mockDB, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	logger, _ := zap.NewProduction()
+	inboxRepo := repository.NewInbox(mockDB, logger)
+	ctx := t.Context()
+
+	mockDB.ExpectExec("UPDATE inbox SET status = 'FAILED'.* AND attempts = \\$2").
+		WithArgs("acq-1", 1, "author not found", "2000 ms").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mockDB.ExpectExec("UPDATE inbox SET status = 'DEAD'.* AND attempts = \\$2").
+		WithArgs("acq-2", 3, "author not found").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	// The message was claimed again meanwhile.
+	mockDB.ExpectExec("UPDATE inbox SET status = 'FAILED'").
+		WithArgs("acq-3", 1, "author not found", "2000 ms").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mockDB.ExpectExec("UPDATE inbox SET status = 'DEAD'").
+		WithArgs("acq-4", 3, "author not found").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	require.NoError(t, inboxRepo.MarkAsFailed(ctx, "acq-1", 1, "author not found", 2*time.Second))
+	require.NoError(t, inboxRepo.MarkAsDead(ctx, "acq-2", 3, "author not found"))
+	require.ErrorIs(t, inboxRepo.MarkAsFailed(ctx, "acq-3", 1, "author not found", 2*time.Second),
+		repository.ErrInboxMessageReclaimed)
+	require.ErrorIs(t, inboxRepo.MarkAsDead(ctx, "acq-4", 3, "author not found"),
+		repository.ErrInboxMessageReclaimed)
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
@@ -5,11 +5,11 @@ import (
 	"testing"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/project/library/internal/entity"
 	"github.com/project/library/internal/usecase/repository"
 )
 
@@ -17,21 +17,56 @@ func TestSendMassage(t *testing.T) {
 	t.Parallel()
 
 	idempotencyKey := "test-key"
-	kind := repository.OutboxKindBook
+	aggregateKey := "book-1"
 	message := []byte("test-message")
 	traceID := "test-trace-id"
 
 	tests := []struct {
-		name    string
-		wantErr error
+		name        string
+		kind        repository.OutboxKind
+		inserted    int64
+		insertErr   error
+		sequenceErr error
+		changeErr   error
+		wantChange  bool
+		wantErr     bool
 	}{
 		{
-			name:    "send massage",
-			wantErr: nil,
+			name:       "send massage",
+			kind:       repository.OutboxKindBook,
+			inserted:   1,
+			wantChange: true,
+		},
+		{
+			name:     "send massage | not a catalog change",
+			kind:     repository.OutboxKindCopy,
+			inserted: 1,
+		},
+		{
+			name:     "send massage | duplicate takes no sequence",
+			kind:     repository.OutboxKindBook,
+			inserted: 0,
+		},
+		{
+			name:      "send massage | failure",
+			kind:      repository.OutboxKindBook,
+			insertErr: fmt.Errorf("test error"),
+			wantErr:   true,
+		},
+		{
+			name:        "send massage | sequence failure",
+			kind:        repository.OutboxKindBook,
+			inserted:    1,
+			sequenceErr: fmt.Errorf("test error"),
+			wantErr:     true,
 		},
 		{
-			name:    "send massage | failure",
-			wantErr: fmt.Errorf("test error"),
+			name:       "send massage | change log failure",
+			kind:       repository.OutboxKindAuthorUpdated,
+			inserted:   1,
+			changeErr:  fmt.Errorf("test error"),
+			wantChange: true,
+			wantErr:    true,
 		},
 	}
 
@@ -49,18 +84,36 @@ func TestSendMassage(t *testing.T) {
 			outboxRepo := repository.NewOutbox(mockDB, logger)
 			ctx := t.Context()
 
-			if tt.wantErr != nil {
-				mockDB.ExpectExec("INSERT INTO outbox").
-					WithArgs(idempotencyKey, message, kind, traceID).
-					WillReturnError(tt.wantErr)
+			insert := mockDB.ExpectExec("INSERT INTO outbox").
+				WithArgs(idempotencyKey, message, tt.kind, traceID, aggregateKey)
+			if tt.insertErr != nil {
+				insert.WillReturnError(tt.insertErr)
 			} else {
-				mockDB.ExpectExec("INSERT INTO outbox").
-					WithArgs(idempotencyKey, message, kind, traceID).
-					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				insert.WillReturnResult(pgxmock.NewResult("INSERT", tt.inserted))
 			}
 
-			err = outboxRepo.SendMessage(ctx, idempotencyKey, kind, message, traceID)
-			if tt.wantErr != nil {
+			if tt.inserted > 0 {
+				sequence := mockDB.ExpectExec("INSERT INTO outbox_aggregate .* UPDATE outbox SET sequence").
+					WithArgs(idempotencyKey, aggregateKey)
+				if tt.sequenceErr != nil {
+					sequence.WillReturnError(tt.sequenceErr)
+				} else {
+					sequence.WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				}
+			}
+
+			if tt.wantChange {
+				change := mockDB.ExpectExec("UPDATE catalog_change_position .* INSERT INTO catalog_change").
+					WithArgs(tt.kind, aggregateKey, message, traceID)
+				if tt.changeErr != nil {
+					change.WillReturnError(tt.changeErr)
+				} else {
+					change.WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				}
+			}
+
+			err = outboxRepo.SendMessage(ctx, idempotencyKey, tt.kind, aggregateKey, message, traceID)
+			if tt.wantErr {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
@@ -74,6 +127,8 @@ func TestSendMassage(t *testing.T) {
 func TestGetMessages(t *testing.T) {
 	t.Parallel()
 
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name          string
 		batchSize     int
@@ -87,21 +142,28 @@ func TestGetMessages(t *testing.T) {
 			name:          "get messages",
 			batchSize:     2,
 			inProgressTTL: 5 * time.Second,
-			returnRows: pgxmock.NewRows([]string{"idempotency_key", "data", "kind", "trace_id"}).
-				AddRow("key1", []byte("message1"), repository.OutboxKindBook, "trace1").
-				AddRow("key2", []byte("message2"), repository.OutboxKindBook, "trace2"),
+			returnRows: pgxmock.NewRows([]string{"idempotency_key", "data", "kind", "trace_id", "attempts", "created_at",
+				"aggregate_key", "sequence"}).
+				AddRow("key1", []byte("message1"), repository.OutboxKindBook, "trace1", 1, createdAt, "book-1", int64(1)).
+				AddRow("key2", []byte("message2"), repository.OutboxKindBook, "trace2", 3, createdAt, "", int64(0)),
 			expectedData: []repository.OutboxData{
 				{
 					IdempotencyKey: "key1",
 					RawData:        []byte("message1"),
 					Kind:           repository.OutboxKindBook,
 					TraceID:        "trace1",
+					Attempts:       1,
+					CreatedAt:      createdAt,
+					AggregateKey:   "book-1",
+					Sequence:       1,
 				},
 				{
 					IdempotencyKey: "key2",
 					RawData:        []byte("message2"),
 					Kind:           repository.OutboxKindBook,
 					TraceID:        "trace2",
+					Attempts:       3,
+					CreatedAt:      createdAt,
 				},
 			},
 			wantErr: false,
@@ -119,9 +181,10 @@ func TestGetMessages(t *testing.T) {
 			name:          "get messages | scan error",
 			batchSize:     2,
 			inProgressTTL: 5 * time.Second,
-			returnRows: pgxmock.NewRows([]string{"idempotency_key", "data", "kind", "trace_id"}).
-				AddRow("key1", []byte("message1"), repository.OutboxKindBook, "trace1").
-				AddRow("key2", nil, "1", "trace2"),
+			returnRows: pgxmock.NewRows([]string{"idempotency_key", "data", "kind", "trace_id", "attempts", "created_at",
+				"aggregate_key", "sequence"}).
+				AddRow("key1", []byte("message1"), repository.OutboxKindBook, "trace1", 1, createdAt, "book-1", int64(1)).
+				AddRow("key2", nil, "1", "trace2", 1, createdAt, "", int64(0)),
 			expectedData: nil,
 			wantErr:      true,
 		},
@@ -164,28 +227,122 @@ func TestGetMessages(t *testing.T) {
 	}
 }
 
+func TestGetOrderedMessages(t *testing.T) {
+	t.Parallel()
+
+	mockDB, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	logger, _ := zap.NewProduction()
+	outboxRepo := repository.NewOutbox(mockDB, logger)
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mockDB.ExpectQuery(`UPDATE outbox .* NOT EXISTS \( SELECT 1 FROM outbox earlier `+
+		`WHERE earlier.aggregate_key = outbox.aggregate_key AND earlier.sequence < outbox.sequence `+
+		`AND earlier.status <> 'SUCCESS'\)`).
+		WithArgs("5000 ms", 10).
+		WillReturnRows(pgxmock.NewRows([]string{"idempotency_key", "data", "kind", "trace_id", "attempts",
+			"created_at", "aggregate_key", "sequence"}).
+			AddRow("key1", []byte("message1"), repository.OutboxKindBookUpdated, "trace1", 1,
+				createdAt, "book-1", int64(2)))
+
+	data, err := outboxRepo.GetOrderedMessages(t.Context(), 10, 5*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, []repository.OutboxData{
+		{
+			IdempotencyKey: "key1",
+			RawData:        []byte("message1"),
+			Kind:           repository.OutboxKindBookUpdated,
+			TraceID:        "trace1",
+			Attempts:       1,
+			CreatedAt:      createdAt,
+			AggregateKey:   "book-1",
+			Sequence:       2,
+		},
+	}, data)
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
 func TestMarkAsProcessed(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name            string
-		idempotencyKeys []string
-		mockErr         error
-		wantErr         bool
-		returnResult    pgconn.CommandTag
+		name    string
+		mockErr error
+		rows    int64
+		wantErr error
 	}{
 		{
-			name:            "mark as processed successfully",
-			idempotencyKeys: []string{"key1", "key2"},
-			mockErr:         nil,
-			wantErr:         false,
+			name: "mark as processed successfully",
+			rows: 1,
+		},
+		{
+			name:    "mark as processed | reclaimed",
+			wantErr: repository.ErrOutboxMessageReclaimed,
+		},
+		{
+			name:    "database error",
+			mockErr: fmt.Errorf("database error"),
+			wantErr: fmt.Errorf("database error"),
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			logger, _ := zap.NewProduction()
+			outboxRepo := repository.NewOutbox(mockDB, logger)
+			ctx := t.Context()
 
+			expect := mockDB.ExpectExec("UPDATE outbox SET status = 'SUCCESS' WHERE idempotency_key = \\$1 "+
+				"AND status = 'IN_PROGRESS' AND attempts = \\$2").
+				WithArgs("key1", 2)
+			if tt.mockErr != nil {
+				expect.WillReturnError(tt.mockErr)
+			} else {
+				expect.WillReturnResult(pgxmock.NewResult("UPDATE", tt.rows))
+			}
+
+			err = outboxRepo.MarkAsProcessed(ctx, "key1", 2)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMarkAsFailed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mockErr error
+		rows    int64
+		wantErr error
+	}{
+		{
+			name: "mark as failed",
+			rows: 1,
+		},
 		{
-			name:            "database error",
-			idempotencyKeys: []string{"key1", "key2"},
-			mockErr:         fmt.Errorf("database error"),
-			wantErr:         true,
+			name:    "mark as failed | reclaimed",
+			wantErr: repository.ErrOutboxMessageReclaimed,
+		},
+		{
+			name:    "mark as failed | database error",
+			mockErr: fmt.Errorf("database error"),
+			wantErr: fmt.Errorf("database error"),
 		},
 	}
 
@@ -201,25 +358,289 @@ func TestMarkAsProcessed(t *testing.T) {
 			outboxRepo := repository.NewOutbox(mockDB, logger)
 			ctx := t.Context()
 
+			expect := mockDB.ExpectExec("UPDATE outbox SET status = 'FAILED'.* "+
+				"AND status = 'IN_PROGRESS' AND attempts = \\$2").
+				WithArgs("key1", 2, "connection refused", "1500 ms")
 			if tt.mockErr != nil {
-				mockDB.ExpectExec("UPDATE outbox").
-					WithArgs(tt.idempotencyKeys).
-					WillReturnError(tt.mockErr)
+				expect.WillReturnError(tt.mockErr)
 			} else {
-				mockDB.ExpectExec("UPDATE outbox").
-					WithArgs(tt.idempotencyKeys).
-					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				expect.WillReturnResult(pgxmock.NewResult("UPDATE", tt.rows))
 			}
 
-			err = outboxRepo.MarkAsProcessed(ctx, tt.idempotencyKeys)
+			err = outboxRepo.MarkAsFailed(ctx, "key1", 2, "connection refused", 1500*time.Millisecond)
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
 
-			if tt.wantErr {
+			require.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMarkAsDead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mockErr error
+		rows    int64
+		wantErr error
+	}{
+		{
+			name: "mark as dead",
+			rows: 1,
+		},
+		{
+			name:    "mark as dead | reclaimed",
+			wantErr: repository.ErrOutboxMessageReclaimed,
+		},
+		{
+			name:    "mark as dead | database error",
+			mockErr: fmt.Errorf("database error"),
+			wantErr: fmt.Errorf("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			logger, _ := zap.NewProduction()
+			outboxRepo := repository.NewOutbox(mockDB, logger)
+			ctx := t.Context()
+
+			expect := mockDB.ExpectExec("UPDATE outbox SET status = 'DEAD'.* "+
+				"AND status = 'IN_PROGRESS' AND attempts = \\$2").
+				WithArgs("key1", 2, "unsupported outbox kind: 0")
+			if tt.mockErr != nil {
+				expect.WillReturnError(tt.mockErr)
+			} else {
+				expect.WillReturnResult(pgxmock.NewResult("UPDATE", tt.rows))
+			}
+
+			err = outboxRepo.MarkAsDead(ctx, "key1", 2, "unsupported outbox kind: 0")
+			if tt.wantErr != nil {
+				require.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func outboxMessageRows() *pgxmock.Rows {
+	return pgxmock.NewRows([]string{"idempotency_key", "kind", "status", "data", "trace_id",
+		"attempts", "next_attempt_at", "last_error", "created_at", "updated_at"})
+}
+
+func TestListMessages(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+	createdBefore := createdAt.Add(time.Hour)
+
+	mockDB, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	logger, _ := zap.NewProduction()
+	outboxRepo := repository.NewOutbox(mockDB, logger)
+	ctx := t.Context()
+
+	filter := repository.OutboxFilter{
+		Status:        repository.OutboxStatusDead,
+		Kind:          repository.OutboxKindBookUpdated,
+		CreatedBefore: createdBefore,
+	}
+	after := &repository.OutboxCursor{CreatedAt: createdAt, IdempotencyKey: "key0"}
+
+	mockDB.ExpectQuery(`WHERE status = \$1 AND kind = \$2 AND created_at < \$3 `+
+		`AND \(created_at, idempotency_key\) > \(\$4, \$5\) ORDER BY created_at, idempotency_key LIMIT \$6`).
+		WithArgs("DEAD", repository.OutboxKindBookUpdated, createdBefore, createdAt, "key0", 11).
+		WillReturnRows(outboxMessageRows().
+			AddRow("key1", repository.OutboxKindBookUpdated, "DEAD", []byte(`{}`), "trace1",
+				10, createdAt, "non success response: 503", createdAt, createdAt))
+
+	messages, err := outboxRepo.ListMessages(ctx, filter, after, 11)
+	require.NoError(t, err)
+	require.Equal(t, []*repository.OutboxMessage{{
+		IdempotencyKey: "key1",
+		Kind:           repository.OutboxKindBookUpdated,
+		Status:         repository.OutboxStatusDead,
+		RawData:        []byte(`{}`),
+		TraceID:        "trace1",
+		Attempts:       10,
+		NextAttemptAt:  createdAt,
+		LastError:      "non success response: 503",
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+	}}, messages)
+
+	mockDB.ExpectQuery(`FROM outbox ORDER BY created_at, idempotency_key LIMIT \$1`).
+		WithArgs(50).
+		WillReturnRows(outboxMessageRows())
+
+	messages, err = outboxRepo.ListMessages(ctx, repository.OutboxFilter{}, nil, 50)
+	require.NoError(t, err)
+	require.Empty(t, messages)
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestGetMessage(t *testing.T) {
+	t.Parallel()
+
+	mockDB, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	logger, _ := zap.NewProduction()
+	outboxRepo := repository.NewOutbox(mockDB, logger)
+	ctx := t.Context()
+
+	now := time.Now()
+	mockDB.ExpectQuery("FROM outbox WHERE idempotency_key").
+		WithArgs("key1").
+		WillReturnRows(outboxMessageRows().
+			AddRow("key1", repository.OutboxKindBook, "SUCCESS", []byte(`{}`), "",
+				1, now, "", now, now))
+
+	message, err := outboxRepo.GetMessage(ctx, "key1")
+	require.NoError(t, err)
+	require.Equal(t, repository.OutboxStatusSuccess, message.Status)
+
+	mockDB.ExpectQuery("FROM outbox WHERE idempotency_key").
+		WithArgs("key2").
+		WillReturnRows(outboxMessageRows())
+
+	_, err = outboxRepo.GetMessage(ctx, "key2")
+	require.ErrorIs(t, err, entity.ErrOutboxMessageNotFound)
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestRetryDeadMessages(t *testing.T) {
+	t.Parallel()
+
+	mockDB, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	logger, _ := zap.NewProduction()
+	outboxRepo := repository.NewOutbox(mockDB, logger)
+	ctx := t.Context()
+
+	mockDB.ExpectExec("UPDATE outbox SET status = 'CREATED', attempts = 0").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 3))
+
+	retried, err := outboxRepo.RetryDeadMessages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), retried)
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestPurgeProcessed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mockErr error
+	}{
+		{
+			name: "purge processed",
+		},
+		{
+			name:    "purge processed | database error",
+			mockErr: fmt.Errorf("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			logger, _ := zap.NewProduction()
+			outboxRepo := repository.NewOutbox(mockDB, logger)
+			ctx := t.Context()
+
+			expect := mockDB.ExpectExec("DELETE FROM outbox WHERE idempotency_key IN").
+				WithArgs("86400000 ms", 1000)
+			if tt.mockErr != nil {
+				expect.WillReturnError(tt.mockErr)
+			} else {
+				expect.WillReturnResult(pgxmock.NewResult("DELETE", 5))
+			}
+
+			deleted, err := outboxRepo.PurgeProcessed(ctx, 24*time.Hour, 1000)
+			if tt.mockErr != nil {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
+				require.Equal(t, int64(5), deleted)
 			}
 
 			require.NoError(t, mockDB.ExpectationsWereMet())
 		})
 	}
 }
+
+func TestOutboxKindEventType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		kind repository.OutboxKind
+		want string
+	}{
+		{kind: repository.OutboxKindBook, want: "library.book.created"},
+		{kind: repository.OutboxKindAuthor, want: "library.author.created"},
+		{kind: repository.OutboxKindBookDeleted, want: "library.book.deleted"},
+		{kind: repository.OutboxKindAuthorUpdated, want: "library.author.updated"},
+		{kind: repository.OutboxKindLoanCheckedOut, want: "library.loan.checked_out"},
+		{kind: repository.OutboxKindHoldReady, want: "library.hold.ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind.String(), func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, tt.kind.EventType())
+		})
+	}
+}
+
+func TestOutboxKindCatalog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		kind repository.OutboxKind
+		want bool
+	}{
+		{kind: repository.OutboxKindBook, want: true},
+		{kind: repository.OutboxKindBookPurged, want: true},
+		{kind: repository.OutboxKindAuthorUpdated, want: true},
+		{kind: repository.OutboxKindCopy, want: false},
+		{kind: repository.OutboxKindLoanCheckedOut, want: false},
+		{kind: repository.OutboxKindHoldReady, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind.String(), func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, tt.kind.Catalog())
+		})
+	}
+}
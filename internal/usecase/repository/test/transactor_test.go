@@ -73,3 +73,88 @@ func TestWithTx(t *testing.T) {
 		})
 	}
 }
+
+func TestWithTxAppendsCatalogChangesLast(t *testing.T) {
+	t.Parallel()
+
+	mockDB, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	logger, _ := zap.NewProduction()
+	transactor := repository.NewTransactor(mockDB, logger)
+	outboxRepo := repository.NewOutbox(mockDB, logger)
+	ctx := t.Context()
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec("INSERT INTO outbox").WithArgs(anyArgs(5)...).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockDB.ExpectExec("INSERT INTO outbox_aggregate").WithArgs(anyArgs(2)...).WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	// A lock taken after the first message, e.g. the next book of an
+	// author merge, comes before the position.
+	mockDB.ExpectExec("SELECT 1 FROM book").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mockDB.ExpectExec("INSERT INTO outbox").WithArgs(anyArgs(5)...).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockDB.ExpectExec("INSERT INTO outbox_aggregate").WithArgs(anyArgs(2)...).WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mockDB.ExpectExec("UPDATE catalog_change_position .* INSERT INTO catalog_change").
+		WithArgs(repository.OutboxKindBookUpdated, "book-1", []byte("change1"), "trace").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockDB.ExpectExec("UPDATE catalog_change_position .* INSERT INTO catalog_change").
+		WithArgs(repository.OutboxKindBookUpdated, "book-2", []byte("change2"), "trace").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockDB.ExpectCommit()
+
+	err = transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := outboxRepo.SendMessage(ctx, "key1", repository.OutboxKindBookUpdated,
+			"book-1", []byte("change1"), "trace"); err != nil {
+			return err
+		}
+
+		if _, err := mockDB.Exec(ctx, "SELECT 1 FROM book WHERE id = 'book-2' FOR UPDATE"); err != nil {
+			return err
+		}
+
+		return outboxRepo.SendMessage(ctx, "key2", repository.OutboxKindBookUpdated,
+			"book-2", []byte("change2"), "trace")
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestWithTxDropsCatalogChangesOnRollback(t *testing.T) {
+	t.Parallel()
+
+	mockDB, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	logger, _ := zap.NewProduction()
+	transactor := repository.NewTransactor(mockDB, logger)
+	outboxRepo := repository.NewOutbox(mockDB, logger)
+	ctx := t.Context()
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec("INSERT INTO outbox").WithArgs(anyArgs(5)...).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockDB.ExpectExec("INSERT INTO outbox_aggregate").WithArgs(anyArgs(2)...).WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mockDB.ExpectRollback()
+
+	err = transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := outboxRepo.SendMessage(ctx, "key1", repository.OutboxKindBook,
+			"book-1", []byte("change1"), "trace"); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("operation failed")
+	})
+	require.Error(t, err)
+
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func anyArgs(n int) []any {
+	args := make([]any, n)
+	for i := range args {
+		args[i] = pgxmock.AnyArg()
+	}
+
+	return args
+}
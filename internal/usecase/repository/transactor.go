@@ -53,11 +53,25 @@ func (t transactor) WithTx(
 		}
 	}()
 
+	// The catalog changes are appended by the transaction that started,
+	// after every other lock is taken.
+	pending, nested := ctx.Value(catalogChangesInjector{}).(*pendingCatalogChanges)
+	if !nested {
+		pending = &pendingCatalogChanges{}
+		ctxWithTx = context.WithValue(ctxWithTx, catalogChangesInjector{}, pending)
+	}
+
 	err = function(ctxWithTx)
 	if err != nil {
 		return fmt.Errorf("function execution error: %w", err)
 	}
 
+	if !nested {
+		if err = appendCatalogChanges(ctxWithTx, tx, pending.changes); err != nil {
+			return fmt.Errorf("can not append catalog changes, error: %w", err)
+		}
+	}
+
 	return nil
 }
 